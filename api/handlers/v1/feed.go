@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/feeds [post]
+// @Summary Create a calendar feed
+// @Description API to create the secret calendar feed URL of the current user, which calendar apps can subscribe to without a token.
+// @Description The URL is only returned once; creating a feed again revokes the previous URL.
+// @Tags FEED
+// @Accept  json
+// @Produce  json
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 201 {object} models.FeedModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) CreateFeed(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().CreateFeedToken(ctx, &todo_service.FeedTokenRequest{
+		UserId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create feed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.FeedModel{
+		Token:     res.Token,
+		URL:       feedURL(c, res.Token),
+		CreatedAt: timeValue(res.CreatedAt),
+	})
+}
+
+// @Router /v1/feeds [get]
+// @Summary Get the calendar feed
+// @Description API to check if the current user has a calendar feed, its URL is not returned again.
+// @Tags FEED
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.FeedModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetFeed(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().GetFeedToken(ctx, &todo_service.FeedTokenRequest{
+		UserId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to get feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.FeedModel{
+		CreatedAt: timeValue(res.CreatedAt),
+	})
+}
+
+// @Router /v1/feeds [delete]
+// @Summary Revoke the calendar feed
+// @Description API to revoke the calendar feed URL of the current user, subscribed calendars stop receiving todos.
+// @Tags FEED
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) RevokeFeed(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	_, err = h.grpcClient.TodoService().RevokeFeedToken(ctx, &todo_service.FeedTokenRequest{
+		UserId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to revoke feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      user.ID,
+		Message: "feed revoked",
+	})
+}
+
+// @Router /v1/feeds/{token}/todos.ics [get]
+// @Summary Calendar feed of a user's todos
+// @Description iCalendar feed of the todos of the feed's user, see GET /v1/todo/export.ics. The token in the path authorizes the request, no Authorization header is needed.
+// @Tags FEED
+// @Produce  text/calendar
+// @Param token path string true "feed token"
+// @Param component query string false "vtodo (default) for tasks or vevent for events at the due dates"
+// @Param list_id query string false "only export the todos of this list"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetFeedTodos(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	feed, err := h.grpcClient.TodoService().ResolveFeedToken(ctx, &todo_service.ResolveFeedTokenRequest{
+		Token: c.Param("token"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to get feed")
+		return
+	}
+
+	h.writeCalendar(ctx, c, feed.UserId, "inline")
+}
+
+// feedURL returns the absolute URL of a feed as the client reached us
+func feedURL(c *gin.Context, token string) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return scheme + "://" + c.Request.Host + "/v1/feeds/" + token + "/todos.ics"
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"strings"
+
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/filter"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	// todoFilter are the fields todos can be filtered by, name and status
+	// stand for task_name and task_status
+	todoFilter = filter.NewSchema(
+		filter.Field{Name: "task_name", Type: filter.String},
+		filter.Field{Name: "description", Type: filter.String},
+		filter.Field{Name: "task_status", Type: filter.Enum, Values: enumValues(todo_service.TaskStatus_value, "TASK_STATUS_")},
+		filter.Field{Name: "priority", Type: filter.Enum, Values: enumValues(todo_service.Priority_value, "PRIORITY_"), Ordered: true},
+		filter.Field{Name: "due_at", Type: filter.Time, Nullable: true},
+		filter.Field{Name: "created_at", Type: filter.Time},
+		filter.Field{Name: "updated_at", Type: filter.Time},
+		filter.Field{Name: "user_id", Type: filter.String, Nullable: true},
+		filter.Field{Name: "workspace_id", Type: filter.String, Nullable: true},
+		filter.Field{Name: "updated_by", Type: filter.String, Nullable: true},
+		filter.Field{Name: "parent_id", Type: filter.String, Nullable: true},
+		filter.Field{Name: "list_id", Type: filter.String, Nullable: true},
+		filter.Field{Name: "tag", Type: filter.Tag},
+	).
+		Alias("name", "task_name").
+		Alias("status", "task_status")
+
+	// todoListFilter are the fields lists can be filtered by
+	todoListFilter = filter.NewSchema(
+		filter.Field{Name: "name", Type: filter.String},
+		filter.Field{Name: "description", Type: filter.String},
+		filter.Field{Name: "user_id", Type: filter.String, Nullable: true},
+		filter.Field{Name: "archived_at", Type: filter.Time, Nullable: true},
+		filter.Field{Name: "created_at", Type: filter.Time},
+		filter.Field{Name: "updated_at", Type: filter.Time},
+	)
+)
+
+var filterOperators = map[filter.Op]todo_service.FilterCondition_Operator{
+	filter.Eq:    todo_service.FilterCondition_EQ,
+	filter.Ne:    todo_service.FilterCondition_NE,
+	filter.Lt:    todo_service.FilterCondition_LT,
+	filter.Le:    todo_service.FilterCondition_LE,
+	filter.Gt:    todo_service.FilterCondition_GT,
+	filter.Ge:    todo_service.FilterCondition_GE,
+	filter.In:    todo_service.FilterCondition_IN,
+	filter.NotIn: todo_service.FilterCondition_NOT_IN,
+}
+
+// ParseFilterQueryParam parses the filter query parameter, e.g.
+// `status in (todo, in_progress) and due_at < "2026-11-01"`, against the
+// fields of a resource
+func ParseFilterQueryParam(c *gin.Context, schema *filter.Schema) (*todo_service.FilterExpr, error) {
+	expr, err := schema.Parse(c.Query("filter"))
+	if err != nil || expr == nil {
+		return nil, err
+	}
+
+	return filterToProto(expr), nil
+}
+
+func filterToProto(expr filter.Expr) *todo_service.FilterExpr {
+	switch e := expr.(type) {
+	case filter.And:
+		return &todo_service.FilterExpr{
+			Expr: &todo_service.FilterExpr_And{And: filterOperands(e)},
+		}
+	case filter.Or:
+		return &todo_service.FilterExpr{
+			Expr: &todo_service.FilterExpr_Or{Or: filterOperands(e)},
+		}
+	case filter.Not:
+		return &todo_service.FilterExpr{
+			Expr: &todo_service.FilterExpr_Not{Not: filterToProto(e.X)},
+		}
+	}
+
+	c := expr.(*filter.Condition)
+	cond := &todo_service.FilterCondition{
+		Field:  c.Field,
+		Op:     filterOperators[c.Op],
+		Values: make([]*todo_service.FilterValue, 0, len(c.Values)),
+	}
+	for _, v := range c.Values {
+		value := &todo_service.FilterValue{}
+		switch v.Kind {
+		case filter.KindString:
+			value.Value = &todo_service.FilterValue_StringValue{StringValue: v.String}
+		case filter.KindInt:
+			value.Value = &todo_service.FilterValue_IntValue{IntValue: v.Int}
+		case filter.KindTime:
+			value.Value = &todo_service.FilterValue_TimeValue{TimeValue: timestamppb.New(v.Time)}
+		case filter.KindNull:
+			value.Value = &todo_service.FilterValue_NullValue{NullValue: true}
+		}
+		cond.Values = append(cond.Values, value)
+	}
+
+	return &todo_service.FilterExpr{
+		Expr: &todo_service.FilterExpr_Condition{Condition: cond},
+	}
+}
+
+func filterOperands(operands []filter.Expr) *todo_service.FilterOperands {
+	res := &todo_service.FilterOperands{
+		Operands: make([]*todo_service.FilterExpr, 0, len(operands)),
+	}
+	for _, operand := range operands {
+		res.Operands = append(res.Operands, filterToProto(operand))
+	}
+
+	return res
+}
+
+// enumValues turns the values of a proto enum, e.g. TASK_STATUS_IN_PROGRESS,
+// into names without prefix like in_progress, leaving out unspecified
+func enumValues(values map[string]int32, prefix string) map[string]int64 {
+	res := make(map[string]int64, len(values))
+	for name, n := range values {
+		name = strings.ToLower(strings.TrimPrefix(name, prefix))
+		if name != "unspecified" {
+			res[name] = int64(n)
+		}
+	}
+
+	return res
+}
@@ -9,21 +9,30 @@ import (
 
 	"github.com/abdukhashimov/go_gin_example/api/models"
 	"github.com/abdukhashimov/go_gin_example/config"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
 	"github.com/abdukhashimov/go_gin_example/pkg/grpc_client"
+	"github.com/abdukhashimov/go_gin_example/pkg/idempotency"
+	"github.com/abdukhashimov/go_gin_example/pkg/imaging"
 	"github.com/abdukhashimov/go_gin_example/pkg/jwt"
 	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/sortspec"
 	jwtg "github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	validate "github.com/go-ozzo/ozzo-validation/v3"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type handlerV1 struct {
-	log        logger.Logger
-	grpcClient *grpc_client.GrpcClient
-	cfg        *config.Config
+	log         logger.Logger
+	grpcClient  *grpc_client.GrpcClient
+	cfg         *config.Config
+	images      *imaging.Cache
+	idempotency idempotency.Store
 }
 
 //HandlerV1Config ...
@@ -31,6 +40,9 @@ type HandlerV1Config struct {
 	Logger     logger.Logger
 	GrpcClient *grpc_client.GrpcClient
 	Cfg        *config.Config
+	// IdempotencyStore keeps the responses replayed for Idempotency-Key,
+	// nil keeps them in memory
+	IdempotencyStore idempotency.Store
 }
 
 const (
@@ -58,6 +70,32 @@ const (
 	ErrorCodeWrongClub = "WRONG_CLUB"
 	//ErrorCodePasswordsNotEqual ...
 	ErrorCodePasswordsNotEqual = "PASSWORDS_NOT_EQUAL"
+	//ErrorCodeInvalidTransition is returned when the status workflow does not allow a status change
+	ErrorCodeInvalidTransition = "INVALID_TRANSITION"
+	//ErrorCodeHasSubtasks is returned when a todo with subtasks is deleted without recursive
+	ErrorCodeHasSubtasks = "HAS_SUBTASKS"
+	//ErrorCodeInboxList is returned when the inbox list is deleted or archived
+	ErrorCodeInboxList = "INBOX_LIST"
+	//ErrorCodeListArchived is returned when a todo is put into an archived list
+	ErrorCodeListArchived = "LIST_ARCHIVED"
+	//ErrorCodeConflict ...
+	ErrorCodeConflict = "CONFLICT"
+	//ErrorCodeTooLarge ...
+	ErrorCodeTooLarge = "TOO_LARGE"
+	//ErrorCodeFileTooLarge is returned when an attachment exceeds the size limit
+	ErrorCodeFileTooLarge = "FILE_TOO_LARGE"
+	//ErrorCodeQuotaExceeded is returned when an attachment exceeds what is left of the user's quota
+	ErrorCodeQuotaExceeded = "QUOTA_EXCEEDED"
+	//ErrorCodeUnsupportedImage is returned when an image to resize is not a JPEG, PNG or GIF
+	ErrorCodeUnsupportedImage = "UNSUPPORTED_IMAGE"
+	//ErrorCodeImageTooLarge is returned when an image has too many pixels to resize
+	ErrorCodeImageTooLarge = "IMAGE_TOO_LARGE"
+	//ErrorCodeVersionConflict is returned when the If-Match of a write is not the current version
+	ErrorCodeVersionConflict = "VERSION_CONFLICT"
+	//ErrorCodeIdempotencyKeyReused is returned when an Idempotency-Key is sent with another request
+	ErrorCodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+	//ErrorCodeTimeout is returned when the deadline of a request was reached, for batches on the items not started before it
+	ErrorCodeTimeout = "TIMEOUT"
 )
 
 var (
@@ -66,10 +104,17 @@ var (
 
 //New ...
 func New(c *HandlerV1Config) *handlerV1 {
+	store := c.IdempotencyStore
+	if store == nil {
+		store = idempotency.NewMemoryStore()
+	}
+
 	return &handlerV1{
-		log:        c.Logger,
-		grpcClient: c.GrpcClient,
-		cfg:        c.Cfg,
+		log:         c.Logger,
+		grpcClient:  c.GrpcClient,
+		cfg:         c.Cfg,
+		images:      imaging.NewCache(c.Cfg.ImageCacheDir, c.Cfg.ImageCacheMaxSize),
+		idempotency: store,
 	}
 }
 
@@ -104,12 +149,96 @@ func (h *handlerV1) handleBadRequest(c *gin.Context, err error, message string)
 
 func (h *handlerV1) handleInternalServerError(c *gin.Context, err error, message string) {
 	h.log.Error(message, logger.Error(err))
-	c.JSON(http.StatusBadRequest, models.ResponseError{
+	c.JSON(http.StatusInternalServerError, models.ResponseError{
 		Message: message,
 		Reason:  err.Error(),
 	})
 }
 
+// handleInvalidQuery reports an invalid query parameter, the message of
+// err says what is wrong with it
+func (h *handlerV1) handleInvalidQuery(c *gin.Context, err error) {
+	h.log.Error("invalid query", logger.Error(err))
+	c.JSON(http.StatusBadRequest, models.ResponseError{
+		Message: err.Error(),
+		Reason:  ErrorBadRequest,
+	})
+}
+
+// handleGrpcError maps an error returned by a grpc service to the matching
+// http status code and writes it to the response
+func (h *handlerV1) handleGrpcError(c *gin.Context, err error, message string) {
+	st, ok := status.FromError(err)
+	if !ok {
+		h.handleInternalServerError(c, err, message)
+		return
+	}
+
+	httpStatus, reason, ok := grpcCodeToHTTP(st.Code())
+	if !ok {
+		h.handleInternalServerError(c, err, message)
+		return
+	}
+
+	// the service may name a more specific reason in the error details, a
+	// version conflict carries the current todo
+	var current *todo_service.TodoModel
+	for _, detail := range st.Details() {
+		switch d := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if d.Reason != "" {
+				reason = d.Reason
+			}
+		case *todo_service.TodoModel:
+			current = d
+		}
+	}
+
+	h.log.Error(message, logger.Error(err))
+	if current != nil {
+		c.Header("ETag", todoETag(current))
+		c.JSON(httpStatus, models.TodoConflictModel{
+			Message: st.Message(),
+			Reason:  reason,
+			Current: todoToModel(current),
+		})
+		return
+	}
+	c.JSON(httpStatus, models.ResponseError{
+		Message: st.Message(),
+		Reason:  reason,
+	})
+}
+
+// grpcCodeToHTTP returns the http status and error code for a grpc code,
+// ok is false for codes which should be reported as internal errors
+func grpcCodeToHTTP(code codes.Code) (httpStatus int, reason string, ok bool) {
+	switch code {
+	case codes.OK:
+		return http.StatusOK, "", true
+	case codes.NotFound:
+		return http.StatusNotFound, ErrorCodeNotFound, true
+	case codes.InvalidArgument:
+		return http.StatusBadRequest, ErrorBadRequest, true
+	case codes.AlreadyExists:
+		return http.StatusConflict, ErrorCodeAlreadyExists, true
+	case codes.PermissionDenied:
+		return http.StatusForbidden, ErrorCodeForbidden, true
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, ErrorCodeUnauthorized, true
+	case codes.FailedPrecondition:
+		return http.StatusConflict, ErrorCodeConflict, true
+	case codes.ResourceExhausted:
+		return http.StatusRequestEntityTooLarge, ErrorCodeTooLarge, true
+	case codes.Aborted:
+		return http.StatusPreconditionFailed, ErrorCodeVersionConflict, true
+	case codes.DeadlineExceeded, codes.Canceled:
+		return http.StatusGatewayTimeout, ErrorCodeTimeout, true
+	default:
+		return http.StatusInternalServerError, ErrorCodeInternal, false
+	}
+}
+
 func ValidatePhoneNumber(phoneNumber string) error {
 	if phoneNumber == "" {
 		return errors.New("phone_number is blank")
@@ -186,49 +315,23 @@ func ParseSearchQueryParam(c *gin.Context) (string, error) {
 	return s, nil
 }
 
-//ParseActiveQueryParam ...
-func ParseActiveQueryParam(c *gin.Context) (bool, error) {
-	a, err := strconv.ParseBool(c.DefaultQuery("active", "false"))
-	if err != nil {
-		return false, err
-	}
-	return a, nil
-}
-
-//ParseInactiveQueryParam ...
-func ParseInactiveQueryParam(c *gin.Context) (bool, error) {
-	a, err := strconv.ParseBool(c.DefaultQuery("inactive", "false"))
-	if err != nil {
-		return false, err
-	}
-	return a, nil
-}
-
-//ParseRecommendedQueryParam ...
-func ParseRecommendedQueryParam(c *gin.Context) (bool, error) {
-	a, err := strconv.ParseBool(c.DefaultQuery("recommended", "false"))
+// ParseSortQueryParam parses the sort query parameter, e.g.
+// "priority|desc,due_at|asc", against the sortable keys of a resource
+func ParseSortQueryParam(c *gin.Context, schema *sortspec.Schema) ([]*todo_service.SortField, error) {
+	keys, err := schema.Parse(c.Query("sort"))
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return a, nil
-}
 
-//ParseOnlyRelatedQueryParam ...
-func ParseOnlyRelatedQueryParam(c *gin.Context) (bool, error) {
-	a, err := strconv.ParseBool(c.DefaultQuery("onlyRelatedProducts", "false"))
-	if err != nil {
-		return false, err
+	fields := make([]*todo_service.SortField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, &todo_service.SortField{
+			Field: key.Field,
+			Desc:  key.Desc,
+		})
 	}
-	return a, nil
-}
 
-//ParsePopularQueryParam ...
-func ParsePopularQueryParam(c *gin.Context) (bool, error) {
-	a, err := strconv.ParseBool(c.DefaultQuery("popular", "false"))
-	if err != nil {
-		return false, err
-	}
-	return a, nil
+	return fields, nil
 }
 
 //FloatToString ...
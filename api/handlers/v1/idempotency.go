@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/pkg/idempotency"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotentReplayedHeader marks responses replayed for a retry
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLen     = 255
+	// maxBufferedBody is how much of a body is kept in memory while it is
+	// hashed, larger ones like uploads are spooled to a temporary file
+	maxBufferedBody = 1 << 20
+)
+
+// Idempotent runs a request with an Idempotency-Key header once per user and
+// key, retries get the response of the first request replayed. A retry
+// waits while the first request is still running. The key can't be reused
+// for a request with another method, path or body. Server errors aren't
+// stored, so the request can be retried with the same key. Keys need a
+// token, anonymous callers would share them.
+func (h *handlerV1) Idempotent(c *gin.Context) {
+	key := c.GetHeader(idempotencyKeyHeader)
+	if key == "" {
+		c.Next()
+		return
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		h.handleBadRequest(c, errors.New("Idempotency-Key is longer than 255 characters"), "invalid Idempotency-Key")
+		c.Abort()
+		return
+	}
+
+	user, err := userInfo(h, c)
+	if err != nil {
+		c.Abort()
+		return
+	}
+
+	hash, body, err := hashRequest(c.Request)
+	if err != nil {
+		h.handleBadRequest(c, err, "failed to read body")
+		c.Abort()
+		return
+	}
+	defer body.Close()
+	c.Request.Body = body
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	// keys are scoped to the user, a request is identified by its hash
+	storeKey := user.ID + "\x00" + key
+	res, err := h.idempotency.Begin(ctx, storeKey, hash)
+	switch {
+	case errors.Is(err, idempotency.ErrMismatch):
+		h.log.Error("idempotency key reused", logger.Error(err))
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, models.ResponseError{
+			Message: "Idempotency-Key was already used for another request",
+			Reason:  ErrorCodeIdempotencyKeyReused,
+		})
+		return
+	case errors.Is(err, idempotency.ErrInFlight):
+		h.log.Error("idempotency key in flight", logger.Error(err))
+		c.AbortWithStatusJSON(http.StatusConflict, models.ResponseError{
+			Message: "a request with this Idempotency-Key is still in progress",
+			Reason:  ErrorCodeConflict,
+		})
+		return
+	case err != nil:
+		h.handleInternalServerError(c, err, "failed to check Idempotency-Key")
+		c.Abort()
+		return
+	case res != nil:
+		for name, values := range res.Header {
+			c.Writer.Header()[name] = values
+		}
+		c.Header(idempotentReplayedHeader, "true")
+		c.Status(res.Status)
+		c.Writer.Write(res.Body)
+		c.Abort()
+		return
+	}
+
+	// the key is released if a handler panics
+	finished := false
+	defer func() {
+		if !finished {
+			h.idempotency.Abort(storeKey)
+		}
+	}()
+
+	w := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = w
+	c.Next()
+
+	finished = true
+	if w.Status() >= http.StatusInternalServerError {
+		h.idempotency.Abort(storeKey)
+		return
+	}
+
+	header := w.Header().Clone()
+	header.Del("Content-Length")
+	err = h.idempotency.Finish(storeKey, &idempotency.Response{
+		Status: w.Status(),
+		Header: header,
+		Body:   w.body.Bytes(),
+	}, time.Duration(h.cfg.IdempotencyKeyTTL)*time.Second)
+	if err != nil {
+		h.log.Error("failed to store idempotent response", logger.Error(err))
+	}
+}
+
+// hashRequest identifies a request by its method, path and body. The body
+// is read for it, the returned one reads it again.
+func hashRequest(r *http.Request) (string, io.ReadCloser, error) {
+	sum := sha256.New()
+	sum.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+
+	var buf bytes.Buffer
+	_, err := io.CopyN(&buf, io.TeeReader(r.Body, sum), maxBufferedBody+1)
+	if err == io.EOF {
+		return hex.EncodeToString(sum.Sum(nil)), ioutil.NopCloser(&buf), nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	file, err := ioutil.TempFile("", "idempotent-body-")
+	if err != nil {
+		return "", nil, err
+	}
+	body := &spooledBody{file}
+	if _, err = buf.WriteTo(file); err == nil {
+		_, err = io.Copy(file, io.TeeReader(r.Body, sum))
+	}
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		body.Close()
+		return "", nil, err
+	}
+
+	return hex.EncodeToString(sum.Sum(nil)), body, nil
+}
+
+// spooledBody is a request body in a temporary file, which is removed on
+// close
+type spooledBody struct {
+	*os.File
+}
+
+func (b *spooledBody) Close() error {
+	err := b.File.Close()
+	if rmErr := os.Remove(b.Name()); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// recordingWriter keeps a copy of the body written to the response
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
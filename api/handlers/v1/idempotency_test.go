@@ -0,0 +1,247 @@
+package v1
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	"github.com/abdukhashimov/go_gin_example/pkg/idempotency"
+	"github.com/abdukhashimov/go_gin_example/pkg/jwt"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// countingHandler answers requests with the number of calls and the size
+// of the body, with status while it is set, 201 otherwise
+type countingHandler struct {
+	mu     sync.Mutex
+	calls  int
+	status int
+	// block, when set, holds requests until it is closed
+	block chan struct{}
+}
+
+func (c *countingHandler) handle(ctx *gin.Context) {
+	body, err := ioutil.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	c.calls++
+	calls, status, block := c.calls, c.status, c.block
+	c.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	ctx.Header("X-Call", strconv.Itoa(calls))
+	ctx.String(status, "call %d of %d bytes", calls, len(body))
+}
+
+func (c *countingHandler) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls
+}
+
+func newIdempotentRouter(t *testing.T) (*gin.Engine, *countingHandler) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	h := &handlerV1{
+		log:         logger.New(logger.LevelError, "test"),
+		cfg:         &config.Config{CtxTimeout: 50, IdempotencyKeyTTL: 60},
+		idempotency: idempotency.NewMemoryStore(),
+	}
+	handler := &countingHandler{}
+
+	router := gin.New()
+	router.POST("/v1/todo/:id/restore", h.Idempotent, handler.handle)
+
+	return router, handler
+}
+
+func token(t *testing.T, userID string) string {
+	t.Helper()
+
+	token, err := jwt.GenerateJWT(userID, "user", signingKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// post sends body to path with an Idempotency-Key of the user, no key
+// when key is empty
+func post(router http.Handler, path, userToken, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if userToken != "" {
+		req.Header.Set("Authorization", userToken)
+	}
+	if key != "" {
+		req.Header.Set(idempotencyKeyHeader, key)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+	user := token(t, "user-1")
+
+	first := post(router, "/v1/todo/1/restore", user, "key-1", `{"a":1}`)
+	retry := post(router, "/v1/todo/1/restore", user, "key-1", `{"a":1}`)
+
+	if handler.count() != 1 {
+		t.Fatalf("handler ran %d times, want once", handler.count())
+	}
+	if retry.Code != first.Code || retry.Body.String() != first.Body.String() || retry.Header().Get("X-Call") != "1" {
+		t.Errorf("retry got %d %q, want the first response %d %q", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if first.Header().Get(idempotentReplayedHeader) != "" || retry.Header().Get(idempotentReplayedHeader) != "true" {
+		t.Errorf("%s = %q and %q, want it on the retry only", idempotentReplayedHeader,
+			first.Header().Get(idempotentReplayedHeader), retry.Header().Get(idempotentReplayedHeader))
+	}
+
+	// keys are scoped to users, and requests without one always run
+	post(router, "/v1/todo/1/restore", token(t, "user-2"), "key-1", `{"a":1}`)
+	post(router, "/v1/todo/1/restore", user, "", `{"a":1}`)
+	post(router, "/v1/todo/1/restore", user, "", `{"a":1}`)
+	if handler.count() != 4 {
+		t.Errorf("handler ran %d times, want 4", handler.count())
+	}
+}
+
+func TestIdempotentMismatch(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+	user := token(t, "user-1")
+
+	post(router, "/v1/todo/1/restore", user, "key-1", `{"a":1}`)
+	tests := []struct {
+		name, path, body string
+	}{
+		{"body", "/v1/todo/1/restore", `{"a":2}`},
+		{"path", "/v1/todo/2/restore", `{"a":1}`},
+		{"query", "/v1/todo/1/restore?recursive=true", `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := post(router, tt.path, user, "key-1", tt.body)
+			if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), ErrorCodeIdempotencyKeyReused) {
+				t.Errorf("got %d %s, want 422 %s", w.Code, w.Body, ErrorCodeIdempotencyKeyReused)
+			}
+		})
+	}
+	if handler.count() != 1 {
+		t.Errorf("handler ran %d times, want once", handler.count())
+	}
+}
+
+func TestIdempotentInFlight(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+	user := token(t, "user-1")
+	handler.block = make(chan struct{})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		done <- post(router, "/v1/todo/1/restore", user, "key-1", `{}`)
+	}()
+	// wait for the first request to hold the key
+	for handler.count() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the retry gives up waiting after CtxTimeout
+	w := post(router, "/v1/todo/1/restore", user, "key-1", `{}`)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), ErrorCodeConflict) {
+		t.Errorf("got %d %s, want 409 %s", w.Code, w.Body, ErrorCodeConflict)
+	}
+
+	close(handler.block)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first request got %d, want 201", first.Code)
+	}
+	if handler.count() != 1 {
+		t.Errorf("handler ran %d times, want once", handler.count())
+	}
+}
+
+func TestIdempotentServerErrorNotStored(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+	user := token(t, "user-1")
+
+	handler.status = http.StatusBadGateway
+	if w := post(router, "/v1/todo/1/restore", user, "key-1", `{}`); w.Code != http.StatusBadGateway {
+		t.Fatalf("got %d, want 502", w.Code)
+	}
+
+	// the retry runs again, its response is stored
+	handler.status = 0
+	for i := 0; i < 2; i++ {
+		w := post(router, "/v1/todo/1/restore", user, "key-1", `{}`)
+		if w.Code != http.StatusCreated || w.Header().Get("X-Call") != "2" {
+			t.Errorf("retry %d got %d of call %s, want 201 of call 2", i, w.Code, w.Header().Get("X-Call"))
+		}
+	}
+
+	// client errors are stored like successes
+	handler.status = http.StatusNotFound
+	post(router, "/v1/todo/1/restore", user, "key-2", `{}`)
+	if w := post(router, "/v1/todo/1/restore", user, "key-2", `{}`); w.Code != http.StatusNotFound || handler.count() != 3 {
+		t.Errorf("retry got %d after %d calls, want the stored 404 after 3", w.Code, handler.count())
+	}
+}
+
+func TestIdempotentNeedsToken(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+
+	if w := post(router, "/v1/todo/1/restore", "", "key-1", `{}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want 401", w.Code)
+	}
+	if w := post(router, "/v1/todo/1/restore", token(t, "user-1"), strings.Repeat("k", maxIdempotencyKeyLen+1), `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("long key got %d, want 400", w.Code)
+	}
+	if handler.count() != 0 {
+		t.Errorf("handler ran %d times, want never", handler.count())
+	}
+}
+
+func TestIdempotentLargeBody(t *testing.T) {
+	router, handler := newIdempotentRouter(t)
+	user := token(t, "user-1")
+
+	// spooled to a file, the handler still reads all of it
+	body := strings.Repeat("x", maxBufferedBody+10)
+	first := post(router, "/v1/todo/1/restore", user, "key-1", body)
+	if want := "call 1 of " + strconv.Itoa(len(body)) + " bytes"; first.Body.String() != want {
+		t.Fatalf("got %q, want %q", first.Body, want)
+	}
+	if retry := post(router, "/v1/todo/1/restore", user, "key-1", body); !bytes.Equal(retry.Body.Bytes(), first.Body.Bytes()) {
+		t.Errorf("retry got %q, want the first response", retry.Body)
+	}
+
+	// a change past the buffered part is still noticed
+	changed := body[:len(body)-1] + "y"
+	if w := post(router, "/v1/todo/1/restore", user, "key-1", changed); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("changed body got %d, want 422", w.Code)
+	}
+	if handler.count() != 1 {
+		t.Errorf("handler ran %d times, want once", handler.count())
+	}
+}
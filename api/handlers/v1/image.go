@@ -0,0 +1,174 @@
+package v1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/pkg/imaging"
+	"github.com/gin-gonic/gin"
+)
+
+// maxImagePixels keeps huge images from being decoded for resizing, a
+// decoded image takes 4 bytes per pixel
+const maxImagePixels = 40 << 20
+
+var errImageTooLarge = errors.New("image has too many pixels to resize")
+
+// @Router /images/{name} [get]
+// @Summary Get an image
+// @Description API to get an image, resized when w or h is given. Images are only scaled down. With both w and h,
+// @Description fit says how the image fits the box: contain keeps it inside, cover crops it to fill the box,
+// @Description fill stretches it. Resized variants are cached and only JPEG, PNG and GIF images can be resized.
+// @Description w and h are rounded up to the next of 16, 24, 32, 48, 64, 96, 128, 160, 192, 256, 320, 384, 480, 512,
+// @Description 640, 768, 960, 1024, 1280, 1536, 1920, 2048, 2560, 3072, 3840 and 4096.
+// @Tags IMAGE
+// @Produce  image/png
+// @Produce  image/jpeg
+// @Produce  image/gif
+// @Param name path string true "image path"
+// @Param w query integer false "width"
+// @Param h query integer false "height"
+// @Param fit query string false "how the image fits when w and h are given" Enums(contain, cover, fill)
+// @Success 200 {file} file
+// @Success 304 {string} string "not modified"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 415 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetImage(c *gin.Context) {
+	// cleaning a rooted path drops any .. which would leave ImagesDir
+	name := path.Clean("/" + c.Param("name"))
+	file := filepath.Join(h.cfg.ImagesDir, filepath.FromSlash(name))
+
+	info, err := os.Stat(file)
+	if err != nil || info.IsDir() {
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			Message: "image not found",
+			Reason:  ErrorCodeNotFound,
+		})
+		return
+	}
+
+	opts, err := h.imageOptions(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid image options")
+		return
+	}
+
+	// a new version of the file gets new etags and cache keys
+	version := strconv.FormatInt(info.ModTime().UnixNano(), 16) + "-" + strconv.FormatInt(info.Size(), 16)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", h.cfg.ImageCacheMaxAge))
+
+	if opts.Width == 0 && opts.Height == 0 {
+		original, err := os.Open(file)
+		if err != nil {
+			h.handleInternalServerError(c, err, "failed to open image")
+			return
+		}
+		defer original.Close()
+
+		serveImage(c, original, name, strconv.Quote(version), info.ModTime())
+		return
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%dx%d\x00%s", name, version, opts.Width, opts.Height, opts.Fit)))
+	key := hex.EncodeToString(sum[:])
+
+	variant, err := h.images.Open(key, func(w io.Writer) error {
+		return resizeImage(file, w, opts)
+	})
+	switch {
+	case errors.Is(err, imaging.ErrUnsupportedFormat):
+		c.JSON(http.StatusUnsupportedMediaType, models.ResponseError{
+			Message: "only JPEG, PNG and GIF images can be resized",
+			Reason:  ErrorCodeUnsupportedImage,
+		})
+		return
+	case errors.Is(err, errImageTooLarge):
+		c.JSON(http.StatusUnprocessableEntity, models.ResponseError{
+			Message: err.Error(),
+			Reason:  ErrorCodeImageTooLarge,
+		})
+		return
+	case err != nil:
+		h.handleInternalServerError(c, err, "failed to resize image")
+		return
+	}
+
+	defer variant.Close()
+
+	serveImage(c, variant, name, strconv.Quote(key), info.ModTime())
+}
+
+// imageOptions parses the w, h and fit query params, w and h are rounded
+// up to the sizes variants are made in
+func (h *handlerV1) imageOptions(c *gin.Context) (imaging.Options, error) {
+	var (
+		opts imaging.Options
+		err  error
+	)
+
+	for param, dst := range map[string]*int{"w": &opts.Width, "h": &opts.Height} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		*dst, err = strconv.Atoi(value)
+		if err != nil || *dst < 1 || *dst > h.cfg.ImageMaxDimension {
+			return opts, fmt.Errorf("%s must be between 1 and %d", param, h.cfg.ImageMaxDimension)
+		}
+		if *dst = imaging.SnapSize(*dst); *dst > h.cfg.ImageMaxDimension {
+			*dst = h.cfg.ImageMaxDimension
+		}
+	}
+
+	opts.Fit, err = imaging.ParseFit(c.Query("fit"))
+
+	return opts, err
+}
+
+// serveImage sends content with an etag, name only sets the content type.
+// Conditional requests are answered with 304 by http.ServeContent.
+func serveImage(c *gin.Context, content io.ReadSeeker, name, etag string, modTime time.Time) {
+	c.Header("ETag", etag)
+	http.ServeContent(c.Writer, c.Request, name, modTime, content)
+}
+
+// resizeImage writes the image in file resized as opts says, in the format
+// of the original
+func resizeImage(file string, w io.Writer, opts imaging.Options) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cfg, _, err := imaging.DecodeConfig(f)
+	if err != nil {
+		return err
+	}
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return errImageTooLarge
+	}
+
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, format, err := imaging.Decode(f)
+	if err != nil {
+		return err
+	}
+
+	return imaging.Encode(w, imaging.Resize(img, opts), format)
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageDeprecatedAt is when the page query parameter was deprecated in
+// favour of cursor, it is sent as the Deprecation header (RFC 9745)
+var pageDeprecatedAt = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+
+// ParseCursorQueryParam returns the cursor query parameter, the next_cursor
+// of the previous page. It can't be combined with page.
+func ParseCursorQueryParam(c *gin.Context) (string, error) {
+	cursor := c.Query("cursor")
+	if cursor != "" && c.Query("page") != "" {
+		return "", errors.New("cursor and page can't be combined")
+	}
+
+	return cursor, nil
+}
+
+// ParseCountQueryParam reports if the client asked for the number of items
+// of a listing with count=true. Counting takes another query, listings
+// leave it out otherwise.
+func ParseCountQueryParam(c *gin.Context) (bool, error) {
+	value := c.Query("count")
+	if value == "" {
+		return false, nil
+	}
+
+	withCount, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("count must be true or false")
+	}
+
+	return withCount, nil
+}
+
+// totalCount returns count for a listing asked to count, nil otherwise
+func totalCount(withCount bool, count int64) *int64 {
+	if !withCount {
+		return nil
+	}
+
+	return &count
+}
+
+// setPageHeaders sets a Link header (RFC 8288) with the first and, unless
+// this is the last page, the next page of a listing, and X-Total-Count if
+// the items were counted. Requests using page get a Deprecation header.
+func setPageHeaders(c *gin.Context, count *int64, nextCursor string) {
+	if count != nil {
+		c.Header("X-Total-Count", strconv.FormatInt(*count, 10))
+	}
+
+	path := c.Request.URL.Path
+	query := c.Request.URL.Query()
+	query.Del("page")
+	query.Del("cursor")
+
+	links := []string{pageLink(path, query, "first")}
+	if nextCursor != "" {
+		query.Set("cursor", nextCursor)
+		links = append(links, pageLink(path, query, "next"))
+	}
+	c.Header("Link", strings.Join(links, ", "))
+
+	if c.Query("page") != "" {
+		c.Header("Deprecation", "@"+strconv.FormatInt(pageDeprecatedAt.Unix(), 10))
+	}
+}
+
+func pageLink(path string, query url.Values, rel string) string {
+	u := url.URL{Path: path, RawQuery: query.Encode()}
+	return "<" + u.String() + `>; rel="` + rel + `"`
+}
@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listPage answers like a listing of 42 items with a next page
+func listPage(c *gin.Context) {
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		c.String(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	setPageHeaders(c, totalCount(withCount, 42), "next")
+	c.Status(http.StatusOK)
+}
+
+func TestPageHeadersCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/v1/todo", listPage)
+
+	tests := []struct {
+		query string
+		code  int
+		count string
+	}{
+		{"", http.StatusOK, ""},
+		{"?count=false", http.StatusOK, ""},
+		{"?count=true", http.StatusOK, "42"},
+		{"?count=1&limit=2", http.StatusOK, "42"},
+		{"?count=yes", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/todo"+tt.query, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("got %d, want %d", w.Code, tt.code)
+			}
+			if got := w.Header().Get("X-Total-Count"); got != tt.count {
+				t.Errorf("X-Total-Count = %q, want %q", got, tt.count)
+			}
+			if w.Code == http.StatusOK && w.Header().Get("Link") == "" {
+				t.Error("no Link header")
+			}
+		})
+	}
+}
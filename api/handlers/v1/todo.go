@@ -1,20 +1,73 @@
 package v1
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // @Router /v1/todo [post]
-// @Summary Get List of Todo
-// @Description API to retreive list of todo
+// @Summary Create a Todo
+// @Description API to create a new todo
 // @Tags TODO
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} models.AllTodoModel
-// @Failure 404 {object} models.ResponseError
+// @Param todo body models.CreateTodoModel true "todo"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 201 {object} models.SingleTodoModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
 // @Failure 500 {object} models.ResponseError
 func (h *handlerV1) CreateNewTodo(c *gin.Context) {
+	var (
+		body   models.CreateTodoModel
+		userID string
+	)
+
+	// todos created with a token belong to its user, so they show up in
+	// that user's todo stream
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid todo")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	req := todoFromModel(body)
+	req.UserId = userID
 
+	res, err := h.grpcClient.TodoService().CreateTodo(ctx, req)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create todo")
+		return
+	}
+
+	c.Header("ETag", todoETag(res))
+	c.JSON(http.StatusCreated, todoToModel(res))
 }
 
 // @Router /v1/todo [get]
@@ -23,11 +76,89 @@ func (h *handlerV1) CreateNewTodo(c *gin.Context) {
 // @Tags TODO
 // @Accept  json
 // @Produce  json
+// @Param page query integer false "page, deprecated in favour of cursor"
+// @Param cursor query string false "next_cursor of the previous page, can't be combined with page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Param search query string false "search"
+// @Param sort query string false "comma separated field|direction keys, fields are task_name, task_status, priority, due_at, created_at and updated_at, ties are ordered by id" example(priority|desc,due_at|asc)
+// @Param filter query string false "filter expression comparing task_name, description, task_status, priority, due_at, created_at, updated_at, user_id, workspace_id, updated_by, parent_id, list_id or tag with =, !=, <, <=, >, >=, in or not in, combined with and, or, not and parentheses; times are RFC 3339 or dates, null matches unset fields; tag = ops matches todos with #ops in their title or description" example(status in (todo, in_progress) and due_at < "2026-11-01" and tag = ops)
+// @Param parent_id query string false "only the direct subtasks of this todo"
+// @Param list_id query string false "only the todos of this list"
 // @Success 200 {object} models.AllTodoModel
-// @Failure 404 {object} models.ResponseError
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of matching items, only with count=true"
+// @Failure 400 {object} models.ResponseError
 // @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetAllTodo(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid page")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	search, _ := ParseSearchQueryParam(c)
+
+	cursor, err := ParseCursorQueryParam(c)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	sort, err := ParseSortQueryParam(c, models.TodoSort)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	filter, err := ParseFilterQueryParam(c, todoFilter)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
 
+	res, err := h.grpcClient.TodoService().ListTodos(ctx, &todo_service.ListTodosRequest{
+		Page:      int64(page),
+		Limit:     int64(limit),
+		Search:    search,
+		Sort:      sort,
+		Filter:    filter,
+		ParentId:  c.Query("parent_id"),
+		ListId:    c.Query("list_id"),
+		Cursor:    cursor,
+		WithCount: withCount,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list todos")
+		return
+	}
+
+	todos := models.AllTodoModel{
+		Todos:      make([]models.SingleTodoModel, 0, len(res.Todos)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, t := range res.Todos {
+		todos.Todos = append(todos.Todos, todoToModel(t))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, todos)
 }
 
 // @Router /v1/todo/{id} [get]
@@ -36,35 +167,353 @@ func (h *handlerV1) GetAllTodo(c *gin.Context) {
 // @Tags TODO
 // @Accept  json
 // @Produce  json
+// @Param id path string true "todo id"
 // @Success 200 {object} models.SingleTodoModel
 // @Failure 404 {object} models.ResponseError
 // @Failure 500 {object} models.ResponseError
 func (h *handlerV1) GetTodo(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().GetTodo(ctx, &todo_service.GetTodoRequest{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to get todo")
+		return
+	}
 
+	c.Header("ETag", todoETag(res))
+	c.JSON(http.StatusOK, todoToModel(res))
 }
 
 // @Router /v1/todo/{id} [put]
-// @Summary Get a Todo
-// @Description API to retreive a single todo
+// @Summary Update a Todo
+// @Description API to update a single todo
 // @Tags TODO
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} models.Response
+// @Param id path string true "todo id"
+// @Param todo body models.CreateTodoModel true "todo"
+// @Param If-Match header string false "ETags of the todo the update is based on, comma separated, * for any version; weak ETags never match"
+// @Success 200 {object} models.SingleTodoModel
+// @Header 200 {string} ETag "version of the todo"
+// @Failure 400 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
 // @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 412 {object} models.TodoConflictModel
 // @Failure 500 {object} models.ResponseError
 func (h *handlerV1) UpdateTodo(c *gin.Context) {
+	var (
+		body   models.CreateTodoModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
 
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid todo")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	version, ok := h.ifMatchVersion(ctx, c, c.Param("id"))
+	if !ok {
+		return
+	}
+
+	req := todoFromModel(body)
+	req.Id = c.Param("id")
+	req.UpdatedBy = userID
+	req.Version = version
+
+	res, err := h.grpcClient.TodoService().UpdateTodo(ctx, req)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to update todo")
+		return
+	}
+
+	c.Header("ETag", todoETag(res))
+	c.JSON(http.StatusOK, todoToModel(res))
 }
 
 // @Router /v1/todo/{id} [delete]
-// @Summary Get a Todo
-// @Description API to retreive a single todo
+// @Summary Delete a Todo
+// @Description API to move a single todo to the trash, see GET /v1/trash. A todo with subtasks is only deleted with recursive, which deletes its subtasks too.
 // @Tags TODO
 // @Accept  json
 // @Produce  json
+// @Param id path string true "todo id"
+// @Param recursive query boolean false "delete subtasks too"
+// @Param If-Match header string false "ETags of the todo the delete is based on, comma separated, * for any version; weak ETags never match"
 // @Success 200 {object} models.Response
+// @Failure 400 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
 // @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 412 {object} models.TodoConflictModel
 // @Failure 500 {object} models.ResponseError
 func (h *handlerV1) DeleteTodo(c *gin.Context) {
+	var userID string
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	id := c.Param("id")
+
+	recursive, err := strconv.ParseBool(c.DefaultQuery("recursive", "false"))
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid recursive")
+		return
+	}
+
+	version, ok := h.ifMatchVersion(ctx, c, id)
+	if !ok {
+		return
+	}
+
+	_, err = h.grpcClient.TodoService().DeleteTodo(ctx, &todo_service.DeleteTodoRequest{
+		Id:        id,
+		Recursive: recursive,
+		ActorId:   userID,
+		Version:   version,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to delete todo")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "todo moved to trash",
+	})
+}
+
+func todoToModel(t *todo_service.TodoModel) models.SingleTodoModel {
+	todo := models.SingleTodoModel{
+		ID:          t.Id,
+		TaskName:    t.TaskName,
+		Description: t.Description,
+		TaskStatus:  taskStatusName(t.TaskStatus),
+		Priority:    priorityName(t.Priority),
+		DueAt:       timeValue(t.DueAt),
+		UserID:      t.UserId,
+		WorkspaceID: t.WorkspaceId,
+		CreatedAt:   timeValue(t.CreatedAt),
+		UpdatedAt:   timeValue(t.UpdatedAt),
+		UpdatedBy:   t.UpdatedBy,
+		ParentID:    t.ParentId,
+		ListID:      t.ListId,
+		DeletedAt:   timeValue(t.DeletedAt),
+		Version:     t.Version,
+	}
+	if t.Progress != nil {
+		todo.Progress = &models.SubtaskProgressModel{
+			Done:  t.Progress.Done,
+			Total: t.Progress.Total,
+		}
+	}
+	if t.Recurrence != nil {
+		todo.Recurrence = &models.RecurrenceModel{
+			Rule:     t.Recurrence.Rule,
+			Timezone: t.Recurrence.Timezone,
+			Start:    timeValue(t.Recurrence.Start),
+			NextID:   t.Recurrence.NextId,
+		}
+	}
+	for _, before := range t.RemindBefore {
+		todo.Reminders = append(todo.Reminders, models.FormatReminder(before))
+	}
+
+	return todo
+}
+
+// todoFromModel expects a validated body, unknown enum names become the
+// zero value
+func todoFromModel(m models.CreateTodoModel) *todo_service.TodoModel {
+	todo := &todo_service.TodoModel{
+		TaskName:    m.TaskName,
+		Description: m.Description,
+		TaskStatus:  parseTaskStatus(m.TaskStatus),
+		Priority:    parsePriority(m.Priority),
+		WorkspaceId: m.WorkspaceID,
+		ParentId:    m.ParentID,
+		ListId:      m.ListID,
+	}
+	if m.DueAt != nil {
+		todo.DueAt = timestamppb.New(*m.DueAt)
+	}
+	if m.Recurrence != nil {
+		todo.Recurrence = &todo_service.Recurrence{
+			Rule:     m.Recurrence.Rule,
+			Timezone: m.Recurrence.Timezone,
+		}
+	}
+	for _, reminder := range m.Reminders {
+		before, _ := models.ParseReminder(reminder)
+		todo.RemindBefore = append(todo.RemindBefore, before)
+	}
+
+	return todo
+}
+
+// taskStatusName turns TASK_STATUS_IN_PROGRESS into "in_progress"
+func taskStatusName(s todo_service.TaskStatus) string {
+	if s == todo_service.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(s.String(), "TASK_STATUS_"))
+}
+
+func parseTaskStatus(s string) todo_service.TaskStatus {
+	return todo_service.TaskStatus(todo_service.TaskStatus_value["TASK_STATUS_"+strings.ToUpper(s)])
+}
+
+// priorityName turns PRIORITY_HIGH into "high"
+func priorityName(p todo_service.Priority) string {
+	return strings.ToLower(strings.TrimPrefix(p.String(), "PRIORITY_"))
+}
+
+func parsePriority(s string) todo_service.Priority {
+	return todo_service.Priority(todo_service.Priority_value["PRIORITY_"+strings.ToUpper(s)])
+}
+
+// todoETag is the strong ETag of a todo's version, e.g. "3"
+func todoETag(t *todo_service.TodoModel) string {
+	return strconv.Quote(strconv.FormatInt(t.Version, 10))
+}
+
+// ifMatch is an If-Match header, any for * or without the header,
+// otherwise the todo versions its entity tags name
+type ifMatch struct {
+	any      bool
+	versions []int64
+}
+
+// parseIfMatch parses an If-Match header (RFC 9110, section 13.1.1), * or
+// a comma separated list of entity tags. If-Match compares strongly, so
+// weak tags never match, nor do tags which are no todo version.
+func parseIfMatch(header string) (ifMatch, error) {
+	s := strings.Trim(header, " \t")
+	if s == "" || s == "*" {
+		return ifMatch{any: true}, nil
+	}
+
+	var (
+		m    ifMatch
+		tags int
+	)
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			break
+		}
+
+		weak := strings.HasPrefix(s, "W/")
+		if weak {
+			s = s[2:]
+		}
+		if !strings.HasPrefix(s, `"`) {
+			return ifMatch{}, fmt.Errorf("%s is not a list of ETags", header)
+		}
+		end := strings.IndexByte(s[1:], '"') + 1
+		if end == 0 {
+			return ifMatch{}, fmt.Errorf("%s is not a list of ETags", header)
+		}
+		opaque := s[1:end]
+		s = strings.TrimLeft(s[end+1:], " \t")
+		if s != "" && s[0] != ',' {
+			return ifMatch{}, fmt.Errorf("%s is not a list of ETags", header)
+		}
+
+		tags++
+		version, err := strconv.ParseInt(opaque, 10, 64)
+		if !weak && err == nil && version > 0 && strconv.FormatInt(version, 10) == opaque {
+			m.versions = append(m.versions, version)
+		}
+	}
+	if tags == 0 {
+		return ifMatch{}, fmt.Errorf("%s is not a list of ETags", header)
+	}
+
+	return m, nil
+}
+
+// matches reports if a todo at version matches the header
+func (m ifMatch) matches(version int64) bool {
+	if m.any {
+		return true
+	}
+	for _, v := range m.versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ifMatchVersion returns the version If-Match makes a write of todo id
+// depend on, 0 for any. Of several ETags, it is the one of the current
+// todo, the write still fails if the todo changes meanwhile. ok is false
+// once it responded, with 412 and the current todo if no ETag can match.
+func (h *handlerV1) ifMatchVersion(ctx context.Context, c *gin.Context, id string) (version int64, ok bool) {
+	match, err := parseIfMatch(c.GetHeader("If-Match"))
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid If-Match")
+		return 0, false
+	}
+	if match.any {
+		return 0, true
+	}
+	if len(match.versions) == 1 {
+		return match.versions[0], true
+	}
+
+	current, err := h.grpcClient.TodoService().GetTodo(ctx, &todo_service.GetTodoRequest{Id: id})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to get todo")
+		return 0, false
+	}
+	if !match.matches(current.Version) {
+		c.Header("ETag", todoETag(current))
+		c.JSON(http.StatusPreconditionFailed, models.TodoConflictModel{
+			Message: fmt.Sprintf("todo %s is at version %d", current.Id, current.Version),
+			Reason:  ErrorCodeVersionConflict,
+			Current: todoToModel(current),
+		})
+		return 0, false
+	}
+
+	return current.Version, true
+}
 
+func timeValue(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
 }
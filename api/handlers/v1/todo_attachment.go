@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// attachmentChunkSize is the size of the chunks an upload is sent to the
+// service in
+const attachmentChunkSize = 64 << 10
+
+// @Router /v1/todo/{id}/attachments [post]
+// @Summary Attach a file to a Todo
+// @Description API to upload a file as the file field of a multipart form. The content type is sniffed from the content,
+// @Description uploads above the size limit or what is left of the user's quota fail with 413.
+// @Tags ATTACHMENT
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param file formData file true "file"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 201 {object} models.TodoAttachmentModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 413 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UploadTodoAttachment(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	// the form is read part by part, so the file is passed on to the
+	// service without buffering it here
+	file, err := formFile(c.Request, "file")
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid attachment")
+		return
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	stream, err := h.grpcClient.TodoService().UploadTodoAttachment(ctx)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to upload attachment")
+		return
+	}
+
+	err = stream.Send(&todo_service.AttachmentChunk{
+		Attachment: &todo_service.TodoAttachment{
+			TodoId:     c.Param("id"),
+			FileName:   file.FileName(),
+			UploadedBy: user.ID,
+		},
+	})
+
+	buf := make([]byte, attachmentChunkSize)
+	for err == nil {
+		n, readErr := io.ReadFull(file, buf)
+		if n > 0 {
+			// a failed send means the service gave up, its error is
+			// returned by CloseAndRecv
+			err = stream.Send(&todo_service.AttachmentChunk{Data: buf[:n]})
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			cancel()
+			h.handleBadRequest(c, readErr, "failed to read file")
+			return
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to upload attachment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachmentToModel(res))
+}
+
+// @Router /v1/todo/{id}/attachments [get]
+// @Summary Get attachments of a Todo
+// @Description API to retreive the files attached to a todo, oldest first
+// @Tags ATTACHMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.AllTodoAttachmentModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of attachments, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoAttachments(c *gin.Context) {
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTodoAttachments(ctx, &todo_service.ListTodoAttachmentsRequest{
+		TodoId: c.Param("id"),
+		Limit:  int64(limit),
+		Cursor: c.Query("cursor"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list attachments")
+		return
+	}
+
+	attachments := models.AllTodoAttachmentModel{
+		Attachments: make([]models.TodoAttachmentModel, 0, len(res.Attachments)),
+		Count:       totalCount(withCount, res.Count),
+		NextCursor:  res.NextCursor,
+	}
+	for _, attachment := range res.Attachments {
+		attachments.Attachments = append(attachments.Attachments, attachmentToModel(attachment))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, attachments)
+}
+
+// @Router /v1/todo/{id}/attachments/{attachment_id} [get]
+// @Summary Download an attachment
+// @Description API to download the content of a file attached to a todo
+// @Tags ATTACHMENT
+// @Produce  octet-stream
+// @Param id path string true "todo id"
+// @Param attachment_id path string true "attachment id"
+// @Success 200 {file} file
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) DownloadTodoAttachment(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	stream, err := h.grpcClient.TodoService().DownloadTodoAttachment(ctx, &todo_service.GetTodoAttachmentRequest{
+		Id:     c.Param("attachment_id"),
+		TodoId: c.Param("id"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to download attachment")
+		return
+	}
+
+	// the first message carries the attachment, errors show up there too
+	first, err := stream.Recv()
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to download attachment")
+		return
+	}
+	attachment := first.Attachment
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": attachment.FileName,
+	}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("ETag", strconv.Quote(attachment.Sha256))
+	c.Status(http.StatusOK)
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			// the status is sent already, the client sees a short body
+			h.log.Error("failed to download attachment", logger.Error(err))
+			c.Abort()
+			return
+		}
+		if _, err = c.Writer.Write(chunk.Data); err != nil {
+			return
+		}
+	}
+}
+
+// @Router /v1/todo/{id}/attachments/{attachment_id} [delete]
+// @Summary Delete an attachment
+// @Description API to remove a file from a todo
+// @Tags ATTACHMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param attachment_id path string true "attachment id"
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) DeleteTodoAttachment(c *gin.Context) {
+	var userID string
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	id := c.Param("attachment_id")
+
+	_, err := h.grpcClient.TodoService().DeleteTodoAttachment(ctx, &todo_service.GetTodoAttachmentRequest{
+		Id:      id,
+		TodoId:  c.Param("id"),
+		ActorId: userID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to delete attachment")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "attachment deleted",
+	})
+}
+
+func attachmentToModel(attachment *todo_service.TodoAttachment) models.TodoAttachmentModel {
+	return models.TodoAttachmentModel{
+		ID:          attachment.Id,
+		TodoID:      attachment.TodoId,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.Sha256,
+		UploadedBy:  attachment.UploadedBy,
+		CreatedAt:   timeValue(attachment.CreatedAt),
+	}
+}
+
+// formFile returns the first part of a multipart form with the field name,
+// the parts before it are skipped
+func formFile(r *http.Request, name string) (*multipart.Part, error) {
+	form, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == name {
+			return part, nil
+		}
+		part.Close()
+	}
+}
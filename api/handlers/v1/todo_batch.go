@@ -0,0 +1,307 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+)
+
+// TodoAction dispatches custom methods on the todo collection, which gin
+// routes as "/v1/todo:action" with the colon kept in the param
+func (h *handlerV1) TodoAction(c *gin.Context) {
+	switch c.Param("action") {
+	case ":batchCreate":
+		h.BatchCreateTodos(c)
+	case ":batchUpdate":
+		h.BatchUpdateTodos(c)
+	case ":batchDelete":
+		h.BatchDeleteTodos(c)
+	default:
+		c.JSON(http.StatusNotFound, models.ResponseError{
+			Message: "unknown todo action " + c.Param("action"),
+			Reason:  ErrorCodeInvalidURL,
+		})
+	}
+}
+
+// @Router /v1/todo:batchCreate [post]
+// @Summary Create many Todos
+// @Description API to create todos in bulk. Every item gets its own result, a bad item does not fail the whole batch. Items not started before the deadline get a 504 result, the items before them are kept.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param todos body models.BatchCreateTodoModel true "todos"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.BatchTodoResponseModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) BatchCreateTodos(c *gin.Context) {
+	var (
+		body   models.BatchCreateTodoModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := h.validateBatchSize(len(body.Todos)); err != nil {
+		h.handleBadRequest(c, err, "invalid batch")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	stream, err := h.grpcClient.TodoService().BulkCreateTodos(ctx)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create todos")
+		return
+	}
+
+	var (
+		results = make([]models.BatchTodoResultModel, len(body.Todos))
+		sent    []int
+	)
+	for i, item := range body.Todos {
+		results[i].Index = i
+
+		if err := item.Validate(); err != nil {
+			setBatchError(&results[i], http.StatusBadRequest, ErrorBadRequest, err.Error())
+			continue
+		}
+
+		todo := todoFromModel(item)
+		todo.UserId = userID
+
+		err = stream.Send(todo)
+		if err != nil {
+			// the real error is returned by CloseAndRecv
+			break
+		}
+		sent = append(sent, i)
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create todos")
+		return
+	}
+
+	h.writeBatchResponse(c, results, sent, res)
+}
+
+// @Router /v1/todo:batchUpdate [post]
+// @Summary Update many Todos
+// @Description API to update todos in bulk. Every item gets its own result, a bad item does not fail the whole batch. Items not started before the deadline get a 504 result, the items before them are kept.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param todos body models.BatchUpdateTodoModel true "todos"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.BatchTodoResponseModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) BatchUpdateTodos(c *gin.Context) {
+	var (
+		body   models.BatchUpdateTodoModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := h.validateBatchSize(len(body.Todos)); err != nil {
+		h.handleBadRequest(c, err, "invalid batch")
+		return
+	}
+
+	var (
+		results = make([]models.BatchTodoResultModel, len(body.Todos))
+		sent    []int
+		req     todo_service.BatchUpdateTodosRequest
+	)
+	for i, item := range body.Todos {
+		results[i].Index = i
+		results[i].ID = item.ID
+
+		if err := item.Validate(); err != nil {
+			setBatchError(&results[i], http.StatusBadRequest, ErrorBadRequest, err.Error())
+			continue
+		}
+
+		todo := todoFromModel(item.CreateTodoModel)
+		todo.Id = item.ID
+		todo.UpdatedBy = userID
+
+		req.Todos = append(req.Todos, todo)
+		sent = append(sent, i)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().BatchUpdateTodos(ctx, &req)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to update todos")
+		return
+	}
+
+	h.writeBatchResponse(c, results, sent, res)
+}
+
+// @Router /v1/todo:batchDelete [post]
+// @Summary Delete many Todos
+// @Description API to delete todos in bulk. Every id gets its own result, a missing todo does not fail the whole batch. Ids not reached before the deadline get a 504 result, the deletes before them are kept.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param ids body models.BatchDeleteTodoModel true "ids"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.BatchTodoResponseModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) BatchDeleteTodos(c *gin.Context) {
+	var (
+		body   models.BatchDeleteTodoModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := h.validateBatchSize(len(body.IDs)); err != nil {
+		h.handleBadRequest(c, err, "invalid batch")
+		return
+	}
+
+	var (
+		results = make([]models.BatchTodoResultModel, len(body.IDs))
+		sent    []int
+		req     = todo_service.BatchDeleteTodosRequest{ActorId: userID}
+	)
+	for i, id := range body.IDs {
+		results[i].Index = i
+		results[i].ID = id
+
+		if id == "" {
+			setBatchError(&results[i], http.StatusBadRequest, ErrorBadRequest, "id is required")
+			continue
+		}
+
+		req.Ids = append(req.Ids, id)
+		sent = append(sent, i)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().BatchDeleteTodos(ctx, &req)
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to delete todos")
+		return
+	}
+
+	h.writeBatchResponse(c, results, sent, res)
+}
+
+func (h *handlerV1) validateBatchSize(size int) error {
+	if size == 0 {
+		return errors.New("batch is empty")
+	}
+	if size > h.cfg.BatchMaxSize {
+		return fmt.Errorf("batch has %d items, at most %d are allowed", size, h.cfg.BatchMaxSize)
+	}
+	return nil
+}
+
+// writeBatchResponse merges the service results into results. sent maps
+// the index of an item in the service request to its index in the body,
+// items missing from it were already rejected by the gateway.
+func (h *handlerV1) writeBatchResponse(c *gin.Context, results []models.BatchTodoResultModel, sent []int, res *todo_service.BatchTodosResponse) {
+	for _, r := range res.Results {
+		if r.Index < 0 || int(r.Index) >= len(sent) {
+			continue
+		}
+		result := &results[sent[r.Index]]
+		if r.Id != "" {
+			result.ID = r.Id
+		}
+
+		httpStatus, reason, _ := grpcCodeToHTTP(codes.Code(r.Code))
+		if r.Code != int32(codes.OK) {
+			setBatchError(result, httpStatus, reason, r.Message)
+			continue
+		}
+
+		result.Status = httpStatus
+		if r.Todo != nil {
+			todo := todoToModel(r.Todo)
+			result.Todo = &todo
+		}
+	}
+
+	response := models.BatchTodoResponseModel{
+		Results: results,
+	}
+	for _, result := range results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func setBatchError(result *models.BatchTodoResultModel, httpStatus int, reason, message string) {
+	result.Status = httpStatus
+	result.Error = &models.ResponseError{
+		Code:    httpStatus,
+		Message: message,
+		Reason:  reason,
+	}
+}
@@ -0,0 +1,251 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/{id}/comments [get]
+// @Summary Get comments of a Todo
+// @Description API to retreive the comments of a todo, oldest first. Replies have the id of the comment they answer as parent_id.
+// @Tags COMMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param page query integer false "page, deprecated in favour of cursor"
+// @Param cursor query string false "next_cursor of the previous page, can't be combined with page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Param parent_id query string false "only the direct replies to this comment"
+// @Param mention query string false "only comments mentioning this username"
+// @Success 200 {object} models.AllTodoCommentModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of matching items, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoComments(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid page")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	cursor, err := ParseCursorQueryParam(c)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTodoComments(ctx, &todo_service.ListTodoCommentsRequest{
+		TodoId:    c.Param("id"),
+		Page:      int64(page),
+		Limit:     int64(limit),
+		ParentId:  c.Query("parent_id"),
+		Mention:   c.Query("mention"),
+		Cursor:    cursor,
+		WithCount: withCount,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list comments")
+		return
+	}
+
+	comments := models.AllTodoCommentModel{
+		Comments:   make([]models.TodoCommentModel, 0, len(res.Comments)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, comment := range res.Comments {
+		comments.Comments = append(comments.Comments, commentToModel(comment))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, comments)
+}
+
+// @Router /v1/todo/{id}/comments [post]
+// @Summary Comment on a Todo
+// @Description API to add a comment to a todo, or a reply with parent_id. Usernames mentioned as @username are stored with the comment.
+// @Tags COMMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param comment body models.CreateTodoCommentModel true "comment"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 201 {object} models.TodoCommentModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) CreateTodoComment(c *gin.Context) {
+	var body models.CreateTodoCommentModel
+
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid comment")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().CreateTodoComment(ctx, &todo_service.TodoComment{
+		TodoId:   c.Param("id"),
+		ParentId: body.ParentID,
+		AuthorId: user.ID,
+		Body:     body.Body,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, commentToModel(res))
+}
+
+// @Router /v1/todo/{id}/comments/{comment_id} [put]
+// @Summary Edit a comment
+// @Description API to change the body of a comment. Only its author may, the previous body is kept in edits.
+// @Tags COMMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param comment_id path string true "comment id"
+// @Param comment body models.UpdateTodoCommentModel true "comment"
+// @Success 200 {object} models.TodoCommentModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UpdateTodoComment(c *gin.Context) {
+	var body models.UpdateTodoCommentModel
+
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid comment")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().UpdateTodoComment(ctx, &todo_service.TodoComment{
+		Id:       c.Param("comment_id"),
+		TodoId:   c.Param("id"),
+		AuthorId: user.ID,
+		Body:     body.Body,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to update comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, commentToModel(res))
+}
+
+// @Router /v1/todo/{id}/comments/{comment_id} [delete]
+// @Summary Delete a comment
+// @Description API to delete a comment with all replies to it. Only its author may.
+// @Tags COMMENT
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param comment_id path string true "comment id"
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) DeleteTodoComment(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	id := c.Param("comment_id")
+
+	_, err = h.grpcClient.TodoService().DeleteTodoComment(ctx, &todo_service.DeleteTodoCommentRequest{
+		Id:      id,
+		TodoId:  c.Param("id"),
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "comment deleted",
+	})
+}
+
+func commentToModel(comment *todo_service.TodoComment) models.TodoCommentModel {
+	m := models.TodoCommentModel{
+		ID:        comment.Id,
+		TodoID:    comment.TodoId,
+		ParentID:  comment.ParentId,
+		AuthorID:  comment.AuthorId,
+		Body:      comment.Body,
+		Mentions:  comment.Mentions,
+		Edited:    len(comment.Edits) > 0,
+		Edits:     make([]models.CommentEditModel, 0, len(comment.Edits)),
+		CreatedAt: timeValue(comment.CreatedAt),
+		UpdatedAt: timeValue(comment.UpdatedAt),
+	}
+	if m.Mentions == nil {
+		m.Mentions = []string{}
+	}
+	for _, edit := range comment.Edits {
+		m.Edits = append(m.Edits, models.CommentEditModel{
+			Body:     edit.Body,
+			EditedAt: timeValue(edit.EditedAt),
+		})
+	}
+
+	return m
+}
@@ -0,0 +1,275 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/ical"
+	"github.com/abdukhashimov/go_gin_example/pkg/rrule"
+	"github.com/gin-gonic/gin"
+)
+
+const calendarProdID = "-//go_gin_example//todo_service//EN"
+
+// icalStatuses maps task statuses to the STATUS of a VTODO, VEVENTs are
+// only confirmed or cancelled
+var icalStatuses = map[todo_service.TaskStatus]string{
+	todo_service.TaskStatus_TASK_STATUS_TODO:        "NEEDS-ACTION",
+	todo_service.TaskStatus_TASK_STATUS_IN_PROGRESS: "IN-PROCESS",
+	todo_service.TaskStatus_TASK_STATUS_DONE:        "COMPLETED",
+	todo_service.TaskStatus_TASK_STATUS_CANCELLED:   "CANCELLED",
+	todo_service.TaskStatus_TASK_STATUS_REOPENED:    "NEEDS-ACTION",
+}
+
+// icalPriorities maps priorities to PRIORITY values, 1 is the highest and
+// 9 the lowest, none is left out
+var icalPriorities = map[todo_service.Priority]int{
+	todo_service.Priority_PRIORITY_URGENT: 1,
+	todo_service.Priority_PRIORITY_HIGH:   2,
+	todo_service.Priority_PRIORITY_MEDIUM: 5,
+	todo_service.Priority_PRIORITY_LOW:    9,
+}
+
+// @Router /v1/todo/export.ics [get]
+// @Summary Export Todos as iCalendar
+// @Description RFC 5545 calendar of the current user's todos. As VTODOs every todo is exported, with its due date, status, priority, recurrence rule and reminders as alarms.
+// @Description As VEVENTs only todos with a due date are exported, as events at their due dates. Completed occurrences of recurring todos are exported on their own, the open one carries the recurrence rule.
+// @Tags TODO
+// @Security ApiKeyAuth
+// @Produce  text/calendar
+// @Param component query string false "vtodo (default) for tasks or vevent for events at the due dates"
+// @Param list_id query string false "only export the todos of this list"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ExportTodos(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	h.writeCalendar(ctx, c, user.ID, "attachment")
+}
+
+// writeCalendar responds with the calendar of userID's todos, disposition
+// tells browsers to download or show it
+func (h *handlerV1) writeCalendar(ctx context.Context, c *gin.Context, userID, disposition string) {
+	component := strings.ToLower(c.DefaultQuery("component", "vtodo"))
+	if component != "vtodo" && component != "vevent" {
+		h.handleInvalidQuery(c, fmt.Errorf("component must be vtodo or vevent, not %q", component))
+		return
+	}
+
+	res, err := h.grpcClient.TodoService().ListTodos(ctx, &todo_service.ListTodosRequest{
+		ListId: c.Query("list_id"),
+		Sort:   []*todo_service.SortField{{Field: "created_at"}},
+		Filter: &todo_service.FilterExpr{Expr: &todo_service.FilterExpr_Condition{Condition: &todo_service.FilterCondition{
+			Field:  "user_id",
+			Op:     todo_service.FilterCondition_EQ,
+			Values: []*todo_service.FilterValue{{Value: &todo_service.FilterValue_StringValue{StringValue: userID}}},
+		}}},
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list todos")
+		return
+	}
+
+	var buf bytes.Buffer
+	if err = writeTodoCalendar(&buf, res.Todos, component == "vevent"); err != nil {
+		h.handleInternalServerError(c, err, "failed to write calendar")
+		return
+	}
+
+	c.Header("Content-Disposition", disposition+`; filename="todos.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", buf.Bytes())
+}
+
+// writeTodoCalendar writes todos as a calendar of VTODOs, or of VEVENTs at
+// their due dates for events
+func writeTodoCalendar(w io.Writer, todos []*todo_service.TodoModel, events bool) error {
+	cal := ical.NewWriter(w)
+	cal.Begin("VCALENDAR")
+	cal.Line("VERSION", "2.0")
+	cal.Line("PRODID", calendarProdID)
+	cal.Line("CALSCALE", "GREGORIAN")
+	cal.Line("METHOD", "PUBLISH")
+	cal.Text("X-WR-CALNAME", "Todos")
+
+	// recurring todos are written in the time zone of their rule, each
+	// zone is defined once from the earliest due date in it
+	zones := make(map[string]time.Time)
+	for _, t := range todos {
+		if loc := todoLocation(t); loc != time.UTC {
+			due := t.DueAt.AsTime()
+			if from, ok := zones[loc.String()]; !ok || due.Before(from) {
+				zones[loc.String()] = due
+			}
+		}
+	}
+	names := make([]string, 0, len(zones))
+	for name := range zones {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		loc, _ := time.LoadLocation(name)
+		cal.Timezone(loc, zones[name])
+	}
+
+	for _, t := range todos {
+		if events {
+			if t.DueAt != nil {
+				writeTodoEvent(cal, t)
+			}
+		} else {
+			writeTodo(cal, t)
+		}
+	}
+
+	cal.End("VCALENDAR")
+
+	return cal.Flush()
+}
+
+func writeTodo(cal *ical.Writer, t *todo_service.TodoModel) {
+	cal.Begin("VTODO")
+	writeTodoProperties(cal, t)
+
+	if t.DueAt != nil {
+		due := t.DueAt.AsTime().In(todoLocation(t))
+		if rule := todoRule(t); rule != "" {
+			// a rule needs a start, due is the first occurrence
+			cal.LocalTime("DTSTART", due)
+			cal.LocalTime("DUE", due)
+			cal.Line("RRULE", rule)
+		} else {
+			cal.LocalTime("DUE", due)
+		}
+	}
+
+	if s, ok := icalStatuses[t.TaskStatus]; ok {
+		cal.Line("STATUS", s)
+	}
+	if t.TaskStatus == todo_service.TaskStatus_TASK_STATUS_DONE {
+		cal.Time("COMPLETED", t.UpdatedAt.AsTime())
+		cal.Line("PERCENT-COMPLETE", "100")
+	}
+
+	// alarms of a VTODO are related to its DUE by END
+	writeTodoAlarms(cal, t, "TRIGGER;RELATED=END")
+	cal.End("VTODO")
+}
+
+func writeTodoEvent(cal *ical.Writer, t *todo_service.TodoModel) {
+	cal.Begin("VEVENT")
+	writeTodoProperties(cal, t)
+
+	// without DTEND the event takes no time, it doesn't block the calendar
+	cal.LocalTime("DTSTART", t.DueAt.AsTime().In(todoLocation(t)))
+	if rule := todoRule(t); rule != "" {
+		cal.Line("RRULE", rule)
+	}
+	cal.Line("TRANSP", "TRANSPARENT")
+
+	status := "CONFIRMED"
+	if t.TaskStatus == todo_service.TaskStatus_TASK_STATUS_CANCELLED {
+		status = "CANCELLED"
+	}
+	cal.Line("STATUS", status)
+
+	writeTodoAlarms(cal, t, "TRIGGER")
+	cal.End("VEVENT")
+}
+
+// writeTodoProperties writes the properties VTODOs and VEVENTs share
+func writeTodoProperties(cal *ical.Writer, t *todo_service.TodoModel) {
+	cal.Text("UID", t.Id)
+	cal.Time("DTSTAMP", t.UpdatedAt.AsTime())
+	cal.Time("CREATED", t.CreatedAt.AsTime())
+	cal.Time("LAST-MODIFIED", t.UpdatedAt.AsTime())
+	if t.Version > 1 {
+		cal.Line("SEQUENCE", fmt.Sprint(t.Version-1))
+	}
+	cal.Text("SUMMARY", t.TaskName)
+	if t.Description != "" {
+		cal.Text("DESCRIPTION", t.Description)
+	}
+	if p, ok := icalPriorities[t.Priority]; ok {
+		cal.Line("PRIORITY", fmt.Sprint(p))
+	}
+	if t.ParentId != "" {
+		cal.Text("RELATED-TO", t.ParentId)
+	}
+}
+
+// writeTodoAlarms writes the reminders of an open todo as display alarms
+func writeTodoAlarms(cal *ical.Writer, t *todo_service.TodoModel, trigger string) {
+	if t.DueAt == nil || t.TaskStatus == todo_service.TaskStatus_TASK_STATUS_DONE ||
+		t.TaskStatus == todo_service.TaskStatus_TASK_STATUS_CANCELLED {
+		return
+	}
+
+	for _, before := range t.RemindBefore {
+		cal.Begin("VALARM")
+		cal.Line("ACTION", "DISPLAY")
+		cal.Line(trigger, ical.FormatDuration(-time.Duration(before)*time.Second))
+		cal.Text("DESCRIPTION", t.TaskName)
+		cal.End("VALARM")
+	}
+}
+
+// todoLocation returns the time zone of a recurring todo with a due date,
+// UTC for other todos
+func todoLocation(t *todo_service.TodoModel) *time.Location {
+	if t.DueAt == nil || t.Recurrence == nil || t.Recurrence.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(t.Recurrence.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// todoRule returns the RRULE of the open occurrence of a recurring todo,
+// empty for other todos. Completed occurrences are separate todos, so the
+// rule starts at this one: a COUNT, which counts from the first
+// occurrence, becomes the UNTIL of the last.
+func todoRule(t *todo_service.TodoModel) string {
+	rec := t.Recurrence
+	if rec == nil || rec.NextId != "" || t.DueAt == nil {
+		return ""
+	}
+
+	rule, err := rrule.Parse(rec.Rule)
+	if err != nil {
+		return ""
+	}
+	if rule.Count > 0 {
+		start := t.DueAt.AsTime()
+		if rec.Start != nil {
+			start = rec.Start.AsTime()
+		}
+		var last time.Time
+		it := rule.Iterator(start.In(todoLocation(t)))
+		for next, ok := it.Next(); ok; next, ok = it.Next() {
+			last = next
+		}
+		rule.Count, rule.Until = 0, last
+	}
+
+	return rule.String()
+}
@@ -0,0 +1,365 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// listTodoActions maps the todos query param of DeleteTodoList to what
+// happens to the todos of the deleted list
+var listTodoActions = map[string]todo_service.DeleteTodoListRequest_TodoAction{
+	"inbox":   todo_service.DeleteTodoListRequest_MOVE_TO_INBOX,
+	"cascade": todo_service.DeleteTodoListRequest_CASCADE,
+}
+
+// @Router /v1/lists [post]
+// @Summary Create a Todo list
+// @Description API to create a new todo list
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param list body models.CreateTodoListModel true "list"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 201 {object} models.TodoListModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) CreateTodoList(c *gin.Context) {
+	var (
+		body   models.CreateTodoListModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid list")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().CreateTodoList(ctx, &todo_service.TodoList{
+		Name:        body.Name,
+		Description: body.Description,
+		UserId:      userID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to create list")
+		return
+	}
+
+	c.JSON(http.StatusCreated, todoListToModel(res))
+}
+
+// @Router /v1/lists [get]
+// @Summary Get all Todo lists
+// @Description API to retreive todo lists, archived lists are left out unless archived is true
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param page query integer false "page, deprecated in favour of cursor"
+// @Param cursor query string false "next_cursor of the previous page, can't be combined with page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Param search query string false "search"
+// @Param archived query boolean false "include archived lists"
+// @Param sort query string false "comma separated field|direction keys, fields are name, created_at and updated_at, ties are ordered by id" example(name|asc)
+// @Param filter query string false "filter expression comparing name, description, user_id, archived_at, created_at or updated_at, see GET /v1/todo" example(created_at >= "2026-01-01")
+// @Success 200 {object} models.AllTodoListModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of matching items, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetAllTodoLists(c *gin.Context) {
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid page")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	search, _ := ParseSearchQueryParam(c)
+
+	cursor, err := ParseCursorQueryParam(c)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	archived, err := strconv.ParseBool(c.DefaultQuery("archived", "false"))
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid archived")
+		return
+	}
+
+	sort, err := ParseSortQueryParam(c, models.TodoListSort)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	filter, err := ParseFilterQueryParam(c, todoListFilter)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTodoLists(ctx, &todo_service.ListTodoListsRequest{
+		Page:            int64(page),
+		Limit:           int64(limit),
+		Search:          search,
+		IncludeArchived: archived,
+		Sort:            sort,
+		Filter:          filter,
+		Cursor:          cursor,
+		WithCount:       withCount,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list lists")
+		return
+	}
+
+	lists := models.AllTodoListModel{
+		Lists:      make([]models.TodoListModel, 0, len(res.Lists)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, l := range res.Lists {
+		lists.Lists = append(lists.Lists, todoListToModel(l))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, lists)
+}
+
+// @Router /v1/lists/{id} [get]
+// @Summary Get a Todo list
+// @Description API to retreive a single todo list, its todos are listed by GET /v1/todo?list_id=
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Success 200 {object} models.TodoListModel
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoList(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().GetTodoList(ctx, &todo_service.GetTodoListRequest{
+		Id: c.Param("id"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to get list")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoListToModel(res))
+}
+
+// @Router /v1/lists/{id} [put]
+// @Summary Update a Todo list
+// @Description API to rename a todo list or change its description
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Param list body models.CreateTodoListModel true "list"
+// @Success 200 {object} models.TodoListModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UpdateTodoList(c *gin.Context) {
+	var body models.CreateTodoListModel
+
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid list")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().UpdateTodoList(ctx, &todo_service.TodoList{
+		Id:          c.Param("id"),
+		Name:        body.Name,
+		Description: body.Description,
+		UserId:      user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to update list")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoListToModel(res))
+}
+
+// @Router /v1/lists/{id} [delete]
+// @Summary Delete a Todo list
+// @Description API to move a todo list to the trash, see GET /v1/trash. With todos=inbox, the default, its todos move to the owner's inbox list,
+// @Description with todos=cascade they go to the trash along with their subtasks and come back when the list is restored. The inbox list itself can't be deleted.
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Param todos query string false "what happens to the todos of the list" Enums(inbox, cascade)
+// @Success 200 {object} models.Response
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) DeleteTodoList(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	id := c.Param("id")
+
+	action, ok := listTodoActions[c.DefaultQuery("todos", "inbox")]
+	if !ok {
+		h.handleBadRequest(c, errors.New("todos must be inbox or cascade"), "invalid todos")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	_, err = h.grpcClient.TodoService().DeleteTodoList(ctx, &todo_service.DeleteTodoListRequest{
+		Id:      id,
+		Todos:   action,
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to delete list")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "list moved to trash",
+	})
+}
+
+// @Router /v1/lists/{id}/archive [post]
+// @Summary Archive a Todo list
+// @Description API to archive a todo list. Archived lists are hidden from the list of lists and take no new todos.
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.TodoListModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) ArchiveTodoList(c *gin.Context) {
+	h.archiveTodoList(c, true)
+}
+
+// @Router /v1/lists/{id}/unarchive [post]
+// @Summary Unarchive a Todo list
+// @Description API to restore an archived todo list
+// @Tags LIST
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.TodoListModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) UnarchiveTodoList(c *gin.Context) {
+	h.archiveTodoList(c, false)
+}
+
+func (h *handlerV1) archiveTodoList(c *gin.Context, archived bool) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ArchiveTodoList(ctx, &todo_service.ArchiveTodoListRequest{
+		Id:       c.Param("id"),
+		Archived: archived,
+		ActorId:  user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to archive list")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoListToModel(res))
+}
+
+func todoListToModel(l *todo_service.TodoList) models.TodoListModel {
+	return models.TodoListModel{
+		ID:          l.Id,
+		Name:        l.Name,
+		Description: l.Description,
+		UserID:      l.UserId,
+		Inbox:       l.Inbox,
+		Archived:    l.ArchivedAt != nil,
+		ArchivedAt:  timeValue(l.ArchivedAt),
+		CreatedAt:   timeValue(l.CreatedAt),
+		UpdatedAt:   timeValue(l.UpdatedAt),
+		DeletedAt:   timeValue(l.DeletedAt),
+	}
+}
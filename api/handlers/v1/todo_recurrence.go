@@ -0,0 +1,54 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/{id}/occurrences [get]
+// @Summary Preview occurrences of a recurring Todo
+// @Description API to retreive the due date of a recurring todo and the occurrences following it, in the time zone of its recurrence.
+// @Description The list ends early when the rule has a COUNT or UNTIL.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param limit query integer false "number of occurrences, 10 by default and at most 100"
+// @Success 200 {object} models.OccurrencesModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoOccurrences(c *gin.Context) {
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListOccurrences(ctx, &todo_service.ListOccurrencesRequest{
+		Id:    c.Param("id"),
+		Limit: int64(limit),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list occurrences")
+		return
+	}
+
+	occurrences := models.OccurrencesModel{
+		Occurrences: make([]time.Time, 0, len(res.Occurrences)),
+	}
+	for _, t := range res.Occurrences {
+		occurrences.Occurrences = append(occurrences.Occurrences, *timeValue(t))
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
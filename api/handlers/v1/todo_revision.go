@@ -0,0 +1,157 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/{id}/history [get]
+// @Summary Get history of a Todo
+// @Description API to retreive every revision of a todo, oldest first. A revision is recorded on create, update, status change, delete and restore,
+// @Description with the fields it changed, who made it and the todo as it was after it.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.TodoHistoryModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of revisions, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoHistory(c *gin.Context) {
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTodoRevisions(ctx, &todo_service.ListTodoRevisionsRequest{
+		TodoId: c.Param("id"),
+		Limit:  int64(limit),
+		Cursor: c.Query("cursor"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list todo revisions")
+		return
+	}
+
+	history := models.TodoHistoryModel{
+		Revisions:  make([]models.TodoRevisionModel, 0, len(res.Revisions)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, r := range res.Revisions {
+		history.Revisions = append(history.Revisions, revisionToModel(r))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, history)
+}
+
+// @Router /v1/todo/{id}/revert [post]
+// @Summary Revert a Todo
+// @Description API to bring a todo back to how it was after an earlier revision. The revert is checked like an update and is recorded as a new revision. Reverting to the current state changes nothing and returns the todo unchanged.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param revision query integer true "revision to revert to"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.SingleTodoModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) RevertTodo(c *gin.Context) {
+	var userID string
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	revision, err := strconv.ParseInt(c.Query("revision"), 10, 64)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid revision")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().RevertTodo(ctx, &todo_service.RevertTodoRequest{
+		Id:       c.Param("id"),
+		Revision: revision,
+		ActorId:  userID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to revert todo")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoToModel(res))
+}
+
+func revisionToModel(r *todo_service.TodoRevision) models.TodoRevisionModel {
+	rev := models.TodoRevisionModel{
+		Revision:   r.Revision,
+		Action:     strings.ToLower(strings.TrimPrefix(r.Action.String(), "REVISION_")),
+		ActorID:    r.ActorId,
+		Changes:    make([]models.FieldChangeModel, 0, len(r.Changes)),
+		Todo:       todoToModel(r.Todo),
+		RevertedTo: r.RevertedTo,
+		OccurredAt: timeValue(r.OccurredAt),
+	}
+	for _, change := range r.Changes {
+		rev.Changes = append(rev.Changes, models.FieldChangeModel{
+			Field: change.Field,
+			From:  revisionValueName(change.Field, change.From),
+			To:    revisionValueName(change.Field, change.To),
+		})
+	}
+
+	return rev
+}
+
+// revisionValueName turns the enum values of a change into the names the
+// api uses, TASK_STATUS_IN_PROGRESS into "in_progress"
+func revisionValueName(field, v string) string {
+	switch field {
+	case "task_status":
+		if s, ok := todo_service.TaskStatus_value[v]; ok {
+			return taskStatusName(todo_service.TaskStatus(s))
+		}
+	case "priority":
+		if p, ok := todo_service.Priority_value[v]; ok {
+			return priorityName(todo_service.Priority(p))
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,163 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/search [get]
+// @Summary Search Todos
+// @Description API to search the user's todos by title, description and comments. Words are matched by stem and by prefix, every word must match. Results are ranked by relevance and come with highlighted snippets of the matching fields.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param q query string true "search query" example(plan meeting)
+// @Param page query integer false "page, deprecated in favour of cursor"
+// @Param cursor query string false "next_cursor of the previous page, can't be combined with page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.SearchTodosModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of matching items, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) SearchTodos(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		h.handleBadRequest(c, errors.New("q is required"), "invalid query")
+		return
+	}
+
+	page, err := ParsePageQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid page")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	cursor, err := ParseCursorQueryParam(c)
+	if err != nil {
+		h.handleInvalidQuery(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().SearchTodos(ctx, &todo_service.SearchTodosRequest{
+		UserId: user.ID,
+		Query:  query,
+		Page:   int64(page),
+		Limit:  int64(limit),
+		Cursor: cursor,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to search todos")
+		return
+	}
+
+	result := models.SearchTodosModel{
+		Hits:       make([]models.SearchHitModel, 0, len(res.Hits)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, hit := range res.Hits {
+		highlights := make([]models.SearchHighlightModel, 0, len(hit.Highlights))
+		for _, hl := range hit.Highlights {
+			highlights = append(highlights, models.SearchHighlightModel{
+				Field:   hl.Field,
+				Snippet: hl.Snippet,
+			})
+		}
+
+		result.Hits = append(result.Hits, models.SearchHitModel{
+			Todo:       todoToModel(hit.Todo),
+			Score:      hit.Score,
+			Highlights: highlights,
+		})
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, result)
+}
+
+// @Router /v1/todo/suggest [get]
+// @Summary Suggest Todo Titles
+// @Description API to complete a prefix of the titles and #tags of the user's todos and the names of their lists while typing. Tags are the #words in the title or description of a todo. Titles starting with the prefix come first, then those with a word starting with it, the most used ones first. A prefix ending with a space only completes whole words.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param prefix query string true "typed prefix" example(plan)
+// @Param limit query integer false "limit, at most 20"
+// @Success 200 {object} models.SuggestionsModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) SuggestTodos(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	prefix := c.Query("prefix")
+	if strings.TrimSpace(prefix) == "" {
+		h.handleBadRequest(c, errors.New("prefix is required"), "invalid prefix")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().SuggestTodos(ctx, &todo_service.SuggestTodosRequest{
+		UserId: user.ID,
+		Prefix: prefix,
+		Limit:  int64(limit),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to suggest todos")
+		return
+	}
+
+	suggestions := models.SuggestionsModel{
+		Suggestions: make([]models.SuggestionModel, 0, len(res.Suggestions)),
+	}
+	for _, s := range res.Suggestions {
+		suggestions.Suggestions = append(suggestions.Suggestions, models.SuggestionModel{
+			Text:  s.Text,
+			Kind:  s.Kind,
+			Count: s.Count,
+		})
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
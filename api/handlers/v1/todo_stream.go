@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+var todoEventNames = map[todo_service.TodoEventType]string{
+	todo_service.TodoEventType_TODO_CREATED:     "created",
+	todo_service.TodoEventType_TODO_UPDATED:     "updated",
+	todo_service.TodoEventType_TODO_DELETED:     "deleted",
+	todo_service.TodoEventType_TODO_EVENTS_LOST: "reset",
+	todo_service.TodoEventType_TODO_REMINDER:    "reminder",
+}
+
+// @Router /v1/todo/stream [get]
+// @Summary Stream todo changes
+// @Description Server-Sent Events stream of created, updated and deleted events for the current user's todos,
+// @Description and reminder events when one of their reminders becomes due.
+// @Description Every event carries an id; reconnecting with the Last-Event-ID header resumes after it.
+// @Description A "reset" event means some events were lost and the client should reload its todos.
+// @Tags TODO
+// @Security ApiKeyAuth
+// @Produce  text/event-stream
+// @Param Last-Event-ID header string false "id of the last received event"
+// @Success 200 {object} models.TodoEventModel
+// @Failure 401 {object} models.ResponseError
+func (h *handlerV1) StreamTodos(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource can't set headers on the first connection
+		lastEventID = c.Query("last_event_id")
+	}
+
+	var lastID uint64
+	if lastEventID != "" {
+		if lastID, err = strconv.ParseUint(lastEventID, 10, 64); err != nil {
+			h.handleBadRequest(c, err, "invalid Last-Event-ID")
+			return
+		}
+	}
+
+	// cancelled when the client goes away, which tears down the grpc stream
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	stream, err := h.grpcClient.TodoService().WatchTodos(ctx, &todo_service.WatchTodosRequest{
+		UserId:      user.ID,
+		LastEventId: lastID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to watch todos")
+		return
+	}
+
+	var (
+		events = make(chan *todo_service.TodoEvent)
+		errs   = make(chan error, 1)
+	)
+
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(time.Duration(h.cfg.SSEHeartbeatInterval) * time.Second)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case err := <-errs:
+			if err != io.EOF && ctx.Err() == nil {
+				h.log.Error("todo stream closed", logger.Error(err))
+			}
+			return false
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case event := <-events:
+			data := models.TodoEventModel{
+				Type:       todoEventNames[event.Type],
+				OccurredAt: event.OccurredAt.AsTime().Format(time.RFC3339Nano),
+			}
+			if event.Todo != nil {
+				todo := todoToModel(event.Todo)
+				data.Todo = &todo
+			}
+			if event.Reminder != nil {
+				data.Reminder = &models.ReminderModel{
+					RemindBefore: models.FormatReminder(event.Reminder.RemindBefore),
+					RemindAt:     event.Reminder.RemindAt.AsTime(),
+				}
+			}
+
+			e := sse.Event{
+				Event: data.Type,
+				Data:  data,
+			}
+			if event.Id != 0 {
+				e.Id = strconv.FormatUint(event.Id, 10)
+			}
+
+			return sse.Encode(w, e) == nil
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/{id}/subtasks [get]
+// @Summary Get subtasks of a Todo
+// @Description API to retreive the direct subtasks of a todo, oldest first, or with recursive the whole tree of subtasks below it. Pages hold direct subtasks, with their subtrees when recursive.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param recursive query boolean false "include subtasks of subtasks"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.SubtasksModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of direct subtasks, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetSubtasks(c *gin.Context) {
+	recursive, err := strconv.ParseBool(c.DefaultQuery("recursive", "false"))
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid recursive")
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListSubtasks(ctx, &todo_service.ListSubtasksRequest{
+		Id:        c.Param("id"),
+		Recursive: recursive,
+		Limit:     int64(limit),
+		Cursor:    c.Query("cursor"),
+		WithCount: withCount,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list subtasks")
+		return
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, models.SubtasksModel{
+		Subtasks:   subtaskModels(res.Subtasks),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	})
+}
+
+func subtaskModels(nodes []*todo_service.TodoNode) []models.SubtaskModel {
+	subtasks := make([]models.SubtaskModel, 0, len(nodes))
+	for _, node := range nodes {
+		subtasks = append(subtasks, models.SubtaskModel{
+			SingleTodoModel: todoToModel(node.Todo),
+			Subtasks:        subtaskModels(node.Subtasks),
+		})
+	}
+
+	return subtasks
+}
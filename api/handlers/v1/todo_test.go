@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   ifMatch
+	}{
+		{``, ifMatch{any: true}},
+		{` * `, ifMatch{any: true}},
+		{`"3"`, ifMatch{versions: []int64{3}}},
+		{`"3", "4"`, ifMatch{versions: []int64{3, 4}}},
+		{`"3","4" ,, "5"`, ifMatch{versions: []int64{3, 4, 5}}},
+		// If-Match compares strongly, weak tags never match
+		{`W/"3"`, ifMatch{}},
+		{`W/"3", "4"`, ifMatch{versions: []int64{4}}},
+		// tags other servers could have sent are no todo versions
+		{`"abc", "0", "03", "-1", "a,b"`, ifMatch{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := parseIfMatch(tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseIfMatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIfMatchInvalid(t *testing.T) {
+	for _, header := range []string{`3`, `"3`, `"3" "4"`, `"3", *`, `w/"3"`, `W/3`, `,`} {
+		t.Run(header, func(t *testing.T) {
+			if got, err := parseIfMatch(header); err == nil {
+				t.Errorf("parseIfMatch() = %+v, want an error", got)
+			}
+		})
+	}
+}
+
+func TestIfMatchMatches(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int64
+		want    bool
+	}{
+		{`*`, 7, true},
+		{`"7"`, 7, true},
+		{`"6", "7"`, 7, true},
+		{`"6", "8"`, 7, false},
+		{`W/"7"`, 7, false},
+	}
+	for _, tt := range tests {
+		m, err := parseIfMatch(tt.header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := m.matches(tt.version); got != tt.want {
+			t.Errorf("%s matches version %d = %v, want %v", tt.header, tt.version, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/todo/{id}/transition [post]
+// @Summary Change the status of a Todo
+// @Description API to move a todo to another status. The move must be allowed by the workflow of the todo's workspace,
+// @Description by default todo -> in_progress -> done, done -> reopened, and cancelled is terminal.
+// @Description With recursive the subtasks move too, unless they are already in the status or in a terminal one.
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param transition body models.TransitionTodoModel true "transition"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.SingleTodoModel
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) TransitionTodo(c *gin.Context) {
+	var (
+		body   models.TransitionTodoModel
+		userID string
+	)
+
+	if c.GetHeader("Authorization") != "" {
+		user, err := userInfo(h, c)
+		if err != nil {
+			return
+		}
+		userID = user.ID
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		h.handleBadRequest(c, err, "failed to bind json")
+		return
+	}
+
+	if err := body.Validate(); err != nil {
+		h.handleBadRequest(c, err, "invalid transition")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().TransitionTodo(ctx, &todo_service.TransitionTodoRequest{
+		Id:        c.Param("id"),
+		To:        parseTaskStatus(body.TaskStatus),
+		ActorId:   userID,
+		Comment:   body.Comment,
+		Recursive: body.Recursive,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to change todo status")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoToModel(res))
+}
+
+// @Router /v1/todo/{id}/transitions [get]
+// @Summary Get status history of a Todo
+// @Description API to retreive every status change of a todo with who made it and when, oldest first
+// @Tags TODO
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.TodoTransitionsModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of status changes, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTodoTransitions(c *gin.Context) {
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTodoTransitions(ctx, &todo_service.ListTodoTransitionsRequest{
+		TodoId: c.Param("id"),
+		Limit:  int64(limit),
+		Cursor: c.Query("cursor"),
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list todo transitions")
+		return
+	}
+
+	transitions := models.TodoTransitionsModel{
+		Transitions: make([]models.TodoTransitionModel, 0, len(res.Transitions)),
+		Count:       totalCount(withCount, res.Count),
+		NextCursor:  res.NextCursor,
+	}
+	for _, t := range res.Transitions {
+		transitions.Transitions = append(transitions.Transitions, models.TodoTransitionModel{
+			ID:         t.Id,
+			TodoID:     t.TodoId,
+			From:       taskStatusName(t.From),
+			To:         taskStatusName(t.To),
+			ActorID:    t.ActorId,
+			Comment:    t.Comment,
+			OccurredAt: timeValue(t.OccurredAt),
+		})
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, transitions)
+}
@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/api/models"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /v1/trash [get]
+// @Summary Get the trash
+// @Description API to retreive the user's deleted todos and lists, most recently deleted first.
+// @Description Items stay in the trash for a configured number of days before they are deleted for good.
+// @Tags TRASH
+// @Accept  json
+// @Produce  json
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query integer false "limit"
+// @Param count query boolean false "count the items into count and X-Total-Count"
+// @Success 200 {object} models.TrashModel
+// @Header 200 {string} Link "first and next page, see RFC 8288"
+// @Header 200 {integer} X-Total-Count "number of items in the trash, only with count=true"
+// @Failure 400 {object} models.ResponseError
+// @Failure 401 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) GetTrash(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	limit, err := ParseLimitQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid limit")
+		return
+	}
+
+	withCount, err := ParseCountQueryParam(c)
+	if err != nil {
+		h.handleBadRequest(c, err, "invalid count")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().ListTrash(ctx, &todo_service.ListTrashRequest{
+		UserId:    user.ID,
+		Limit:     int64(limit),
+		Cursor:    c.Query("cursor"),
+		WithCount: withCount,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to list trash")
+		return
+	}
+
+	trash := models.TrashModel{
+		Items:      make([]models.TrashItemModel, 0, len(res.Items)),
+		Count:      totalCount(withCount, res.Count),
+		NextCursor: res.NextCursor,
+	}
+	for _, item := range res.Items {
+		trash.Items = append(trash.Items, trashItemToModel(item))
+	}
+
+	setPageHeaders(c, totalCount(withCount, res.Count), res.NextCursor)
+	c.JSON(http.StatusOK, trash)
+}
+
+// @Router /v1/todo/{id}/restore [post]
+// @Summary Restore a Todo
+// @Description API to take a todo of the user out of the trash, with the subtasks deleted along with it. Its parent and list must not be in the trash.
+// @Tags TRASH
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.SingleTodoModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) RestoreTodo(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().RestoreTodo(ctx, &todo_service.RestoreTodoRequest{
+		Id:      c.Param("id"),
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to restore todo")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoToModel(res))
+}
+
+// @Router /v1/trash/todo/{id} [delete]
+// @Summary Delete a Todo for good
+// @Description API to permanently delete a todo of the user in the trash, with its subtasks, comments and attachments
+// @Tags TRASH
+// @Accept  json
+// @Produce  json
+// @Param id path string true "todo id"
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) PurgeTodo(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	id := c.Param("id")
+
+	_, err = h.grpcClient.TodoService().PurgeTodo(ctx, &todo_service.PurgeTodoRequest{
+		Id:      id,
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to purge todo")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "todo deleted",
+	})
+}
+
+// @Router /v1/lists/{id}/restore [post]
+// @Summary Restore a Todo list
+// @Description API to take a todo list of the user out of the trash, with the todos deleted along with it
+// @Tags TRASH
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Param Idempotency-Key header string false "key to retry the request with, retries get the first response, needs a token"
+// @Success 200 {object} models.TodoListModel
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 422 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) RestoreTodoList(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	res, err := h.grpcClient.TodoService().RestoreTodoList(ctx, &todo_service.RestoreTodoListRequest{
+		Id:      c.Param("id"),
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to restore list")
+		return
+	}
+
+	c.JSON(http.StatusOK, todoListToModel(res))
+}
+
+// @Router /v1/trash/list/{id} [delete]
+// @Summary Delete a Todo list for good
+// @Description API to permanently delete a todo list of the user in the trash, with the todos in the trash which are still in it
+// @Tags TRASH
+// @Accept  json
+// @Produce  json
+// @Param id path string true "list id"
+// @Success 200 {object} models.Response
+// @Failure 401 {object} models.ResponseError
+// @Failure 403 {object} models.ResponseError
+// @Failure 404 {object} models.ResponseError
+// @Failure 409 {object} models.ResponseError
+// @Failure 500 {object} models.ResponseError
+func (h *handlerV1) PurgeTodoList(c *gin.Context) {
+	user, err := userInfo(h, c)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), time.Duration(h.cfg.CtxTimeout)*time.Millisecond)
+	defer cancel()
+
+	id := c.Param("id")
+
+	_, err = h.grpcClient.TodoService().PurgeTodoList(ctx, &todo_service.PurgeTodoListRequest{
+		Id:      id,
+		ActorId: user.ID,
+	})
+	if err != nil {
+		h.handleGrpcError(c, err, "failed to purge list")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.Response{
+		ID:      id,
+		Message: "list deleted",
+	})
+}
+
+func trashItemToModel(item *todo_service.TrashItem) models.TrashItemModel {
+	if t := item.GetTodo(); t != nil {
+		todo := todoToModel(t)
+		return models.TrashItemModel{
+			Type:      "todo",
+			Todo:      &todo,
+			DeletedAt: todo.DeletedAt,
+		}
+	}
+
+	list := todoListToModel(item.GetList())
+	return models.TrashItemModel{
+		Type:      "list",
+		List:      &list,
+		DeletedAt: list.DeletedAt,
+	}
+}
@@ -27,8 +27,6 @@ type Config struct {
 func New(cnf Config) *gin.Engine {
 	router := gin.New()
 
-	router.Static("/images", "./static/images")
-
 	router.Use(gin.Logger())
 
 	router.Use(gin.Recovery())
@@ -52,12 +50,53 @@ func New(cnf Config) *gin.Engine {
 		c.JSON(http.StatusOK, gin.H{"data": "Api gateway"})
 	})
 
+	router.GET("/images/*name", handlerV1.GetImage)
+	router.HEAD("/images/*name", handlerV1.GetImage)
+
 	// -- Todo -->
 	router.GET("/v1/todo", handlerV1.GetAllTodo)
-	router.POST("/v1/todo", handlerV1.CreateNewTodo)
+	router.POST("/v1/todo", handlerV1.Idempotent, handlerV1.CreateNewTodo)
+	router.POST("/v1/todo:action", handlerV1.Idempotent, handlerV1.TodoAction)
+	router.GET("/v1/todo/stream", handlerV1.StreamTodos)
+	router.GET("/v1/todo/search", handlerV1.SearchTodos)
+	router.GET("/v1/todo/suggest", handlerV1.SuggestTodos)
+	router.GET("/v1/todo/export.ics", handlerV1.ExportTodos)
 	router.GET("/v1/todo/:id", handlerV1.GetTodo)
 	router.PUT("/v1/todo/:id", handlerV1.UpdateTodo)
 	router.DELETE("/v1/todo/:id", handlerV1.DeleteTodo)
+	router.POST("/v1/todo/:id/restore", handlerV1.Idempotent, handlerV1.RestoreTodo)
+	router.POST("/v1/todo/:id/transition", handlerV1.Idempotent, handlerV1.TransitionTodo)
+	router.GET("/v1/todo/:id/transitions", handlerV1.GetTodoTransitions)
+	router.GET("/v1/todo/:id/history", handlerV1.GetTodoHistory)
+	router.POST("/v1/todo/:id/revert", handlerV1.Idempotent, handlerV1.RevertTodo)
+	router.GET("/v1/todo/:id/occurrences", handlerV1.GetTodoOccurrences)
+	router.GET("/v1/todo/:id/subtasks", handlerV1.GetSubtasks)
+	router.GET("/v1/todo/:id/comments", handlerV1.GetTodoComments)
+	router.POST("/v1/todo/:id/comments", handlerV1.Idempotent, handlerV1.CreateTodoComment)
+	router.PUT("/v1/todo/:id/comments/:comment_id", handlerV1.UpdateTodoComment)
+	router.DELETE("/v1/todo/:id/comments/:comment_id", handlerV1.DeleteTodoComment)
+	router.GET("/v1/todo/:id/attachments", handlerV1.GetTodoAttachments)
+	router.POST("/v1/todo/:id/attachments", handlerV1.Idempotent, handlerV1.UploadTodoAttachment)
+	router.GET("/v1/todo/:id/attachments/:attachment_id", handlerV1.DownloadTodoAttachment)
+	router.DELETE("/v1/todo/:id/attachments/:attachment_id", handlerV1.DeleteTodoAttachment)
+
+	router.GET("/v1/lists", handlerV1.GetAllTodoLists)
+	router.POST("/v1/lists", handlerV1.Idempotent, handlerV1.CreateTodoList)
+	router.GET("/v1/lists/:id", handlerV1.GetTodoList)
+	router.PUT("/v1/lists/:id", handlerV1.UpdateTodoList)
+	router.DELETE("/v1/lists/:id", handlerV1.DeleteTodoList)
+	router.POST("/v1/lists/:id/archive", handlerV1.Idempotent, handlerV1.ArchiveTodoList)
+	router.POST("/v1/lists/:id/unarchive", handlerV1.Idempotent, handlerV1.UnarchiveTodoList)
+	router.POST("/v1/lists/:id/restore", handlerV1.Idempotent, handlerV1.RestoreTodoList)
+
+	router.POST("/v1/feeds", handlerV1.Idempotent, handlerV1.CreateFeed)
+	router.GET("/v1/feeds", handlerV1.GetFeed)
+	router.DELETE("/v1/feeds", handlerV1.RevokeFeed)
+	router.GET("/v1/feeds/:token/todos.ics", handlerV1.GetFeedTodos)
+
+	router.GET("/v1/trash", handlerV1.GetTrash)
+	router.DELETE("/v1/trash/todo/:id", handlerV1.PurgeTodo)
+	router.DELETE("/v1/trash/list/:id", handlerV1.PurgeTodoList)
 	// <-- End Todo ---
 
 	url := ginSwagger.URL("swagger/doc.json") // The url pointing to API definition
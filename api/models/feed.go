@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// FeedModel is the calendar feed of a user. token and url are only
+// returned when the feed is created, anyone with the url can read the
+// user's todos until the feed is revoked or created again.
+type FeedModel struct {
+	Token     string     `json:"token,omitempty"`
+	URL       string     `json:"url,omitempty" example:"https://todo.example.com/v1/feeds/9oX1.../todos.ics"`
+	CreatedAt *time.Time `json:"created_at" example:"2021-04-20T09:30:00Z"`
+}
@@ -13,13 +13,12 @@ type Response struct {
 
 // Find query ...
 type FindQueryModel struct {
-	Page     int64  `json:"page,string"`
-	Search   string `json:"search"`
-	Active   bool   `json:"active"`
-	Inactive bool   `josn:"inactive"`
-	Limit    int64  `json:"limit,string"`
-	Sort     string `json:"sort" example:"name|asc"`
-	Lang     string `json:"lang"`
+	Page   int64  `json:"page,string"`
+	Search string `json:"search"`
+	Filter string `json:"filter" example:"status in (todo, in_progress) and due_at < \"2026-11-01\""`
+	Limit  int64  `json:"limit,string"`
+	Sort   string `json:"sort" example:"priority|desc,due_at|asc"`
+	Lang   string `json:"lang"`
 }
 
 type AuthorizationModel struct {
@@ -1,10 +1,330 @@
 package models
 
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/pkg/sortspec"
+	validate "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+var (
+	// TaskStatuses are the accepted values of task_status
+	TaskStatuses = []string{"todo", "in_progress", "done", "cancelled", "reopened"}
+	// Priorities are the accepted values of priority
+	Priorities = []string{"none", "low", "medium", "high", "urgent"}
+	// TodoSort are the keys todos can be sorted by, name and status stand
+	// for task_name and task_status
+	TodoSort = sortspec.NewSchema("task_name", "task_status", "priority", "due_at", "created_at", "updated_at").
+			Alias("name", "task_name").
+			Alias("status", "task_status")
+)
+
 type SingleTodoModel struct {
-	TaskName   string `json:"task_name"`
-	TaskStatus string `json:"task_status"`
+	ID          string     `json:"id"`
+	TaskName    string     `json:"task_name"`
+	Description string     `json:"description"`
+	TaskStatus  string     `json:"task_status" example:"todo"`
+	Priority    string     `json:"priority" example:"none"`
+	DueAt       *time.Time `json:"due_at" example:"2021-05-01T18:00:00Z"`
+	UserID      string     `json:"user_id"`
+	WorkspaceID string     `json:"workspace_id"`
+	CreatedAt   *time.Time `json:"created_at" example:"2021-04-20T09:30:00Z"`
+	UpdatedAt   *time.Time `json:"updated_at" example:"2021-04-20T09:30:00Z"`
+	UpdatedBy   string     `json:"updated_by"`
+	ParentID    string     `json:"parent_id"`
+	ListID      string     `json:"list_id"`
+	// DeletedAt is set on todos in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2021-04-20T09:30:00Z"`
+	// Progress is set on todos with subtasks
+	Progress *SubtaskProgressModel `json:"progress,omitempty"`
+	// Version goes up with every change, it is the ETag of the todo
+	Version int64 `json:"version" example:"1"`
+	// Recurrence is set on recurring todos
+	Recurrence *RecurrenceModel `json:"recurrence,omitempty"`
+	// Reminders are how long before due_at the todo is reminded of
+	Reminders []string `json:"reminders,omitempty" example:"1d,30m"`
+}
+
+// RecurrenceModel repeats a todo, its due_at is an occurrence of rule.
+// rule is a subset of an RFC 5545 RRULE: FREQ of daily, weekly, monthly or
+// yearly with INTERVAL, BYDAY, COUNT, UNTIL and WKST. The rule is expanded
+// in timezone, UTC when empty. start and next_id are set by the server.
+type RecurrenceModel struct {
+	Rule     string     `json:"rule" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Timezone string     `json:"timezone" example:"Europe/Berlin"`
+	Start    *time.Time `json:"start,omitempty" example:"2021-05-03T09:00:00Z"`
+	// NextID is the occurrence created when the todo was completed
+	NextID string `json:"next_id,omitempty"`
+}
+
+func (m RecurrenceModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.Rule, validate.Required, validate.Length(1, 255)),
+		validate.Field(&m.Timezone, validate.Length(0, 64)),
+	)
+}
+
+// OccurrencesModel are the due dates of a recurring todo and the ones
+// following it
+type OccurrencesModel struct {
+	Occurrences []time.Time `json:"occurrences"`
+}
+
+// TodoConflictModel is the error of a write whose If-Match is not the
+// current version, current is the todo as the server has it
+type TodoConflictModel struct {
+	Code    int             `json:"code" default:"0"`
+	Message string          `json:"message"`
+	Reason  string          `json:"reason" example:"VERSION_CONFLICT"`
+	Current SingleTodoModel `json:"current"`
+}
+
+// SubtaskProgressModel counts the direct subtasks of a todo which are not
+// cancelled, e.g. 3/5 done
+type SubtaskProgressModel struct {
+	Done  int64 `json:"done" example:"3"`
+	Total int64 `json:"total" example:"5"`
+}
+
+// SubtaskModel is a todo with its own subtasks, which are only filled in
+// when listing recursively
+type SubtaskModel struct {
+	SingleTodoModel
+	Subtasks []SubtaskModel `json:"subtasks,omitempty"`
+}
+
+type SubtasksModel struct {
+	Subtasks   []SubtaskModel `json:"subtasks"`
+	Count      *int64         `json:"count,omitempty"`
+	NextCursor string         `json:"next_cursor"`
 }
 
+// AllTodoModel is a page of todos, next_cursor continues the listing and is
+// empty on the last page. count is only set when asked for with count=true,
+// like in the other listings.
 type AllTodoModel struct {
-	Todos []SingleTodoModel `json:"todo_items"`
+	Todos      []SingleTodoModel `json:"todo_items"`
+	Count      *int64            `json:"count,omitempty"`
+	NextCursor string            `json:"next_cursor"`
+}
+
+// CreateTodoModel is the body of create and update requests. An empty
+// task_status defaults to "todo" on create and is left as is on update,
+// changing it must follow the workspace's workflow. workspace_id is only
+// read on create; due_at is an RFC 3339 time. parent_id makes the todo a
+// subtask, an update without it moves the todo to the top level. list_id
+// must name a list which is not archived. Completing a todo with a
+// recurrence creates its next occurrence, recurring todos need a due_at.
+// reminders are durations like "30m", "2h" or "1d" before due_at at which
+// the todo is reminded of until it is done or cancelled.
+type CreateTodoModel struct {
+	TaskName    string     `json:"task_name"`
+	Description string     `json:"description"`
+	TaskStatus  string     `json:"task_status" example:"todo"`
+	Priority    string     `json:"priority" example:"none"`
+	DueAt       *time.Time `json:"due_at" example:"2021-05-01T18:00:00Z"`
+	WorkspaceID string     `json:"workspace_id"`
+	ParentID    string     `json:"parent_id"`
+	ListID      string     `json:"list_id"`
+	// Recurrence is read without start and next_id
+	Recurrence *RecurrenceModel `json:"recurrence"`
+	Reminders  []string         `json:"reminders" example:"1h"`
+}
+
+func (m CreateTodoModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.TaskName, validate.Required, validate.Length(1, 255)),
+		validate.Field(&m.Description, validate.Length(0, 4000)),
+		validate.Field(&m.TaskStatus, validate.In(stringsToValues(TaskStatuses)...)),
+		validate.Field(&m.Priority, validate.In(stringsToValues(Priorities)...)),
+		validate.Field(&m.WorkspaceID, validate.Length(0, 64)),
+		validate.Field(&m.Recurrence),
+		validate.Field(&m.Reminders, validate.Length(0, 10), validate.Each(validate.By(checkReminder))),
+	)
+}
+
+func checkReminder(value interface{}) error {
+	_, err := ParseReminder(value.(string))
+	return err
+}
+
+// ParseReminder parses a reminder offset, a positive duration like "90m"
+// or "1h30m" where "d" stands for 24 hours, and returns it in seconds
+func ParseReminder(s string) (int64, error) {
+	var days int64
+	if i := strings.IndexByte(s, 'd'); i > 0 {
+		n, err := strconv.ParseInt(s[:i], 10, 64)
+		if err != nil {
+			return 0, errors.New("must be a duration like 30m, 2h or 1d")
+		}
+		days, s = n, s[i+1:]
+	}
+
+	var d time.Duration
+	if s != "" {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, errors.New("must be a duration like 30m, 2h or 1d")
+		}
+	}
+
+	seconds := days*24*60*60 + int64(d/time.Second)
+	if seconds <= 0 {
+		return 0, errors.New("must be at least a second")
+	}
+
+	return seconds, nil
+}
+
+// FormatReminder formats a reminder offset in seconds as ParseReminder
+// reads it, e.g. 90000 as "1d1h"
+func FormatReminder(seconds int64) string {
+	const day = 24 * 60 * 60
+
+	var s string
+	if seconds >= day {
+		s = strconv.FormatInt(seconds/day, 10) + "d"
+		seconds %= day
+	}
+	if seconds == 0 {
+		return s
+	}
+
+	d := (time.Duration(seconds) * time.Second).String()
+	if strings.HasSuffix(d, "m0s") {
+		d = strings.TrimSuffix(d, "0s")
+	}
+	if strings.HasSuffix(d, "h0m") {
+		d = strings.TrimSuffix(d, "0m")
+	}
+
+	return s + d
+}
+
+// TransitionTodoModel is the body of a status change, recursive moves
+// the subtasks too
+type TransitionTodoModel struct {
+	TaskStatus string `json:"task_status" example:"in_progress"`
+	Comment    string `json:"comment"`
+	Recursive  bool   `json:"recursive"`
+}
+
+func (m TransitionTodoModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.TaskStatus, validate.Required, validate.In(stringsToValues(TaskStatuses)...)),
+		validate.Field(&m.Comment, validate.Length(0, 1000)),
+	)
+}
+
+// TodoTransitionModel is an entry of a todo's status audit log
+type TodoTransitionModel struct {
+	ID         string     `json:"id"`
+	TodoID     string     `json:"todo_id"`
+	From       string     `json:"from" example:"todo"`
+	To         string     `json:"to" example:"in_progress"`
+	ActorID    string     `json:"actor_id"`
+	Comment    string     `json:"comment"`
+	OccurredAt *time.Time `json:"occurred_at" example:"2021-04-20T09:30:00Z"`
+}
+
+type TodoTransitionsModel struct {
+	Transitions []TodoTransitionModel `json:"transitions"`
+	Count       *int64                `json:"count,omitempty"`
+	NextCursor  string                `json:"next_cursor"`
+}
+
+// FieldChangeModel is a field of a todo changed by a revision, from and to
+// are empty for unset values
+type FieldChangeModel struct {
+	Field string `json:"field" example:"task_status"`
+	From  string `json:"from" example:"todo"`
+	To    string `json:"to" example:"in_progress"`
+}
+
+// TodoRevisionModel is an entry of a todo's history, todo is the todo as
+// it was after the revision
+type TodoRevisionModel struct {
+	Revision   int64              `json:"revision" example:"2"`
+	Action     string             `json:"action" example:"updated"`
+	ActorID    string             `json:"actor_id"`
+	Changes    []FieldChangeModel `json:"changes"`
+	Todo       SingleTodoModel    `json:"todo"`
+	RevertedTo int64              `json:"reverted_to,omitempty"`
+	OccurredAt *time.Time         `json:"occurred_at" example:"2021-04-20T09:30:00Z"`
+}
+
+type TodoHistoryModel struct {
+	Revisions  []TodoRevisionModel `json:"revisions"`
+	Count      *int64              `json:"count,omitempty"`
+	NextCursor string              `json:"next_cursor"`
+}
+
+// TodoEventModel is the data of a todo stream event, reminder is set on
+// reminder events
+type TodoEventModel struct {
+	Type       string           `json:"type" example:"created"`
+	Todo       *SingleTodoModel `json:"todo,omitempty"`
+	Reminder   *ReminderModel   `json:"reminder,omitempty"`
+	OccurredAt string           `json:"occurred_at"`
+}
+
+// ReminderModel is a reminder of a todo which became due
+type ReminderModel struct {
+	RemindBefore string    `json:"remind_before" example:"1h"`
+	RemindAt     time.Time `json:"remind_at" example:"2021-05-01T17:00:00Z"`
+}
+
+type BatchCreateTodoModel struct {
+	Todos []CreateTodoModel `json:"todos"`
+}
+
+type UpdateTodoItemModel struct {
+	ID string `json:"id"`
+	CreateTodoModel
+}
+
+func (m UpdateTodoItemModel) Validate() error {
+	err := validate.ValidateStruct(&m,
+		validate.Field(&m.ID, validate.Required),
+	)
+	if err != nil {
+		return err
+	}
+
+	return m.CreateTodoModel.Validate()
+}
+
+type BatchUpdateTodoModel struct {
+	Todos []UpdateTodoItemModel `json:"todos"`
+}
+
+type BatchDeleteTodoModel struct {
+	IDs []string `json:"ids"`
+}
+
+// BatchTodoResultModel is the outcome of one item of a batch request,
+// Status is the http status the item would get as a single request
+type BatchTodoResultModel struct {
+	Index  int              `json:"index"`
+	ID     string           `json:"id,omitempty"`
+	Status int              `json:"status"`
+	Todo   *SingleTodoModel `json:"todo,omitempty"`
+	Error  *ResponseError   `json:"error,omitempty"`
+}
+
+type BatchTodoResponseModel struct {
+	Results   []BatchTodoResultModel `json:"results"`
+	Succeeded int64                  `json:"succeeded"`
+	Failed    int64                  `json:"failed"`
+}
+
+func stringsToValues(values []string) []interface{} {
+	res := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		res = append(res, v)
+	}
+	return res
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// TodoAttachmentModel is a file attached to a todo, its content is
+// downloaded from /v1/todo/{id}/attachments/{attachment_id}
+type TodoAttachmentModel struct {
+	ID          string `json:"id"`
+	TodoID      string `json:"todo_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// SHA256 is the hex encoded hash of the content
+	SHA256     string     `json:"sha256"`
+	UploadedBy string     `json:"uploaded_by"`
+	CreatedAt  *time.Time `json:"created_at" example:"2021-04-20T09:30:00Z"`
+}
+
+type AllTodoAttachmentModel struct {
+	Attachments []TodoAttachmentModel `json:"attachments"`
+	Count       *int64                `json:"count,omitempty"`
+	NextCursor  string                `json:"next_cursor"`
+}
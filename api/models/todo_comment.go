@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	validate "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// TodoCommentModel is a comment on a todo. Replies carry the id of the
+// comment they answer as parent_id.
+type TodoCommentModel struct {
+	ID       string `json:"id"`
+	TodoID   string `json:"todo_id"`
+	ParentID string `json:"parent_id"`
+	AuthorID string `json:"author_id"`
+	Body     string `json:"body"`
+	// Mentions are the usernames mentioned in body as @username
+	Mentions  []string           `json:"mentions"`
+	Edited    bool               `json:"edited"`
+	Edits     []CommentEditModel `json:"edits"`
+	CreatedAt *time.Time         `json:"created_at" example:"2021-04-20T09:30:00Z"`
+	UpdatedAt *time.Time         `json:"updated_at" example:"2021-04-20T09:30:00Z"`
+}
+
+// CommentEditModel is an earlier version of a comment body, EditedAt is
+// when it was replaced
+type CommentEditModel struct {
+	Body     string     `json:"body"`
+	EditedAt *time.Time `json:"edited_at" example:"2021-04-20T09:30:00Z"`
+}
+
+type AllTodoCommentModel struct {
+	Comments   []TodoCommentModel `json:"comments"`
+	Count      *int64             `json:"count,omitempty"`
+	NextCursor string             `json:"next_cursor"`
+}
+
+type CreateTodoCommentModel struct {
+	Body     string `json:"body"`
+	ParentID string `json:"parent_id"`
+}
+
+func (m CreateTodoCommentModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.Body, validate.Required, validate.Length(1, 10000)),
+	)
+}
+
+type UpdateTodoCommentModel struct {
+	Body string `json:"body"`
+}
+
+func (m UpdateTodoCommentModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.Body, validate.Required, validate.Length(1, 10000)),
+	)
+}
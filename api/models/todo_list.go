@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/pkg/sortspec"
+	validate "github.com/go-ozzo/ozzo-validation/v3"
+)
+
+// TodoListSort are the keys lists can be sorted by
+var TodoListSort = sortspec.NewSchema("name", "created_at", "updated_at")
+
+// TodoListModel is a list of todos, e.g. a project. Inbox lists are made
+// by the service and collect the todos of deleted lists.
+type TodoListModel struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	UserID      string     `json:"user_id"`
+	Inbox       bool       `json:"inbox"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at" example:"2021-04-20T09:30:00Z"`
+	CreatedAt   *time.Time `json:"created_at" example:"2021-04-20T09:30:00Z"`
+	UpdatedAt   *time.Time `json:"updated_at" example:"2021-04-20T09:30:00Z"`
+	// DeletedAt is set on lists in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2021-04-20T09:30:00Z"`
+}
+
+type AllTodoListModel struct {
+	Lists      []TodoListModel `json:"lists"`
+	Count      *int64          `json:"count,omitempty"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+type CreateTodoListModel struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (m CreateTodoListModel) Validate() error {
+	return validate.ValidateStruct(&m,
+		validate.Field(&m.Name, validate.Required, validate.Length(1, 255)),
+		validate.Field(&m.Description, validate.Length(0, 4000)),
+	)
+}
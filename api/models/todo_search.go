@@ -0,0 +1,33 @@
+package models
+
+// SearchHighlightModel is an HTML escaped snippet of a matching field with
+// the matched words wrapped in <mark> tags
+type SearchHighlightModel struct {
+	Field   string `json:"field" example:"description"`
+	Snippet string `json:"snippet" example:"…send the <mark>invites</mark> to everyone"`
+}
+
+type SearchHitModel struct {
+	Todo       SingleTodoModel        `json:"todo"`
+	Score      float64                `json:"score" example:"1.25"`
+	Highlights []SearchHighlightModel `json:"highlights"`
+}
+
+// SearchTodosModel lists the matching todos, best first
+type SearchTodosModel struct {
+	Hits       []SearchHitModel `json:"hits"`
+	Count      *int64           `json:"count,omitempty"`
+	NextCursor string           `json:"next_cursor"`
+}
+
+// SuggestionModel completes the typed prefix, kind is todo for a todo
+// title, tag for a #tag of todos or list for a list name
+type SuggestionModel struct {
+	Text  string `json:"text" example:"Plan the quarterly meeting"`
+	Kind  string `json:"kind" example:"todo"`
+	Count int64  `json:"count" example:"2"`
+}
+
+type SuggestionsModel struct {
+	Suggestions []SuggestionModel `json:"suggestions"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TrashItemModel is a deleted todo or list, type tells which of the two is
+// set
+type TrashItemModel struct {
+	Type      string           `json:"type" example:"todo"`
+	Todo      *SingleTodoModel `json:"todo,omitempty"`
+	List      *TodoListModel   `json:"list,omitempty"`
+	DeletedAt *time.Time       `json:"deleted_at" example:"2021-04-20T09:30:00Z"`
+}
+
+// TrashModel lists the trash, most recently deleted first
+type TrashModel struct {
+	Items      []TrashItemModel `json:"items"`
+	Count      *int64           `json:"count,omitempty"`
+	NextCursor string           `json:"next_cursor"`
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/migrate"
+	"github.com/abdukhashimov/go_gin_example/storage/postgres"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up        apply all pending migrations
+  down [n]  roll back the last n migrations (default 1)
+  status    show applied and pending migrations`
+
+func main() {
+	cfg := config.Load()
+	log := logger.New(cfg.LogLevel, "migrate")
+	defer logger.Cleanup(log)
+
+	// a typo shouldn't need a database to be reported
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	steps := 1
+	switch os.Args[1] {
+	case "up", "status":
+	case "down":
+		if len(os.Args) > 2 {
+			var err error
+			if steps, err = strconv.Atoi(os.Args[2]); err != nil || steps < 1 {
+				log.Fatal("invalid number of steps", logger.String("steps", os.Args[2]))
+			}
+		}
+	default:
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		log.Fatal("error while connecting to postgres", logger.Error(err))
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, postgres.Migrations())
+	if err != nil {
+		log.Fatal("error while reading migrations", logger.Error(err))
+	}
+
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "up":
+		done, err := migrator.Up(ctx)
+		for _, m := range done {
+			log.Info("applied", logger.Any("version", m.Version), logger.String("name", m.Name))
+		}
+		if err != nil {
+			log.Fatal("error while migrating up", logger.Error(err))
+		}
+	case "down":
+		done, err := migrator.Down(ctx, steps)
+		for _, m := range done {
+			log.Info("rolled back", logger.Any("version", m.Version), logger.String("name", m.Name))
+		}
+		if err != nil {
+			log.Fatal("error while migrating down", logger.Error(err))
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("error while reading migration status", logger.Error(err))
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.Applied {
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Changed {
+				appliedAt += " (changed since)"
+			}
+			fmt.Printf("%06d  %-40s %s\n", s.Version, s.Name, appliedAt)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	// recurrences are expanded in IANA time zones, also on hosts without
+	// a zoneinfo database
+	_ "time/tzdata"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	"github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/cursor"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/scheduler"
+	"github.com/abdukhashimov/go_gin_example/service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"github.com/abdukhashimov/go_gin_example/storage/blob"
+	"github.com/abdukhashimov/go_gin_example/storage/filestore"
+	"github.com/abdukhashimov/go_gin_example/storage/postgres"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	cfg := config.Load()
+	log := logger.New(cfg.LogLevel, "todo_service")
+	defer logger.Cleanup(log)
+
+	strg, err := newStorage(cfg, log)
+	if err != nil {
+		log.Fatal("error while opening storage", logger.Error(err))
+	}
+
+	blobs, err := blob.NewLocalStore(cfg.BlobStoreDir)
+	if err != nil {
+		log.Fatal("error while opening blob store", logger.Error(err))
+	}
+
+	workflows, err := service.LoadWorkflows(cfg.WorkflowsFile)
+	if err != nil {
+		log.Fatal("error while loading workflows", logger.Error(err))
+	}
+
+	if cfg.CursorSecret == "" {
+		log.Warn("CURSOR_SECRET is not set, page cursors become invalid on restart")
+	}
+	cursors, err := cursor.NewSigner(cfg.CursorSecret)
+	if err != nil {
+		log.Fatal("error while creating cursor signer", logger.Error(err))
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.TodoServicePort))
+	if err != nil {
+		log.Fatal("error while listening", logger.Error(err))
+	}
+
+	todoService, err := service.NewTodoService(cfg, log, strg, blobs, workflows, cursors, scheduler.SystemClock{})
+	if err != nil {
+		log.Fatal("error while creating todo service", logger.Error(err))
+	}
+	if err = todoService.BuildSearchIndex(); err != nil {
+		log.Fatal("error while building search index", logger.Error(err))
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	if cfg.TrashPurgeInterval > 0 {
+		go todoService.RunTrashPurge(purgeCtx,
+			time.Duration(cfg.TrashPurgeInterval)*time.Second,
+			time.Duration(cfg.TrashRetentionDays)*24*time.Hour,
+		)
+	}
+
+	remindersCtx, stopReminders := context.WithCancel(context.Background())
+	go todoService.RunReminders(remindersCtx)
+
+	server := grpc.NewServer()
+	todo_service.RegisterTodoServiceServer(server, todoService)
+
+	go func() {
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		log.Info("shutting down todo_service")
+		stopPurge()
+		stopReminders()
+		server.GracefulStop()
+	}()
+	defer func() {
+		if err := strg.Close(); err != nil {
+			log.Error("error while closing storage", logger.Error(err))
+		}
+	}()
+
+	log.Info("todo_service is running", logger.Int("port", cfg.TodoServicePort), logger.String("storage", cfg.StorageType))
+	if err := server.Serve(lis); err != nil {
+		log.Error("error while serving", logger.Error(err))
+	}
+}
+
+func newStorage(cfg config.Config, log logger.Logger) (storage.StorageI, error) {
+	switch cfg.StorageType {
+	case "memory":
+		return storage.NewStorageMemory(), nil
+	case "file":
+		return storage.NewStorageFile(cfg.FileStoreDir, filestore.Options{
+			SyncOnCommit:     cfg.FileStoreSyncOnCommit,
+			SnapshotInterval: time.Duration(cfg.FileStoreSnapshotInterval) * time.Second,
+			CompactThreshold: cfg.FileStoreCompactThreshold,
+		}, log)
+	case "postgres":
+		db, err := postgres.Connect(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return storage.NewStoragePg(db), nil
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s", cfg.StorageType)
+	}
+}
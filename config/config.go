@@ -12,8 +12,94 @@ type Config struct {
 
 	TodoServiceHost string
 	TodoServicePort int
+
+	CtxTimeout int
+
+	// SSEHeartbeatInterval is how often, in seconds, idle event streams
+	// get a comment line, so proxies and browsers keep them open. Values
+	// below one second fall back to the default.
+	SSEHeartbeatInterval int
+
+	// BatchMaxSize limits the number of items in one batch request
+	BatchMaxSize int
+
+	// IdempotencyKeyTTL is how long, in seconds, the gateway replays the
+	// response of a request to retries with the same Idempotency-Key
+	IdempotencyKeyTTL int
+
+	// StorageType selects the todo_service storage: memory, file or postgres
+	StorageType string
+
+	FileStoreDir              string
+	FileStoreSyncOnCommit     bool
+	FileStoreSnapshotInterval int
+	FileStoreCompactThreshold int64
+
+	PostgresHost            string
+	PostgresPort            int
+	PostgresUser            string
+	PostgresPassword        string
+	PostgresDatabase        string
+	PostgresSSLMode         string
+	PostgresMaxOpenConns    int
+	PostgresMaxIdleConns    int
+	PostgresConnMaxLifetime int
+
+	// WorkflowsFile is a json file with the status workflow of each
+	// workspace, empty means the built-in workflow for all of them
+	WorkflowsFile string
+
+	// TodoMaxDepth is how deep subtasks may be nested, top level todos
+	// are at depth 1
+	TodoMaxDepth int
+
+	// BlobStoreDir is where todo_service keeps attachment contents
+	BlobStoreDir string
+	// AttachmentMaxSize limits the size of one attachment in bytes
+	AttachmentMaxSize int64
+	// AttachmentUserQuota limits the total size of the attachments a user
+	// uploaded in bytes, zero means no limit
+	AttachmentUserQuota int64
+
+	// ImagesDir is served under /images by the gateway
+	ImagesDir string
+	// ImageCacheDir keeps resized variants of images, it may be wiped at
+	// any time
+	ImageCacheDir string
+	// ImageCacheMaxSize limits the resized variants kept in ImageCacheDir
+	// in bytes, the least recently used are removed first
+	ImageCacheMaxSize int64
+	// ImageCacheMaxAge is the max-age, in seconds, images are cached for
+	// by browsers
+	ImageCacheMaxAge int
+	// ImageMaxDimension limits the width and height of resized variants
+	ImageMaxDimension int
+
+	// CursorSecret signs page cursors, empty means a random secret, which
+	// invalidates cursors on every restart of todo_service
+	CursorSecret string
+
+	// TrashRetentionDays is how long deleted todos and lists stay in the
+	// trash before they are purged
+	TrashRetentionDays int
+	// TrashPurgeInterval is how often, in seconds, the trash is purged
+	TrashPurgeInterval int
+
+	// ReminderHooks is a comma separated list of the hooks due reminders
+	// are delivered to: log, sse and webhook
+	ReminderHooks string
+	// ReminderWebhookURL receives reminders as JSON events from the
+	// webhook hook
+	ReminderWebhookURL string
+	// ReminderTimeout limits, in milliseconds, the delivery of a reminder
+	// to each hook
+	ReminderTimeout int
 }
 
+// defaultSSEHeartbeatInterval is used when SSE_HEARTBEAT_INTERVAL is unset
+// or not positive, a ticker can't tick every zero seconds
+const defaultSSEHeartbeatInterval = 15
+
 func Load() Config {
 	config := Config{}
 
@@ -23,6 +109,53 @@ func Load() Config {
 	config.TodoServiceHost = cast.ToString(getOrReturnDefault("TODO_SERICE_HOST", "localhost"))
 	config.TodoServicePort = cast.ToInt(getOrReturnDefault("TODO_SERVICE_PORT", 8001))
 
+	config.CtxTimeout = cast.ToInt(getOrReturnDefault("CTX_TIMEOUT", 7000))
+	config.SSEHeartbeatInterval = cast.ToInt(getOrReturnDefault("SSE_HEARTBEAT_INTERVAL", defaultSSEHeartbeatInterval))
+	if config.SSEHeartbeatInterval <= 0 {
+		config.SSEHeartbeatInterval = defaultSSEHeartbeatInterval
+	}
+	config.BatchMaxSize = cast.ToInt(getOrReturnDefault("BATCH_MAX_SIZE", 1000))
+	config.IdempotencyKeyTTL = cast.ToInt(getOrReturnDefault("IDEMPOTENCY_KEY_TTL", 86400))
+
+	config.StorageType = cast.ToString(getOrReturnDefault("STORAGE_TYPE", "memory"))
+
+	config.FileStoreDir = cast.ToString(getOrReturnDefault("FILE_STORE_DIR", "./data"))
+	config.FileStoreSyncOnCommit = cast.ToBool(getOrReturnDefault("FILE_STORE_SYNC_ON_COMMIT", true))
+	config.FileStoreSnapshotInterval = cast.ToInt(getOrReturnDefault("FILE_STORE_SNAPSHOT_INTERVAL", 300))
+	config.FileStoreCompactThreshold = cast.ToInt64(getOrReturnDefault("FILE_STORE_COMPACT_THRESHOLD", 64<<20))
+
+	config.PostgresHost = cast.ToString(getOrReturnDefault("POSTGRES_HOST", "localhost"))
+	config.PostgresPort = cast.ToInt(getOrReturnDefault("POSTGRES_PORT", 5432))
+	config.PostgresUser = cast.ToString(getOrReturnDefault("POSTGRES_USER", "postgres"))
+	config.PostgresPassword = cast.ToString(getOrReturnDefault("POSTGRES_PASSWORD", "postgres"))
+	config.PostgresDatabase = cast.ToString(getOrReturnDefault("POSTGRES_DATABASE", "todo_service"))
+	config.PostgresSSLMode = cast.ToString(getOrReturnDefault("POSTGRES_SSL_MODE", "disable"))
+	config.PostgresMaxOpenConns = cast.ToInt(getOrReturnDefault("POSTGRES_MAX_OPEN_CONNS", 20))
+	config.PostgresMaxIdleConns = cast.ToInt(getOrReturnDefault("POSTGRES_MAX_IDLE_CONNS", 5))
+	config.PostgresConnMaxLifetime = cast.ToInt(getOrReturnDefault("POSTGRES_CONN_MAX_LIFETIME", 300))
+
+	config.WorkflowsFile = cast.ToString(getOrReturnDefault("WORKFLOWS_FILE", ""))
+	config.TodoMaxDepth = cast.ToInt(getOrReturnDefault("TODO_MAX_DEPTH", 5))
+
+	config.BlobStoreDir = cast.ToString(getOrReturnDefault("BLOB_STORE_DIR", "./data/blobs"))
+	config.AttachmentMaxSize = cast.ToInt64(getOrReturnDefault("ATTACHMENT_MAX_SIZE", 10<<20))
+	config.AttachmentUserQuota = cast.ToInt64(getOrReturnDefault("ATTACHMENT_USER_QUOTA", 100<<20))
+
+	config.ImagesDir = cast.ToString(getOrReturnDefault("IMAGES_DIR", "./static/images"))
+	config.ImageCacheDir = cast.ToString(getOrReturnDefault("IMAGE_CACHE_DIR", "./static/cache"))
+	config.ImageCacheMaxSize = cast.ToInt64(getOrReturnDefault("IMAGE_CACHE_MAX_SIZE", 256<<20))
+	config.ImageCacheMaxAge = cast.ToInt(getOrReturnDefault("IMAGE_CACHE_MAX_AGE", 86400))
+	config.ImageMaxDimension = cast.ToInt(getOrReturnDefault("IMAGE_MAX_DIMENSION", 4096))
+
+	config.CursorSecret = cast.ToString(getOrReturnDefault("CURSOR_SECRET", ""))
+
+	config.TrashRetentionDays = cast.ToInt(getOrReturnDefault("TRASH_RETENTION_DAYS", 30))
+	config.TrashPurgeInterval = cast.ToInt(getOrReturnDefault("TRASH_PURGE_INTERVAL", 3600))
+
+	config.ReminderHooks = cast.ToString(getOrReturnDefault("REMINDER_HOOKS", "log,sse"))
+	config.ReminderWebhookURL = cast.ToString(getOrReturnDefault("REMINDER_WEBHOOK_URL", ""))
+	config.ReminderTimeout = cast.ToInt(getOrReturnDefault("REMINDER_TIMEOUT", 5000))
+
 	return config
 }
 
@@ -13,6 +13,7 @@ import (
 	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 	reflect "reflect"
 )
 
@@ -28,27 +29,360 @@ var File_todo_service_proto protoreflect.FileDescriptor
 var file_todo_service_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x1a, 0x0a, 0x74, 0x6f, 0x64, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x4f,
-	0x0a, 0x0b, 0x54, 0x6f, 0x64, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a,
-	0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x17, 0x2e, 0x74, 0x6f,
+	0x63, 0x65, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
+	0x0a, 0x74, 0x6f, 0x64, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32, 0x96, 0x1a, 0x0a, 0x0b,
+	0x54, 0x6f, 0x64, 0x6f, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0a, 0x43,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64,
+	0x65, 0x6c, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0x00, 0x12, 0x42, 0x0a,
+	0x07, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x1c, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x22,
+	0x00, 0x12, 0x4e, 0x0a, 0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x1e,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x40, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x12,
+	0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54,
+	0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x22, 0x00, 0x12, 0x47, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64,
+	0x6f, 0x12, 0x1f, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x4a, 0x0a, 0x0a,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x1f, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x54,
+	0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x30, 0x01, 0x12, 0x50, 0x0a, 0x0f, 0x42, 0x75, 0x6c, 0x6b,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x17, 0x2e, 0x74, 0x6f,
 	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d,
-	0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0x00, 0x42,
-	0x17, 0x5a, 0x15, 0x67, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x74, 0x6f, 0x64, 0x6f,
-	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6f, 0x64, 0x65, 0x6c, 0x1a, 0x20, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x12, 0x5d, 0x0a, 0x10, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x25,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x5d, 0x0a, 0x10, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x25, 0x2e,
+	0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x50, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x23, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54,
+	0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0x00, 0x12, 0x6c, 0x0a, 0x13, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x28, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54,
+	0x6f, 0x64, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74,
+	0x53, 0x75, 0x62, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x12, 0x21, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x74,
+	0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53,
+	0x75, 0x62, 0x74, 0x61, 0x73, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x4a, 0x0a, 0x0b, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x64, 0x6f,
+	0x12, 0x20, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x22, 0x00, 0x12, 0x45, 0x0a,
+	0x09, 0x50, 0x75, 0x72, 0x67, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x1e, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50, 0x75, 0x72, 0x67, 0x65, 0x54,
+	0x6f, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x22, 0x00, 0x12, 0x66, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f,
+	0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x6f,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64,
+	0x6f, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x27, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x48, 0x0a, 0x0a,
+	0x52, 0x65, 0x76, 0x65, 0x72, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x12, 0x1f, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x76, 0x65, 0x72, 0x74,
+	0x54, 0x6f, 0x64, 0x6f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4d,
+	0x6f, 0x64, 0x65, 0x6c, 0x22, 0x00, 0x12, 0x60, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x63,
+	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x74, 0x6f, 0x64, 0x6f,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4f, 0x63, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x25, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x4f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69,
+	0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0b,
+	0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x20, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f,
+	0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64,
+	0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x5a, 0x0a, 0x0d, 0x4c, 0x69, 0x73, 0x74, 0x54,
+	0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x73, 0x12, 0x22, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f,
+	0x4c, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x74,
+	0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x42, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64,
+	0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x16, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x1a, 0x16, 0x2e,
+	0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64,
+	0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x23, 0x2e, 0x74, 0x6f, 0x64, 0x6f,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54,
+	0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16,
+	0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x0f, 0x41, 0x72, 0x63, 0x68,
+	0x69, 0x76, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x24, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x72, 0x63, 0x68, 0x69,
+	0x76, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x16, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x51, 0x0a, 0x0f, 0x52,
+	0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x24,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x52, 0x65,
+	0x73, 0x74, 0x6f, 0x72, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x22, 0x00, 0x12, 0x4d,
+	0x0a, 0x0d, 0x50, 0x75, 0x72, 0x67, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x12,
+	0x22, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x50,
+	0x75, 0x72, 0x67, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x4e, 0x0a,
+	0x09, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72, 0x61, 0x73, 0x68, 0x12, 0x1e, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72,
+	0x61, 0x73, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x72,
+	0x61, 0x73, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4b, 0x0a,
+	0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x12, 0x19, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x1a, 0x19, 0x2e,
+	0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64,
+	0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x63, 0x0a, 0x10, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x4b, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x1a,
+	0x19, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54,
+	0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x12, 0x55, 0x0a, 0x11,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x43, 0x6f, 0x6d, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74,
+	0x79, 0x22, 0x00, 0x12, 0x57, 0x0a, 0x14, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x6f, 0x64,
+	0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x74, 0x74, 0x61, 0x63,
+	0x68, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x1a, 0x1c, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x28, 0x01, 0x12, 0x63, 0x0a, 0x16,
+	0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74, 0x74, 0x61,
+	0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d,
+	0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x00, 0x30,
+	0x01, 0x12, 0x6c, 0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x28, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f,
+	0x41, 0x74, 0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x29, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74, 0x74, 0x61, 0x63, 0x68,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x58, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74, 0x74,
+	0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x26, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x41, 0x74,
+	0x74, 0x61, 0x63, 0x68, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x54, 0x0a, 0x0b, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12, 0x20, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x54, 0x6f,
+	0x64, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x74, 0x6f, 0x64,
+	0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68,
+	0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
+	0x57, 0x0a, 0x0c, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x12,
+	0x21, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x53,
+	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x53, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x54, 0x6f, 0x64, 0x6f, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f,
+	0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x00, 0x12, 0x49, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x46, 0x65, 0x65,
+	0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0x00, 0x12, 0x4b, 0x0a, 0x0f, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x46, 0x65, 0x65, 0x64, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1e, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12, 0x54,
+	0x0a, 0x10, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x12, 0x25, 0x2e, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x74, 0x6f, 0x64, 0x6f,
+	0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x46, 0x65, 0x65, 0x64, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x22, 0x00, 0x42, 0x17, 0x5a, 0x15, 0x67, 0x65, 0x6e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x74, 0x6f, 0x64, 0x6f, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var file_todo_service_proto_goTypes = []interface{}{
-	(*TodoModel)(nil), // 0: todo_service.TodoModel
+	(*TodoModel)(nil),                   // 0: todo_service.TodoModel
+	(*GetTodoRequest)(nil),              // 1: todo_service.GetTodoRequest
+	(*ListTodosRequest)(nil),            // 2: todo_service.ListTodosRequest
+	(*DeleteTodoRequest)(nil),           // 3: todo_service.DeleteTodoRequest
+	(*WatchTodosRequest)(nil),           // 4: todo_service.WatchTodosRequest
+	(*BatchUpdateTodosRequest)(nil),     // 5: todo_service.BatchUpdateTodosRequest
+	(*BatchDeleteTodosRequest)(nil),     // 6: todo_service.BatchDeleteTodosRequest
+	(*TransitionTodoRequest)(nil),       // 7: todo_service.TransitionTodoRequest
+	(*ListTodoTransitionsRequest)(nil),  // 8: todo_service.ListTodoTransitionsRequest
+	(*ListSubtasksRequest)(nil),         // 9: todo_service.ListSubtasksRequest
+	(*RestoreTodoRequest)(nil),          // 10: todo_service.RestoreTodoRequest
+	(*PurgeTodoRequest)(nil),            // 11: todo_service.PurgeTodoRequest
+	(*ListTodoRevisionsRequest)(nil),    // 12: todo_service.ListTodoRevisionsRequest
+	(*RevertTodoRequest)(nil),           // 13: todo_service.RevertTodoRequest
+	(*ListOccurrencesRequest)(nil),      // 14: todo_service.ListOccurrencesRequest
+	(*TodoList)(nil),                    // 15: todo_service.TodoList
+	(*GetTodoListRequest)(nil),          // 16: todo_service.GetTodoListRequest
+	(*ListTodoListsRequest)(nil),        // 17: todo_service.ListTodoListsRequest
+	(*DeleteTodoListRequest)(nil),       // 18: todo_service.DeleteTodoListRequest
+	(*ArchiveTodoListRequest)(nil),      // 19: todo_service.ArchiveTodoListRequest
+	(*RestoreTodoListRequest)(nil),      // 20: todo_service.RestoreTodoListRequest
+	(*PurgeTodoListRequest)(nil),        // 21: todo_service.PurgeTodoListRequest
+	(*ListTrashRequest)(nil),            // 22: todo_service.ListTrashRequest
+	(*TodoComment)(nil),                 // 23: todo_service.TodoComment
+	(*ListTodoCommentsRequest)(nil),     // 24: todo_service.ListTodoCommentsRequest
+	(*DeleteTodoCommentRequest)(nil),    // 25: todo_service.DeleteTodoCommentRequest
+	(*AttachmentChunk)(nil),             // 26: todo_service.AttachmentChunk
+	(*GetTodoAttachmentRequest)(nil),    // 27: todo_service.GetTodoAttachmentRequest
+	(*ListTodoAttachmentsRequest)(nil),  // 28: todo_service.ListTodoAttachmentsRequest
+	(*SearchTodosRequest)(nil),          // 29: todo_service.SearchTodosRequest
+	(*SuggestTodosRequest)(nil),         // 30: todo_service.SuggestTodosRequest
+	(*FeedTokenRequest)(nil),            // 31: todo_service.FeedTokenRequest
+	(*ResolveFeedTokenRequest)(nil),     // 32: todo_service.ResolveFeedTokenRequest
+	(*ListTodosResponse)(nil),           // 33: todo_service.ListTodosResponse
+	(*emptypb.Empty)(nil),               // 34: google.protobuf.Empty
+	(*TodoEvent)(nil),                   // 35: todo_service.TodoEvent
+	(*BatchTodosResponse)(nil),          // 36: todo_service.BatchTodosResponse
+	(*ListTodoTransitionsResponse)(nil), // 37: todo_service.ListTodoTransitionsResponse
+	(*ListSubtasksResponse)(nil),        // 38: todo_service.ListSubtasksResponse
+	(*ListTodoRevisionsResponse)(nil),   // 39: todo_service.ListTodoRevisionsResponse
+	(*ListOccurrencesResponse)(nil),     // 40: todo_service.ListOccurrencesResponse
+	(*ListTodoListsResponse)(nil),       // 41: todo_service.ListTodoListsResponse
+	(*ListTrashResponse)(nil),           // 42: todo_service.ListTrashResponse
+	(*ListTodoCommentsResponse)(nil),    // 43: todo_service.ListTodoCommentsResponse
+	(*TodoAttachment)(nil),              // 44: todo_service.TodoAttachment
+	(*ListTodoAttachmentsResponse)(nil), // 45: todo_service.ListTodoAttachmentsResponse
+	(*SearchTodosResponse)(nil),         // 46: todo_service.SearchTodosResponse
+	(*SuggestTodosResponse)(nil),        // 47: todo_service.SuggestTodosResponse
+	(*FeedToken)(nil),                   // 48: todo_service.FeedToken
 }
 var file_todo_service_proto_depIdxs = []int32{
-	0, // 0: todo_service.TodoService.CreateTodo:input_type -> todo_service.TodoModel
-	0, // 1: todo_service.TodoService.CreateTodo:output_type -> todo_service.TodoModel
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: todo_service.TodoService.CreateTodo:input_type -> todo_service.TodoModel
+	1,  // 1: todo_service.TodoService.GetTodo:input_type -> todo_service.GetTodoRequest
+	2,  // 2: todo_service.TodoService.ListTodos:input_type -> todo_service.ListTodosRequest
+	0,  // 3: todo_service.TodoService.UpdateTodo:input_type -> todo_service.TodoModel
+	3,  // 4: todo_service.TodoService.DeleteTodo:input_type -> todo_service.DeleteTodoRequest
+	4,  // 5: todo_service.TodoService.WatchTodos:input_type -> todo_service.WatchTodosRequest
+	0,  // 6: todo_service.TodoService.BulkCreateTodos:input_type -> todo_service.TodoModel
+	5,  // 7: todo_service.TodoService.BatchUpdateTodos:input_type -> todo_service.BatchUpdateTodosRequest
+	6,  // 8: todo_service.TodoService.BatchDeleteTodos:input_type -> todo_service.BatchDeleteTodosRequest
+	7,  // 9: todo_service.TodoService.TransitionTodo:input_type -> todo_service.TransitionTodoRequest
+	8,  // 10: todo_service.TodoService.ListTodoTransitions:input_type -> todo_service.ListTodoTransitionsRequest
+	9,  // 11: todo_service.TodoService.ListSubtasks:input_type -> todo_service.ListSubtasksRequest
+	10, // 12: todo_service.TodoService.RestoreTodo:input_type -> todo_service.RestoreTodoRequest
+	11, // 13: todo_service.TodoService.PurgeTodo:input_type -> todo_service.PurgeTodoRequest
+	12, // 14: todo_service.TodoService.ListTodoRevisions:input_type -> todo_service.ListTodoRevisionsRequest
+	13, // 15: todo_service.TodoService.RevertTodo:input_type -> todo_service.RevertTodoRequest
+	14, // 16: todo_service.TodoService.ListOccurrences:input_type -> todo_service.ListOccurrencesRequest
+	15, // 17: todo_service.TodoService.CreateTodoList:input_type -> todo_service.TodoList
+	16, // 18: todo_service.TodoService.GetTodoList:input_type -> todo_service.GetTodoListRequest
+	17, // 19: todo_service.TodoService.ListTodoLists:input_type -> todo_service.ListTodoListsRequest
+	15, // 20: todo_service.TodoService.UpdateTodoList:input_type -> todo_service.TodoList
+	18, // 21: todo_service.TodoService.DeleteTodoList:input_type -> todo_service.DeleteTodoListRequest
+	19, // 22: todo_service.TodoService.ArchiveTodoList:input_type -> todo_service.ArchiveTodoListRequest
+	20, // 23: todo_service.TodoService.RestoreTodoList:input_type -> todo_service.RestoreTodoListRequest
+	21, // 24: todo_service.TodoService.PurgeTodoList:input_type -> todo_service.PurgeTodoListRequest
+	22, // 25: todo_service.TodoService.ListTrash:input_type -> todo_service.ListTrashRequest
+	23, // 26: todo_service.TodoService.CreateTodoComment:input_type -> todo_service.TodoComment
+	24, // 27: todo_service.TodoService.ListTodoComments:input_type -> todo_service.ListTodoCommentsRequest
+	23, // 28: todo_service.TodoService.UpdateTodoComment:input_type -> todo_service.TodoComment
+	25, // 29: todo_service.TodoService.DeleteTodoComment:input_type -> todo_service.DeleteTodoCommentRequest
+	26, // 30: todo_service.TodoService.UploadTodoAttachment:input_type -> todo_service.AttachmentChunk
+	27, // 31: todo_service.TodoService.DownloadTodoAttachment:input_type -> todo_service.GetTodoAttachmentRequest
+	28, // 32: todo_service.TodoService.ListTodoAttachments:input_type -> todo_service.ListTodoAttachmentsRequest
+	27, // 33: todo_service.TodoService.DeleteTodoAttachment:input_type -> todo_service.GetTodoAttachmentRequest
+	29, // 34: todo_service.TodoService.SearchTodos:input_type -> todo_service.SearchTodosRequest
+	30, // 35: todo_service.TodoService.SuggestTodos:input_type -> todo_service.SuggestTodosRequest
+	31, // 36: todo_service.TodoService.CreateFeedToken:input_type -> todo_service.FeedTokenRequest
+	31, // 37: todo_service.TodoService.GetFeedToken:input_type -> todo_service.FeedTokenRequest
+	31, // 38: todo_service.TodoService.RevokeFeedToken:input_type -> todo_service.FeedTokenRequest
+	32, // 39: todo_service.TodoService.ResolveFeedToken:input_type -> todo_service.ResolveFeedTokenRequest
+	0,  // 40: todo_service.TodoService.CreateTodo:output_type -> todo_service.TodoModel
+	0,  // 41: todo_service.TodoService.GetTodo:output_type -> todo_service.TodoModel
+	33, // 42: todo_service.TodoService.ListTodos:output_type -> todo_service.ListTodosResponse
+	0,  // 43: todo_service.TodoService.UpdateTodo:output_type -> todo_service.TodoModel
+	34, // 44: todo_service.TodoService.DeleteTodo:output_type -> google.protobuf.Empty
+	35, // 45: todo_service.TodoService.WatchTodos:output_type -> todo_service.TodoEvent
+	36, // 46: todo_service.TodoService.BulkCreateTodos:output_type -> todo_service.BatchTodosResponse
+	36, // 47: todo_service.TodoService.BatchUpdateTodos:output_type -> todo_service.BatchTodosResponse
+	36, // 48: todo_service.TodoService.BatchDeleteTodos:output_type -> todo_service.BatchTodosResponse
+	0,  // 49: todo_service.TodoService.TransitionTodo:output_type -> todo_service.TodoModel
+	37, // 50: todo_service.TodoService.ListTodoTransitions:output_type -> todo_service.ListTodoTransitionsResponse
+	38, // 51: todo_service.TodoService.ListSubtasks:output_type -> todo_service.ListSubtasksResponse
+	0,  // 52: todo_service.TodoService.RestoreTodo:output_type -> todo_service.TodoModel
+	34, // 53: todo_service.TodoService.PurgeTodo:output_type -> google.protobuf.Empty
+	39, // 54: todo_service.TodoService.ListTodoRevisions:output_type -> todo_service.ListTodoRevisionsResponse
+	0,  // 55: todo_service.TodoService.RevertTodo:output_type -> todo_service.TodoModel
+	40, // 56: todo_service.TodoService.ListOccurrences:output_type -> todo_service.ListOccurrencesResponse
+	15, // 57: todo_service.TodoService.CreateTodoList:output_type -> todo_service.TodoList
+	15, // 58: todo_service.TodoService.GetTodoList:output_type -> todo_service.TodoList
+	41, // 59: todo_service.TodoService.ListTodoLists:output_type -> todo_service.ListTodoListsResponse
+	15, // 60: todo_service.TodoService.UpdateTodoList:output_type -> todo_service.TodoList
+	34, // 61: todo_service.TodoService.DeleteTodoList:output_type -> google.protobuf.Empty
+	15, // 62: todo_service.TodoService.ArchiveTodoList:output_type -> todo_service.TodoList
+	15, // 63: todo_service.TodoService.RestoreTodoList:output_type -> todo_service.TodoList
+	34, // 64: todo_service.TodoService.PurgeTodoList:output_type -> google.protobuf.Empty
+	42, // 65: todo_service.TodoService.ListTrash:output_type -> todo_service.ListTrashResponse
+	23, // 66: todo_service.TodoService.CreateTodoComment:output_type -> todo_service.TodoComment
+	43, // 67: todo_service.TodoService.ListTodoComments:output_type -> todo_service.ListTodoCommentsResponse
+	23, // 68: todo_service.TodoService.UpdateTodoComment:output_type -> todo_service.TodoComment
+	34, // 69: todo_service.TodoService.DeleteTodoComment:output_type -> google.protobuf.Empty
+	44, // 70: todo_service.TodoService.UploadTodoAttachment:output_type -> todo_service.TodoAttachment
+	26, // 71: todo_service.TodoService.DownloadTodoAttachment:output_type -> todo_service.AttachmentChunk
+	45, // 72: todo_service.TodoService.ListTodoAttachments:output_type -> todo_service.ListTodoAttachmentsResponse
+	34, // 73: todo_service.TodoService.DeleteTodoAttachment:output_type -> google.protobuf.Empty
+	46, // 74: todo_service.TodoService.SearchTodos:output_type -> todo_service.SearchTodosResponse
+	47, // 75: todo_service.TodoService.SuggestTodos:output_type -> todo_service.SuggestTodosResponse
+	48, // 76: todo_service.TodoService.CreateFeedToken:output_type -> todo_service.FeedToken
+	48, // 77: todo_service.TodoService.GetFeedToken:output_type -> todo_service.FeedToken
+	34, // 78: todo_service.TodoService.RevokeFeedToken:output_type -> google.protobuf.Empty
+	48, // 79: todo_service.TodoService.ResolveFeedToken:output_type -> todo_service.FeedToken
+	40, // [40:80] is the sub-list for method output_type
+	0,  // [0:40] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_todo_service_proto_init() }
@@ -89,6 +423,45 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type TodoServiceClient interface {
 	CreateTodo(ctx context.Context, in *TodoModel, opts ...grpc.CallOption) (*TodoModel, error)
+	GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*TodoModel, error)
+	ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error)
+	UpdateTodo(ctx context.Context, in *TodoModel, opts ...grpc.CallOption) (*TodoModel, error)
+	DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (TodoService_WatchTodosClient, error)
+	BulkCreateTodos(ctx context.Context, opts ...grpc.CallOption) (TodoService_BulkCreateTodosClient, error)
+	BatchUpdateTodos(ctx context.Context, in *BatchUpdateTodosRequest, opts ...grpc.CallOption) (*BatchTodosResponse, error)
+	BatchDeleteTodos(ctx context.Context, in *BatchDeleteTodosRequest, opts ...grpc.CallOption) (*BatchTodosResponse, error)
+	TransitionTodo(ctx context.Context, in *TransitionTodoRequest, opts ...grpc.CallOption) (*TodoModel, error)
+	ListTodoTransitions(ctx context.Context, in *ListTodoTransitionsRequest, opts ...grpc.CallOption) (*ListTodoTransitionsResponse, error)
+	ListSubtasks(ctx context.Context, in *ListSubtasksRequest, opts ...grpc.CallOption) (*ListSubtasksResponse, error)
+	RestoreTodo(ctx context.Context, in *RestoreTodoRequest, opts ...grpc.CallOption) (*TodoModel, error)
+	PurgeTodo(ctx context.Context, in *PurgeTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTodoRevisions(ctx context.Context, in *ListTodoRevisionsRequest, opts ...grpc.CallOption) (*ListTodoRevisionsResponse, error)
+	RevertTodo(ctx context.Context, in *RevertTodoRequest, opts ...grpc.CallOption) (*TodoModel, error)
+	ListOccurrences(ctx context.Context, in *ListOccurrencesRequest, opts ...grpc.CallOption) (*ListOccurrencesResponse, error)
+	CreateTodoList(ctx context.Context, in *TodoList, opts ...grpc.CallOption) (*TodoList, error)
+	GetTodoList(ctx context.Context, in *GetTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error)
+	UpdateTodoList(ctx context.Context, in *TodoList, opts ...grpc.CallOption) (*TodoList, error)
+	DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ArchiveTodoList(ctx context.Context, in *ArchiveTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	RestoreTodoList(ctx context.Context, in *RestoreTodoListRequest, opts ...grpc.CallOption) (*TodoList, error)
+	PurgeTodoList(ctx context.Context, in *PurgeTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	CreateTodoComment(ctx context.Context, in *TodoComment, opts ...grpc.CallOption) (*TodoComment, error)
+	ListTodoComments(ctx context.Context, in *ListTodoCommentsRequest, opts ...grpc.CallOption) (*ListTodoCommentsResponse, error)
+	UpdateTodoComment(ctx context.Context, in *TodoComment, opts ...grpc.CallOption) (*TodoComment, error)
+	DeleteTodoComment(ctx context.Context, in *DeleteTodoCommentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	UploadTodoAttachment(ctx context.Context, opts ...grpc.CallOption) (TodoService_UploadTodoAttachmentClient, error)
+	DownloadTodoAttachment(ctx context.Context, in *GetTodoAttachmentRequest, opts ...grpc.CallOption) (TodoService_DownloadTodoAttachmentClient, error)
+	ListTodoAttachments(ctx context.Context, in *ListTodoAttachmentsRequest, opts ...grpc.CallOption) (*ListTodoAttachmentsResponse, error)
+	DeleteTodoAttachment(ctx context.Context, in *GetTodoAttachmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchTodosResponse, error)
+	SuggestTodos(ctx context.Context, in *SuggestTodosRequest, opts ...grpc.CallOption) (*SuggestTodosResponse, error)
+	CreateFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error)
+	GetFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error)
+	RevokeFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResolveFeedToken(ctx context.Context, in *ResolveFeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error)
 }
 
 type todoServiceClient struct {
@@ -108,50 +481,1538 @@ func (c *todoServiceClient) CreateTodo(ctx context.Context, in *TodoModel, opts
 	return out, nil
 }
 
-// TodoServiceServer is the server API for TodoService service.
-type TodoServiceServer interface {
-	CreateTodo(context.Context, *TodoModel) (*TodoModel, error)
+func (c *todoServiceClient) GetTodo(ctx context.Context, in *GetTodoRequest, opts ...grpc.CallOption) (*TodoModel, error) {
+	out := new(TodoModel)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/GetTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-// UnimplementedTodoServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedTodoServiceServer struct {
+func (c *todoServiceClient) ListTodos(ctx context.Context, in *ListTodosRequest, opts ...grpc.CallOption) (*ListTodosResponse, error) {
+	out := new(ListTodosResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (*UnimplementedTodoServiceServer) CreateTodo(context.Context, *TodoModel) (*TodoModel, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateTodo not implemented")
+func (c *todoServiceClient) UpdateTodo(ctx context.Context, in *TodoModel, opts ...grpc.CallOption) (*TodoModel, error) {
+	out := new(TodoModel)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/UpdateTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterTodoServiceServer(s *grpc.Server, srv TodoServiceServer) {
-	s.RegisterService(&_TodoService_serviceDesc, srv)
+func (c *todoServiceClient) DeleteTodo(ctx context.Context, in *DeleteTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/DeleteTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func _TodoService_CreateTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(TodoModel)
-	if err := dec(in); err != nil {
+func (c *todoServiceClient) WatchTodos(ctx context.Context, in *WatchTodosRequest, opts ...grpc.CallOption) (TodoService_WatchTodosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TodoService_serviceDesc.Streams[0], "/todo_service.TodoService/WatchTodos", opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(TodoServiceServer).CreateTodo(ctx, in)
+	x := &todoServiceWatchTodosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/todo_service.TodoService/CreateTodo",
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(TodoServiceServer).CreateTodo(ctx, req.(*TodoModel))
+	return x, nil
+}
+
+type TodoService_WatchTodosClient interface {
+	Recv() (*TodoEvent, error)
+	grpc.ClientStream
+}
+
+type todoServiceWatchTodosClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceWatchTodosClient) Recv() (*TodoEvent, error) {
+	m := new(TodoEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
 	}
-	return interceptor(ctx, in, info, handler)
+	return m, nil
 }
 
-var _TodoService_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "todo_service.TodoService",
-	HandlerType: (*TodoServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "CreateTodo",
-			Handler:    _TodoService_CreateTodo_Handler,
+func (c *todoServiceClient) BulkCreateTodos(ctx context.Context, opts ...grpc.CallOption) (TodoService_BulkCreateTodosClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TodoService_serviceDesc.Streams[1], "/todo_service.TodoService/BulkCreateTodos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoServiceBulkCreateTodosClient{stream}
+	return x, nil
+}
+
+type TodoService_BulkCreateTodosClient interface {
+	Send(*TodoModel) error
+	CloseAndRecv() (*BatchTodosResponse, error)
+	grpc.ClientStream
+}
+
+type todoServiceBulkCreateTodosClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceBulkCreateTodosClient) Send(m *TodoModel) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *todoServiceBulkCreateTodosClient) CloseAndRecv() (*BatchTodosResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(BatchTodosResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *todoServiceClient) BatchUpdateTodos(ctx context.Context, in *BatchUpdateTodosRequest, opts ...grpc.CallOption) (*BatchTodosResponse, error) {
+	out := new(BatchTodosResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/BatchUpdateTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) BatchDeleteTodos(ctx context.Context, in *BatchDeleteTodosRequest, opts ...grpc.CallOption) (*BatchTodosResponse, error) {
+	out := new(BatchTodosResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/BatchDeleteTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) TransitionTodo(ctx context.Context, in *TransitionTodoRequest, opts ...grpc.CallOption) (*TodoModel, error) {
+	out := new(TodoModel)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/TransitionTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoTransitions(ctx context.Context, in *ListTodoTransitionsRequest, opts ...grpc.CallOption) (*ListTodoTransitionsResponse, error) {
+	out := new(ListTodoTransitionsResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodoTransitions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListSubtasks(ctx context.Context, in *ListSubtasksRequest, opts ...grpc.CallOption) (*ListSubtasksResponse, error) {
+	out := new(ListSubtasksResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListSubtasks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RestoreTodo(ctx context.Context, in *RestoreTodoRequest, opts ...grpc.CallOption) (*TodoModel, error) {
+	out := new(TodoModel)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/RestoreTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) PurgeTodo(ctx context.Context, in *PurgeTodoRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/PurgeTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoRevisions(ctx context.Context, in *ListTodoRevisionsRequest, opts ...grpc.CallOption) (*ListTodoRevisionsResponse, error) {
+	out := new(ListTodoRevisionsResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodoRevisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RevertTodo(ctx context.Context, in *RevertTodoRequest, opts ...grpc.CallOption) (*TodoModel, error) {
+	out := new(TodoModel)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/RevertTodo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListOccurrences(ctx context.Context, in *ListOccurrencesRequest, opts ...grpc.CallOption) (*ListOccurrencesResponse, error) {
+	out := new(ListOccurrencesResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListOccurrences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateTodoList(ctx context.Context, in *TodoList, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/CreateTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetTodoList(ctx context.Context, in *GetTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/GetTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoLists(ctx context.Context, in *ListTodoListsRequest, opts ...grpc.CallOption) (*ListTodoListsResponse, error) {
+	out := new(ListTodoListsResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodoLists", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateTodoList(ctx context.Context, in *TodoList, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/UpdateTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteTodoList(ctx context.Context, in *DeleteTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/DeleteTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ArchiveTodoList(ctx context.Context, in *ArchiveTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ArchiveTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RestoreTodoList(ctx context.Context, in *RestoreTodoListRequest, opts ...grpc.CallOption) (*TodoList, error) {
+	out := new(TodoList)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/RestoreTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) PurgeTodoList(ctx context.Context, in *PurgeTodoListRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/PurgeTodoList", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTrash(ctx context.Context, in *ListTrashRequest, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTrash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateTodoComment(ctx context.Context, in *TodoComment, opts ...grpc.CallOption) (*TodoComment, error) {
+	out := new(TodoComment)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/CreateTodoComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ListTodoComments(ctx context.Context, in *ListTodoCommentsRequest, opts ...grpc.CallOption) (*ListTodoCommentsResponse, error) {
+	out := new(ListTodoCommentsResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodoComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UpdateTodoComment(ctx context.Context, in *TodoComment, opts ...grpc.CallOption) (*TodoComment, error) {
+	out := new(TodoComment)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/UpdateTodoComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteTodoComment(ctx context.Context, in *DeleteTodoCommentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/DeleteTodoComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) UploadTodoAttachment(ctx context.Context, opts ...grpc.CallOption) (TodoService_UploadTodoAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TodoService_serviceDesc.Streams[2], "/todo_service.TodoService/UploadTodoAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoServiceUploadTodoAttachmentClient{stream}
+	return x, nil
+}
+
+type TodoService_UploadTodoAttachmentClient interface {
+	Send(*AttachmentChunk) error
+	CloseAndRecv() (*TodoAttachment, error)
+	grpc.ClientStream
+}
+
+type todoServiceUploadTodoAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceUploadTodoAttachmentClient) Send(m *AttachmentChunk) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *todoServiceUploadTodoAttachmentClient) CloseAndRecv() (*TodoAttachment, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(TodoAttachment)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *todoServiceClient) DownloadTodoAttachment(ctx context.Context, in *GetTodoAttachmentRequest, opts ...grpc.CallOption) (TodoService_DownloadTodoAttachmentClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TodoService_serviceDesc.Streams[3], "/todo_service.TodoService/DownloadTodoAttachment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &todoServiceDownloadTodoAttachmentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TodoService_DownloadTodoAttachmentClient interface {
+	Recv() (*AttachmentChunk, error)
+	grpc.ClientStream
+}
+
+type todoServiceDownloadTodoAttachmentClient struct {
+	grpc.ClientStream
+}
+
+func (x *todoServiceDownloadTodoAttachmentClient) Recv() (*AttachmentChunk, error) {
+	m := new(AttachmentChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *todoServiceClient) ListTodoAttachments(ctx context.Context, in *ListTodoAttachmentsRequest, opts ...grpc.CallOption) (*ListTodoAttachmentsResponse, error) {
+	out := new(ListTodoAttachmentsResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ListTodoAttachments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) DeleteTodoAttachment(ctx context.Context, in *GetTodoAttachmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/DeleteTodoAttachment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) SearchTodos(ctx context.Context, in *SearchTodosRequest, opts ...grpc.CallOption) (*SearchTodosResponse, error) {
+	out := new(SearchTodosResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/SearchTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) SuggestTodos(ctx context.Context, in *SuggestTodosRequest, opts ...grpc.CallOption) (*SuggestTodosResponse, error) {
+	out := new(SuggestTodosResponse)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/SuggestTodos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) CreateFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error) {
+	out := new(FeedToken)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/CreateFeedToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) GetFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error) {
+	out := new(FeedToken)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/GetFeedToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) RevokeFeedToken(ctx context.Context, in *FeedTokenRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/RevokeFeedToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *todoServiceClient) ResolveFeedToken(ctx context.Context, in *ResolveFeedTokenRequest, opts ...grpc.CallOption) (*FeedToken, error) {
+	out := new(FeedToken)
+	err := c.cc.Invoke(ctx, "/todo_service.TodoService/ResolveFeedToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TodoServiceServer is the server API for TodoService service.
+type TodoServiceServer interface {
+	CreateTodo(context.Context, *TodoModel) (*TodoModel, error)
+	GetTodo(context.Context, *GetTodoRequest) (*TodoModel, error)
+	ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error)
+	UpdateTodo(context.Context, *TodoModel) (*TodoModel, error)
+	DeleteTodo(context.Context, *DeleteTodoRequest) (*emptypb.Empty, error)
+	WatchTodos(*WatchTodosRequest, TodoService_WatchTodosServer) error
+	BulkCreateTodos(TodoService_BulkCreateTodosServer) error
+	BatchUpdateTodos(context.Context, *BatchUpdateTodosRequest) (*BatchTodosResponse, error)
+	BatchDeleteTodos(context.Context, *BatchDeleteTodosRequest) (*BatchTodosResponse, error)
+	TransitionTodo(context.Context, *TransitionTodoRequest) (*TodoModel, error)
+	ListTodoTransitions(context.Context, *ListTodoTransitionsRequest) (*ListTodoTransitionsResponse, error)
+	ListSubtasks(context.Context, *ListSubtasksRequest) (*ListSubtasksResponse, error)
+	RestoreTodo(context.Context, *RestoreTodoRequest) (*TodoModel, error)
+	PurgeTodo(context.Context, *PurgeTodoRequest) (*emptypb.Empty, error)
+	ListTodoRevisions(context.Context, *ListTodoRevisionsRequest) (*ListTodoRevisionsResponse, error)
+	RevertTodo(context.Context, *RevertTodoRequest) (*TodoModel, error)
+	ListOccurrences(context.Context, *ListOccurrencesRequest) (*ListOccurrencesResponse, error)
+	CreateTodoList(context.Context, *TodoList) (*TodoList, error)
+	GetTodoList(context.Context, *GetTodoListRequest) (*TodoList, error)
+	ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error)
+	UpdateTodoList(context.Context, *TodoList) (*TodoList, error)
+	DeleteTodoList(context.Context, *DeleteTodoListRequest) (*emptypb.Empty, error)
+	ArchiveTodoList(context.Context, *ArchiveTodoListRequest) (*TodoList, error)
+	RestoreTodoList(context.Context, *RestoreTodoListRequest) (*TodoList, error)
+	PurgeTodoList(context.Context, *PurgeTodoListRequest) (*emptypb.Empty, error)
+	ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error)
+	CreateTodoComment(context.Context, *TodoComment) (*TodoComment, error)
+	ListTodoComments(context.Context, *ListTodoCommentsRequest) (*ListTodoCommentsResponse, error)
+	UpdateTodoComment(context.Context, *TodoComment) (*TodoComment, error)
+	DeleteTodoComment(context.Context, *DeleteTodoCommentRequest) (*emptypb.Empty, error)
+	UploadTodoAttachment(TodoService_UploadTodoAttachmentServer) error
+	DownloadTodoAttachment(*GetTodoAttachmentRequest, TodoService_DownloadTodoAttachmentServer) error
+	ListTodoAttachments(context.Context, *ListTodoAttachmentsRequest) (*ListTodoAttachmentsResponse, error)
+	DeleteTodoAttachment(context.Context, *GetTodoAttachmentRequest) (*emptypb.Empty, error)
+	SearchTodos(context.Context, *SearchTodosRequest) (*SearchTodosResponse, error)
+	SuggestTodos(context.Context, *SuggestTodosRequest) (*SuggestTodosResponse, error)
+	CreateFeedToken(context.Context, *FeedTokenRequest) (*FeedToken, error)
+	GetFeedToken(context.Context, *FeedTokenRequest) (*FeedToken, error)
+	RevokeFeedToken(context.Context, *FeedTokenRequest) (*emptypb.Empty, error)
+	ResolveFeedToken(context.Context, *ResolveFeedTokenRequest) (*FeedToken, error)
+}
+
+// UnimplementedTodoServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedTodoServiceServer struct {
+}
+
+func (*UnimplementedTodoServiceServer) CreateTodo(context.Context, *TodoModel) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) GetTodo(context.Context, *GetTodoRequest) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodos(context.Context, *ListTodosRequest) (*ListTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) UpdateTodo(context.Context, *TodoModel) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) DeleteTodo(context.Context, *DeleteTodoRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) WatchTodos(*WatchTodosRequest, TodoService_WatchTodosServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) BulkCreateTodos(TodoService_BulkCreateTodosServer) error {
+	return status.Errorf(codes.Unimplemented, "method BulkCreateTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) BatchUpdateTodos(context.Context, *BatchUpdateTodosRequest) (*BatchTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchUpdateTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) BatchDeleteTodos(context.Context, *BatchDeleteTodosRequest) (*BatchTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDeleteTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) TransitionTodo(context.Context, *TransitionTodoRequest) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransitionTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodoTransitions(context.Context, *ListTodoTransitionsRequest) (*ListTodoTransitionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoTransitions not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListSubtasks(context.Context, *ListSubtasksRequest) (*ListSubtasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubtasks not implemented")
+}
+func (*UnimplementedTodoServiceServer) RestoreTodo(context.Context, *RestoreTodoRequest) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) PurgeTodo(context.Context, *PurgeTodoRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodoRevisions(context.Context, *ListTodoRevisionsRequest) (*ListTodoRevisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoRevisions not implemented")
+}
+func (*UnimplementedTodoServiceServer) RevertTodo(context.Context, *RevertTodoRequest) (*TodoModel, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertTodo not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListOccurrences(context.Context, *ListOccurrencesRequest) (*ListOccurrencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOccurrences not implemented")
+}
+func (*UnimplementedTodoServiceServer) CreateTodoList(context.Context, *TodoList) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) GetTodoList(context.Context, *GetTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodoLists(context.Context, *ListTodoListsRequest) (*ListTodoListsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoLists not implemented")
+}
+func (*UnimplementedTodoServiceServer) UpdateTodoList(context.Context, *TodoList) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) DeleteTodoList(context.Context, *DeleteTodoListRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) ArchiveTodoList(context.Context, *ArchiveTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) RestoreTodoList(context.Context, *RestoreTodoListRequest) (*TodoList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) PurgeTodoList(context.Context, *PurgeTodoListRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeTodoList not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTrash(context.Context, *ListTrashRequest) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (*UnimplementedTodoServiceServer) CreateTodoComment(context.Context, *TodoComment) (*TodoComment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTodoComment not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodoComments(context.Context, *ListTodoCommentsRequest) (*ListTodoCommentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoComments not implemented")
+}
+func (*UnimplementedTodoServiceServer) UpdateTodoComment(context.Context, *TodoComment) (*TodoComment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTodoComment not implemented")
+}
+func (*UnimplementedTodoServiceServer) DeleteTodoComment(context.Context, *DeleteTodoCommentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoComment not implemented")
+}
+func (*UnimplementedTodoServiceServer) UploadTodoAttachment(TodoService_UploadTodoAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method UploadTodoAttachment not implemented")
+}
+func (*UnimplementedTodoServiceServer) DownloadTodoAttachment(*GetTodoAttachmentRequest, TodoService_DownloadTodoAttachmentServer) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadTodoAttachment not implemented")
+}
+func (*UnimplementedTodoServiceServer) ListTodoAttachments(context.Context, *ListTodoAttachmentsRequest) (*ListTodoAttachmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTodoAttachments not implemented")
+}
+func (*UnimplementedTodoServiceServer) DeleteTodoAttachment(context.Context, *GetTodoAttachmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTodoAttachment not implemented")
+}
+func (*UnimplementedTodoServiceServer) SearchTodos(context.Context, *SearchTodosRequest) (*SearchTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) SuggestTodos(context.Context, *SuggestTodosRequest) (*SuggestTodosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestTodos not implemented")
+}
+func (*UnimplementedTodoServiceServer) CreateFeedToken(context.Context, *FeedTokenRequest) (*FeedToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateFeedToken not implemented")
+}
+func (*UnimplementedTodoServiceServer) GetFeedToken(context.Context, *FeedTokenRequest) (*FeedToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeedToken not implemented")
+}
+func (*UnimplementedTodoServiceServer) RevokeFeedToken(context.Context, *FeedTokenRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeFeedToken not implemented")
+}
+func (*UnimplementedTodoServiceServer) ResolveFeedToken(context.Context, *ResolveFeedTokenRequest) (*FeedToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResolveFeedToken not implemented")
+}
+
+func RegisterTodoServiceServer(s *grpc.Server, srv TodoServiceServer) {
+	s.RegisterService(&_TodoService_serviceDesc, srv)
+}
+
+func _TodoService_CreateTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoModel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/CreateTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTodo(ctx, req.(*TodoModel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/GetTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTodo(ctx, req.(*GetTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodos(ctx, req.(*ListTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoModel)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/UpdateTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateTodo(ctx, req.(*TodoModel))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/DeleteTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTodo(ctx, req.(*DeleteTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_WatchTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTodosRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).WatchTodos(m, &todoServiceWatchTodosServer{stream})
+}
+
+type TodoService_WatchTodosServer interface {
+	Send(*TodoEvent) error
+	grpc.ServerStream
+}
+
+type todoServiceWatchTodosServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceWatchTodosServer) Send(m *TodoEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TodoService_BulkCreateTodos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TodoServiceServer).BulkCreateTodos(&todoServiceBulkCreateTodosServer{stream})
+}
+
+type TodoService_BulkCreateTodosServer interface {
+	SendAndClose(*BatchTodosResponse) error
+	Recv() (*TodoModel, error)
+	grpc.ServerStream
+}
+
+type todoServiceBulkCreateTodosServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceBulkCreateTodosServer) SendAndClose(m *BatchTodosResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *todoServiceBulkCreateTodosServer) Recv() (*TodoModel, error) {
+	m := new(TodoModel)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TodoService_BatchUpdateTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchUpdateTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).BatchUpdateTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/BatchUpdateTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).BatchUpdateTodos(ctx, req.(*BatchUpdateTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_BatchDeleteTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).BatchDeleteTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/BatchDeleteTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).BatchDeleteTodos(ctx, req.(*BatchDeleteTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_TransitionTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransitionTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).TransitionTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/TransitionTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).TransitionTodo(ctx, req.(*TransitionTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoTransitions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoTransitionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoTransitions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodoTransitions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoTransitions(ctx, req.(*ListTodoTransitionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListSubtasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubtasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListSubtasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListSubtasks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListSubtasks(ctx, req.(*ListSubtasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RestoreTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RestoreTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/RestoreTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RestoreTodo(ctx, req.(*RestoreTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_PurgeTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).PurgeTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/PurgeTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).PurgeTodo(ctx, req.(*PurgeTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoRevisionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodoRevisions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoRevisions(ctx, req.(*ListTodoRevisionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RevertTodo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertTodoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RevertTodo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/RevertTodo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RevertTodo(ctx, req.(*RevertTodoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListOccurrences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOccurrencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListOccurrences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListOccurrences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListOccurrences(ctx, req.(*ListOccurrencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/CreateTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTodoList(ctx, req.(*TodoList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/GetTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetTodoList(ctx, req.(*GetTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoLists_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoListsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoLists(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodoLists",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoLists(ctx, req.(*ListTodoListsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoList)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/UpdateTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateTodoList(ctx, req.(*TodoList))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/DeleteTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTodoList(ctx, req.(*DeleteTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ArchiveTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ArchiveTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ArchiveTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ArchiveTodoList(ctx, req.(*ArchiveTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RestoreTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RestoreTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/RestoreTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RestoreTodoList(ctx, req.(*RestoreTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_PurgeTodoList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeTodoListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).PurgeTodoList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/PurgeTodoList",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).PurgeTodoList(ctx, req.(*PurgeTodoListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTrash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTrash(ctx, req.(*ListTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateTodoComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoComment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateTodoComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/CreateTodoComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateTodoComment(ctx, req.(*TodoComment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ListTodoComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodoComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoComments(ctx, req.(*ListTodoCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UpdateTodoComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TodoComment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).UpdateTodoComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/UpdateTodoComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).UpdateTodoComment(ctx, req.(*TodoComment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTodoComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTodoCommentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTodoComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/DeleteTodoComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTodoComment(ctx, req.(*DeleteTodoCommentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_UploadTodoAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TodoServiceServer).UploadTodoAttachment(&todoServiceUploadTodoAttachmentServer{stream})
+}
+
+type TodoService_UploadTodoAttachmentServer interface {
+	SendAndClose(*TodoAttachment) error
+	Recv() (*AttachmentChunk, error)
+	grpc.ServerStream
+}
+
+type todoServiceUploadTodoAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceUploadTodoAttachmentServer) SendAndClose(m *TodoAttachment) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *todoServiceUploadTodoAttachmentServer) Recv() (*AttachmentChunk, error) {
+	m := new(AttachmentChunk)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TodoService_DownloadTodoAttachment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTodoAttachmentRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TodoServiceServer).DownloadTodoAttachment(m, &todoServiceDownloadTodoAttachmentServer{stream})
+}
+
+type TodoService_DownloadTodoAttachmentServer interface {
+	Send(*AttachmentChunk) error
+	grpc.ServerStream
+}
+
+type todoServiceDownloadTodoAttachmentServer struct {
+	grpc.ServerStream
+}
+
+func (x *todoServiceDownloadTodoAttachmentServer) Send(m *AttachmentChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TodoService_ListTodoAttachments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTodoAttachmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ListTodoAttachments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ListTodoAttachments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ListTodoAttachments(ctx, req.(*ListTodoAttachmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_DeleteTodoAttachment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTodoAttachmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).DeleteTodoAttachment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/DeleteTodoAttachment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).DeleteTodoAttachment(ctx, req.(*GetTodoAttachmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_SearchTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).SearchTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/SearchTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).SearchTodos(ctx, req.(*SearchTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_SuggestTodos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTodosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).SuggestTodos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/SuggestTodos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).SuggestTodos(ctx, req.(*SuggestTodosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_CreateFeedToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).CreateFeedToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/CreateFeedToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).CreateFeedToken(ctx, req.(*FeedTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_GetFeedToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).GetFeedToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/GetFeedToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).GetFeedToken(ctx, req.(*FeedTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_RevokeFeedToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).RevokeFeedToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/RevokeFeedToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).RevokeFeedToken(ctx, req.(*FeedTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TodoService_ResolveFeedToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveFeedTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TodoServiceServer).ResolveFeedToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/todo_service.TodoService/ResolveFeedToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TodoServiceServer).ResolveFeedToken(ctx, req.(*ResolveFeedTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _TodoService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "todo_service.TodoService",
+	HandlerType: (*TodoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTodo",
+			Handler:    _TodoService_CreateTodo_Handler,
+		},
+		{
+			MethodName: "GetTodo",
+			Handler:    _TodoService_GetTodo_Handler,
+		},
+		{
+			MethodName: "ListTodos",
+			Handler:    _TodoService_ListTodos_Handler,
+		},
+		{
+			MethodName: "UpdateTodo",
+			Handler:    _TodoService_UpdateTodo_Handler,
+		},
+		{
+			MethodName: "DeleteTodo",
+			Handler:    _TodoService_DeleteTodo_Handler,
+		},
+		{
+			MethodName: "BatchUpdateTodos",
+			Handler:    _TodoService_BatchUpdateTodos_Handler,
+		},
+		{
+			MethodName: "BatchDeleteTodos",
+			Handler:    _TodoService_BatchDeleteTodos_Handler,
+		},
+		{
+			MethodName: "TransitionTodo",
+			Handler:    _TodoService_TransitionTodo_Handler,
+		},
+		{
+			MethodName: "ListTodoTransitions",
+			Handler:    _TodoService_ListTodoTransitions_Handler,
+		},
+		{
+			MethodName: "ListSubtasks",
+			Handler:    _TodoService_ListSubtasks_Handler,
+		},
+		{
+			MethodName: "RestoreTodo",
+			Handler:    _TodoService_RestoreTodo_Handler,
+		},
+		{
+			MethodName: "PurgeTodo",
+			Handler:    _TodoService_PurgeTodo_Handler,
+		},
+		{
+			MethodName: "ListTodoRevisions",
+			Handler:    _TodoService_ListTodoRevisions_Handler,
+		},
+		{
+			MethodName: "RevertTodo",
+			Handler:    _TodoService_RevertTodo_Handler,
+		},
+		{
+			MethodName: "ListOccurrences",
+			Handler:    _TodoService_ListOccurrences_Handler,
+		},
+		{
+			MethodName: "CreateTodoList",
+			Handler:    _TodoService_CreateTodoList_Handler,
+		},
+		{
+			MethodName: "GetTodoList",
+			Handler:    _TodoService_GetTodoList_Handler,
+		},
+		{
+			MethodName: "ListTodoLists",
+			Handler:    _TodoService_ListTodoLists_Handler,
+		},
+		{
+			MethodName: "UpdateTodoList",
+			Handler:    _TodoService_UpdateTodoList_Handler,
+		},
+		{
+			MethodName: "DeleteTodoList",
+			Handler:    _TodoService_DeleteTodoList_Handler,
+		},
+		{
+			MethodName: "ArchiveTodoList",
+			Handler:    _TodoService_ArchiveTodoList_Handler,
+		},
+		{
+			MethodName: "RestoreTodoList",
+			Handler:    _TodoService_RestoreTodoList_Handler,
+		},
+		{
+			MethodName: "PurgeTodoList",
+			Handler:    _TodoService_PurgeTodoList_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _TodoService_ListTrash_Handler,
+		},
+		{
+			MethodName: "CreateTodoComment",
+			Handler:    _TodoService_CreateTodoComment_Handler,
+		},
+		{
+			MethodName: "ListTodoComments",
+			Handler:    _TodoService_ListTodoComments_Handler,
+		},
+		{
+			MethodName: "UpdateTodoComment",
+			Handler:    _TodoService_UpdateTodoComment_Handler,
+		},
+		{
+			MethodName: "DeleteTodoComment",
+			Handler:    _TodoService_DeleteTodoComment_Handler,
+		},
+		{
+			MethodName: "ListTodoAttachments",
+			Handler:    _TodoService_ListTodoAttachments_Handler,
+		},
+		{
+			MethodName: "DeleteTodoAttachment",
+			Handler:    _TodoService_DeleteTodoAttachment_Handler,
+		},
+		{
+			MethodName: "SearchTodos",
+			Handler:    _TodoService_SearchTodos_Handler,
+		},
+		{
+			MethodName: "SuggestTodos",
+			Handler:    _TodoService_SuggestTodos_Handler,
+		},
+		{
+			MethodName: "CreateFeedToken",
+			Handler:    _TodoService_CreateFeedToken_Handler,
+		},
+		{
+			MethodName: "GetFeedToken",
+			Handler:    _TodoService_GetFeedToken_Handler,
+		},
+		{
+			MethodName: "RevokeFeedToken",
+			Handler:    _TodoService_RevokeFeedToken_Handler,
+		},
+		{
+			MethodName: "ResolveFeedToken",
+			Handler:    _TodoService_ResolveFeedToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTodos",
+			Handler:       _TodoService_WatchTodos_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BulkCreateTodos",
+			Handler:       _TodoService_BulkCreateTodos_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadTodoAttachment",
+			Handler:       _TodoService_UploadTodoAttachment_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadTodoAttachment",
+			Handler:       _TodoService_DownloadTodoAttachment_Handler,
+			ServerStreams: true,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "todo_service.proto",
 }
@@ -0,0 +1,73 @@
+// Package cursor turns page positions into opaque tokens, which clients
+// pass back to continue a listing. Tokens are signed, so a client can't
+// forge a position or change one it was given.
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+const (
+	// macSize is how many bytes of the HMAC-SHA256 a token carries
+	macSize = 16
+	// maxTokenLen limits the tokens Decode accepts
+	maxTokenLen = 4096
+)
+
+// ErrInvalid is returned for tokens which weren't issued by the signer
+var ErrInvalid = errors.New("invalid cursor")
+
+// Signer encodes and decodes tokens with a secret key
+type Signer struct {
+	key []byte
+}
+
+// NewSigner returns a signer with secret as its key. An empty secret gets a
+// random key, so tokens become invalid when the process restarts.
+func NewSigner(secret string) (*Signer, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, sha256.Size)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Signer{key: key}, nil
+}
+
+// Encode returns the token of payload
+func (s *Signer) Encode(payload []byte) string {
+	token := append(append([]byte{}, payload...), s.sum(payload)...)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+// Decode returns the payload of a token, ErrInvalid when it wasn't issued
+// by Encode of a signer with the same key
+func (s *Signer) Decode(token string) ([]byte, error) {
+	if len(token) > maxTokenLen {
+		return nil, ErrInvalid
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < macSize {
+		return nil, ErrInvalid
+	}
+
+	payload, sum := raw[:len(raw)-macSize], raw[len(raw)-macSize:]
+	if !hmac.Equal(sum, s.sum(payload)) {
+		return nil, ErrInvalid
+	}
+
+	return payload, nil
+}
+
+func (s *Signer) sum(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)[:macSize]
+}
@@ -3,6 +3,7 @@ package etc
 import (
 	"database/sql"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/golang/protobuf/ptypes/wrappers"
 )
 
@@ -41,3 +42,60 @@ func DoubleValue(ns sql.NullFloat64) *wrappers.DoubleValue {
 	}
 	return nil
 }
+
+//NullInt64 returns value if it is valid
+func NullInt64(s *wrappers.Int64Value) (ns sql.NullInt64) {
+	if s != nil {
+		ns.Int64 = s.Value
+		ns.Valid = true
+	}
+	return ns
+}
+
+//Int64Value ...
+func Int64Value(ns sql.NullInt64) *wrappers.Int64Value {
+	if ns.Valid {
+		s := wrappers.Int64Value{Value: ns.Int64}
+		return &s
+	}
+	return nil
+}
+
+//NullBool returns value if it is valid
+func NullBool(s *wrappers.BoolValue) (ns sql.NullBool) {
+	if s != nil {
+		ns.Bool = s.Value
+		ns.Valid = true
+	}
+	return ns
+}
+
+//BoolValue ...
+func BoolValue(ns sql.NullBool) *wrappers.BoolValue {
+	if ns.Valid {
+		s := wrappers.BoolValue{Value: ns.Bool}
+		return &s
+	}
+	return nil
+}
+
+//NullTime returns value if it is valid
+func NullTime(s *timestamp.Timestamp) (ns sql.NullTime) {
+	if s != nil {
+		ns.Time = s.AsTime()
+		ns.Valid = true
+	}
+	return ns
+}
+
+//TimestampValue ...
+func TimestampValue(ns sql.NullTime) *timestamp.Timestamp {
+	if ns.Valid {
+		s := timestamp.Timestamp{
+			Seconds: ns.Time.Unix(),
+			Nanos:   int32(ns.Time.Nanosecond()),
+		}
+		return &s
+	}
+	return nil
+}
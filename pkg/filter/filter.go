@@ -0,0 +1,89 @@
+// Package filter parses filter expressions such as
+//
+//	status in (todo, in_progress) and due_at < "2026-11-01"
+//
+// and type checks them against the fields of a resource. Conditions
+// compare a field with =, !=, <, <=, >, >=, in or not in, and combine with
+// and, or, not and parentheses; not binds tighter than and, and tighter
+// than or. Values are bare words or single or double quoted strings, null
+// matches unset nullable fields.
+package filter
+
+import (
+	"fmt"
+	"time"
+)
+
+// Op is the operator of a condition
+type Op string
+
+// Operators of conditions
+const (
+	Eq    Op = "="
+	Ne    Op = "!="
+	Lt    Op = "<"
+	Le    Op = "<="
+	Gt    Op = ">"
+	Ge    Op = ">="
+	In    Op = "in"
+	NotIn Op = "not in"
+)
+
+// Expr is And, Or, Not or *Condition
+type Expr interface {
+	isExpr()
+}
+
+// And matches when all of its operands match
+type And []Expr
+
+// Or matches when any of its operands matches
+type Or []Expr
+
+// Not matches when X doesn't
+type Not struct {
+	X Expr
+}
+
+// Condition compares a field with values, in and not in have one value or
+// more, the other operators exactly one
+type Condition struct {
+	Field  string
+	Op     Op
+	Values []Value
+}
+
+func (And) isExpr()        {}
+func (Or) isExpr()         {}
+func (Not) isExpr()        {}
+func (*Condition) isExpr() {}
+
+// Kind is the type of a value
+type Kind int
+
+// Kinds of values, enum names are turned into their Int
+const (
+	KindString Kind = iota
+	KindInt
+	KindTime
+	KindNull
+)
+
+// Value is a typed value of a condition
+type Value struct {
+	Kind   Kind
+	String string
+	Int    int64
+	Time   time.Time
+}
+
+// Error is a syntax or type error, Pos is the byte offset in the
+// expression it was found at
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid filter at position %d: %s", e.Pos, e.Msg)
+}
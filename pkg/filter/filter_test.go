@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSchema = NewSchema(
+	Field{Name: "status", Type: Enum, Values: map[string]int64{"todo": 1, "in_progress": 2, "done": 3}},
+	Field{Name: "priority", Type: Enum, Values: map[string]int64{"low": 1, "high": 2}, Ordered: true},
+	Field{Name: "name", Type: String, Nullable: true},
+	Field{Name: "user_id", Type: String},
+	Field{Name: "due_at", Type: Time, Nullable: true},
+	Field{Name: "tag", Type: Tag},
+).Alias("title", "name")
+
+func cond(field string, op Op, values ...Value) *Condition {
+	return &Condition{Field: field, Op: op, Values: values}
+}
+
+func str(s string) Value {
+	return Value{Kind: KindString, String: s}
+}
+
+func num(n int64) Value {
+	return Value{Kind: KindInt, Int: n}
+}
+
+func at(t time.Time) Value {
+	return Value{Kind: KindTime, Time: t}
+}
+
+var null = Value{Kind: KindNull}
+
+func TestParsePrecedence(t *testing.T) {
+	a := cond("name", Eq, str("a"))
+	b := cond("name", Eq, str("b"))
+	c := cond("name", Eq, str("c"))
+
+	tests := []struct {
+		expr string
+		want Expr
+	}{
+		{`name = a`, a},
+		{`name = a and name = b and name = c`, And{a, b, c}},
+		{`name = a or name = b or name = c`, Or{a, b, c}},
+		// and binds tighter than or
+		{`name = a or name = b and name = c`, Or{a, And{b, c}}},
+		{`name = a and name = b or name = c`, Or{And{a, b}, c}},
+		// not binds tighter than and
+		{`not name = a and name = b`, And{Not{a}, b}},
+		{`not not name = a`, Not{Not{a}}},
+		{`not (name = a and name = b)`, Not{And{a, b}}},
+		{`name = a and (name = b or name = c)`, And{a, Or{b, c}}},
+		{`((name = a))`, a},
+		// keywords in any case
+		{`name = a AND name = b Or NOT name = c`, Or{And{a, b}, Not{c}}},
+		// not in is an operator, not a negation
+		{`not status not in (done)`, Not{cond("status", NotIn, num(3))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := testSchema.Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		expr string
+		want Expr
+	}{
+		// quoting
+		{`name = "pay rent"`, cond("name", Eq, str("pay rent"))},
+		{`name = 'pay rent'`, cond("name", Eq, str("pay rent"))},
+		{`name = "say \"hi\""`, cond("name", Eq, str(`say "hi"`))},
+		{`name = 'it\'s'`, cond("name", Eq, str("it's"))},
+		{`name = "it's"`, cond("name", Eq, str("it's"))},
+		{`name = "back\\slash"`, cond("name", Eq, str(`back\slash`))},
+		{`name = ""`, cond("name", Eq, str(""))},
+		{`name = "and"`, cond("name", Eq, str("and"))},
+		{`name = "(a, b)"`, cond("name", Eq, str("(a, b)"))},
+		{`name = "null"`, cond("name", Eq, str("null"))},
+		{`name = "héllo wörld"`, cond("name", Eq, str("héllo wörld"))},
+		{`name in ("a b", 'c', d)`, cond("name", In, str("a b"), str("c"), str("d"))},
+		// bare words keep their case
+		{`user_id = User-1`, cond("user_id", Eq, str("User-1"))},
+		{`title != x`, cond("name", Ne, str("x"))},
+		// null
+		{`name = null`, cond("name", Eq, null)},
+		{`due_at != NULL`, cond("due_at", Ne, null)},
+		// enums by name, in any case
+		{`status in (todo, IN_PROGRESS)`, cond("status", In, num(1), num(2))},
+		{`priority >= high`, cond("priority", Ge, num(2))},
+		// times and dates
+		{`due_at < 2026-11-01`, cond("due_at", Lt, at(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)))},
+		{`due_at >= "2026-11-01T09:30:00Z"`, cond("due_at", Ge, at(time.Date(2026, 11, 1, 9, 30, 0, 0, time.UTC)))},
+		// tags with or without #, in any case
+		{`tag = ops`, cond("tag", Eq, str("#ops"))},
+		{`tag != "#Ops"`, cond("tag", Ne, str("#ops"))},
+		{`tag not in (#home-office, errands)`, cond("tag", NotIn, str("#home-office"), str("#errands"))},
+		// spacing
+		{"name=a", cond("name", Eq, str("a"))},
+		{"\tname  !=  x \n", cond("name", Ne, str("x"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := testSchema.Parse(tt.expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	for _, expr := range []string{"", "  ", "\n"} {
+		if got, err := testSchema.Parse(expr); got != nil || err != nil {
+			t.Errorf("Parse(%q) = %v, %v, want nil, nil", expr, got, err)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		// syntax
+		{`name = "pay rent`, 7, "unterminated string"},
+		{`name = 'it\'`, 7, "unterminated string"},
+		{`name ! a`, 5, `"!" must be followed by "="`},
+		{`name = a;`, 8, `unexpected ';'`},
+		{`name a`, 5, "expected an operator"},
+		{`name =`, 6, "expected a value, found the end"},
+		{`= a`, 0, "expected a field"},
+		{`(name = a`, 9, `expected ")", found the end`},
+		{`name = a)`, 8, "expected and, or or the end"},
+		{`name = a name = b`, 9, "expected and, or or the end"},
+		{`name = a and`, 12, "expected a field, found the end"},
+		{`name in a`, 8, `expected "("`},
+		{`name in (a b)`, 11, `expected "," or ")"`},
+		{`name in ()`, 9, "expected a value"},
+		{`name not a`, 9, `expected "in"`},
+		{`not`, 3, "expected a field, found the end"},
+		// types
+		{`owner = a`, 0, `unknown field "owner", allowed: status, priority, name, user_id, due_at, tag`},
+		{`status = blocked`, 9, `unknown status "blocked", allowed: todo, in_progress, done`},
+		{`status < done`, 7, "status can't be compared with <"},
+		{`name > a`, 5, "name can't be compared with >"},
+		{"\tname  <=  x", 7, "name can't be compared with <="},
+		{`due_at in (2026-11-01)`, 7, "due_at can't be compared with in"},
+		{`due_at < tomorrow`, 9, `due_at needs an RFC 3339 time or a date, got "tomorrow"`},
+		{`user_id = null`, 10, "user_id can't be compared with null using ="},
+		{`name in (null)`, 9, "name can't be compared with null using in"},
+		{`due_at < null`, 9, "due_at can't be compared with null using <"},
+		{`tag >= ops`, 4, "tag can't be compared with >="},
+		{`tag = null`, 6, "tag can't be compared with null using ="},
+		{`tag in (ops, "two words")`, 13, `tag needs a #tag of letters, digits, _ and -, got "two words"`},
+		{`tag = ##ops`, 6, `tag needs a #tag`},
+		{`tag = -ops`, 6, `tag needs a #tag`},
+		// limits
+		{strings.Repeat("(", maxDepth) + "name = a" + strings.Repeat(")", maxDepth), maxDepth, "the expression is nested too deep"},
+		{strings.Repeat("not ", maxDepth) + "name = a", 4 * maxDepth, "the expression is nested too deep"},
+		{"name = " + strings.Repeat("a", maxExprLen), maxExprLen, "the expression is too long"},
+	}
+	for _, tt := range tests {
+		name := tt.expr
+		if len(name) > 40 {
+			name = name[:40]
+		}
+		t.Run(name, func(t *testing.T) {
+			_, err := testSchema.Parse(tt.expr)
+			var ferr *Error
+			if !errors.As(err, &ferr) {
+				t.Fatalf("Parse(%q) error = %v, want an *Error", tt.expr, err)
+			}
+			if ferr.Pos != tt.pos || !strings.HasPrefix(ferr.Msg, tt.msg) {
+				t.Errorf("Parse(%q) error at %d: %s\nwant at %d: %s", tt.expr, ferr.Pos, ferr.Msg, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseNestingLimit(t *testing.T) {
+	expr := strings.Repeat("(", maxDepth-1) + "name = a" + strings.Repeat(")", maxDepth-1)
+	if _, err := testSchema.Parse(expr); err != nil {
+		t.Errorf("Parse() of %d levels error = %v", maxDepth-1, err)
+	}
+}
@@ -0,0 +1,311 @@
+package filter
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxExprLen limits the length of an expression in bytes
+	maxExprLen = 4096
+	// maxDepth limits how deep parentheses and not may nest
+	maxDepth = 32
+)
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+// literal is a value as written in the expression
+type literal struct {
+	text string
+	null bool
+	pos  int
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src}
+}
+
+// isWordRune tells if r may be part of a bare word, which covers names,
+// numbers, dates, times and #tags
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.:+#", r)
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.pos += size
+	}
+	if l.pos == len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	switch {
+	case r == '(':
+		l.pos++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case r == ',':
+		l.pos++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case r == '=':
+		l.pos++
+		return token{kind: tokOp, text: "=", pos: start}, nil
+	case r == '!' || r == '<' || r == '>':
+		l.pos++
+		if l.pos < len(l.src) && l.src[l.pos] == '=' {
+			l.pos++
+		} else if r == '!' {
+			return token{}, &Error{Pos: start, Msg: `"!" must be followed by "="`}
+		}
+		return token{kind: tokOp, text: l.src[start:l.pos], pos: start}, nil
+	case r == '"' || r == '\'':
+		return l.string(r)
+	case isWordRune(r):
+		for l.pos < len(l.src) {
+			r, size = utf8.DecodeRuneInString(l.src[l.pos:])
+			if !isWordRune(r) {
+				break
+			}
+			l.pos += size
+		}
+		return token{kind: tokWord, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	return token{}, &Error{Pos: start, Msg: "unexpected " + strconv.QuoteRune(r)}
+}
+
+// string reads a string quoted with q, in which a backslash escapes the
+// next character
+func (l *lexer) string(q rune) (token, error) {
+	start := l.pos
+	l.pos++
+
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		l.pos += size
+		switch {
+		case r == q:
+			return token{kind: tokString, text: sb.String(), pos: start}, nil
+		case r == '\\' && l.pos < len(l.src):
+			r, size = utf8.DecodeRuneInString(l.src[l.pos:])
+			l.pos += size
+		}
+		sb.WriteRune(r)
+	}
+
+	return token{}, &Error{Pos: start, Msg: "unterminated string"}
+}
+
+type parser struct {
+	lex    *lexer
+	schema *Schema
+	tok    token
+	err    error
+}
+
+// next moves to the next token, a lexer error is kept and reported by
+// the parse functions as an unexpected token
+func (p *parser) next() {
+	if p.err != nil {
+		return
+	}
+	p.tok, p.err = p.lex.next()
+}
+
+// keyword tells if the current token is the bare word kw, in any case
+func (p *parser) keyword(kw string) bool {
+	return p.tok.kind == tokWord && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *parser) unexpected(expected string) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.tok.kind == tokEOF {
+		return &Error{Pos: p.tok.pos, Msg: "expected " + expected + ", found the end"}
+	}
+
+	return &Error{Pos: p.tok.pos, Msg: "expected " + expected + ", found " + quote(p.tok.text)}
+}
+
+func (p *parser) parseOr(depth int) (Expr, error) {
+	left, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	or := Or{left}
+	for p.keyword("or") {
+		p.next()
+		right, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, right)
+	}
+	if len(or) == 1 {
+		return left, nil
+	}
+
+	return or, nil
+}
+
+func (p *parser) parseAnd(depth int) (Expr, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+
+	and := And{left}
+	for p.keyword("and") {
+		p.next()
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, right)
+	}
+	if len(and) == 1 {
+		return left, nil
+	}
+
+	return and, nil
+}
+
+func (p *parser) parseUnary(depth int) (Expr, error) {
+	if depth == maxDepth {
+		return nil, &Error{Pos: p.tok.pos, Msg: "the expression is nested too deep"}
+	}
+
+	switch {
+	case p.keyword("not"):
+		p.next()
+		x, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+	case p.tok.kind == tokLParen:
+		p.next()
+		x, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.unexpected(`")"`)
+		}
+		p.next()
+		return x, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Expr, error) {
+	if p.tok.kind != tokWord {
+		return nil, p.unexpected("a field")
+	}
+	field, fieldPos := p.tok.text, p.tok.pos
+	p.next()
+
+	opPos := p.tok.pos
+	var op Op
+	switch {
+	case p.tok.kind == tokOp:
+		op = Op(p.tok.text)
+		p.next()
+	case p.keyword("in"):
+		op = In
+		p.next()
+	case p.keyword("not"):
+		p.next()
+		if !p.keyword("in") {
+			return nil, p.unexpected(`"in"`)
+		}
+		op = NotIn
+		p.next()
+	default:
+		return nil, p.unexpected("an operator")
+	}
+
+	var lits []literal
+	if op == In || op == NotIn {
+		if p.tok.kind != tokLParen {
+			return nil, p.unexpected(`"("`)
+		}
+		p.next()
+		for {
+			lit, err := p.parseLiteral()
+			if err != nil {
+				return nil, err
+			}
+			lits = append(lits, lit)
+			if p.tok.kind != tokComma {
+				break
+			}
+			p.next()
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.unexpected(`"," or ")"`)
+		}
+		p.next()
+	} else {
+		lit, err := p.parseLiteral()
+		if err != nil {
+			return nil, err
+		}
+		lits = append(lits, lit)
+	}
+
+	return p.schema.condition(field, fieldPos, op, opPos, lits)
+}
+
+func (p *parser) parseLiteral() (literal, error) {
+	lit := literal{text: p.tok.text, pos: p.tok.pos}
+	switch p.tok.kind {
+	case tokString:
+	case tokWord:
+		lit.null = strings.EqualFold(lit.text, "null")
+	default:
+		return literal{}, p.unexpected("a value")
+	}
+	p.next()
+
+	return lit, nil
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,164 @@
+package filter
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Type is the type of a field
+type Type int
+
+// Types of fields. A Tag field is the set of #tags of a resource, = and
+// in match resources with any of the tags.
+const (
+	String Type = iota
+	Enum
+	Time
+	Tag
+)
+
+// tagPattern is a tag after the #, which can't start or end with a dash
+var tagPattern = regexp.MustCompile(`^\w(?:[\w-]*\w)?$`)
+
+// Field is a filterable field of a resource
+type Field struct {
+	Name string
+	Type Type
+	// Values maps the names of an Enum to their numbers
+	Values map[string]int64
+	// Ordered allows comparing an Enum with <, <=, > and >= by number
+	Ordered bool
+	// Nullable allows comparing with null using = and !=, a null String
+	// is an empty one
+	Nullable bool
+}
+
+// Schema is the filterable fields of a resource
+type Schema struct {
+	fields map[string]*Field
+	names  []string
+}
+
+// NewSchema returns a schema of fields
+func NewSchema(fields ...Field) *Schema {
+	s := &Schema{
+		fields: make(map[string]*Field, len(fields)),
+		names:  make([]string, 0, len(fields)),
+	}
+	for i := range fields {
+		f := fields[i]
+		s.fields[f.Name] = &f
+		s.names = append(s.names, f.Name)
+	}
+
+	return s
+}
+
+// Alias accepts alias as another name of field
+func (s *Schema) Alias(alias, field string) *Schema {
+	s.fields[alias] = s.fields[field]
+	return s
+}
+
+// Parse parses and type checks expr, an empty expr returns nil
+func (s *Schema) Parse(expr string) (Expr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	if len(expr) > maxExprLen {
+		return nil, &Error{Pos: maxExprLen, Msg: "the expression is too long"}
+	}
+
+	p := &parser{lex: newLexer(expr), schema: s}
+	p.next()
+
+	e, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	// a lexer error leaves an empty token, which reads as the end
+	if p.err != nil || p.tok.kind != tokEOF {
+		return nil, p.unexpected("and, or or the end")
+	}
+
+	return e, nil
+}
+
+// condition type checks the operator and values of a condition on field
+func (s *Schema) condition(field string, fieldPos int, op Op, opPos int, lits []literal) (*Condition, error) {
+	f, ok := s.fields[field]
+	if !ok {
+		return nil, &Error{Pos: fieldPos, Msg: "unknown field " + quote(field) + ", allowed: " + strings.Join(s.names, ", ")}
+	}
+
+	switch op {
+	case Lt, Le, Gt, Ge:
+		if f.Type == String || f.Type == Tag || f.Type == Enum && !f.Ordered {
+			return nil, &Error{Pos: opPos, Msg: f.Name + " can't be compared with " + string(op)}
+		}
+	case In, NotIn:
+		if f.Type == Time {
+			return nil, &Error{Pos: opPos, Msg: f.Name + " can't be compared with " + string(op)}
+		}
+	}
+
+	c := &Condition{Field: f.Name, Op: op, Values: make([]Value, 0, len(lits))}
+	for _, lit := range lits {
+		v, err := f.value(lit, op)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = append(c.Values, v)
+	}
+
+	return c, nil
+}
+
+// value converts a literal to the type of f
+func (f *Field) value(lit literal, op Op) (Value, error) {
+	if lit.null {
+		if !f.Nullable || (op != Eq && op != Ne) {
+			return Value{}, &Error{Pos: lit.pos, Msg: f.Name + " can't be compared with null using " + string(op)}
+		}
+		return Value{Kind: KindNull}, nil
+	}
+
+	switch f.Type {
+	case Enum:
+		n, ok := f.Values[strings.ToLower(lit.text)]
+		if !ok {
+			names := make([]string, 0, len(f.Values))
+			for name := range f.Values {
+				names = append(names, name)
+			}
+			sort.Slice(names, func(i, j int) bool { return f.Values[names[i]] < f.Values[names[j]] })
+			return Value{}, &Error{Pos: lit.pos, Msg: "unknown " + f.Name + " " + quote(lit.text) + ", allowed: " + strings.Join(names, ", ")}
+		}
+		return Value{Kind: KindInt, Int: n}, nil
+	case Time:
+		t, err := parseTime(lit.text)
+		if err != nil {
+			return Value{}, &Error{Pos: lit.pos, Msg: f.Name + " needs an RFC 3339 time or a date, got " + quote(lit.text)}
+		}
+		return Value{Kind: KindTime, Time: t}, nil
+	case Tag:
+		name := strings.TrimPrefix(lit.text, "#")
+		if !tagPattern.MatchString(name) {
+			return Value{}, &Error{Pos: lit.pos, Msg: f.Name + " needs a #tag of letters, digits, _ and -, got " + quote(lit.text)}
+		}
+		return Value{Kind: KindString, String: "#" + strings.ToLower(name)}, nil
+	}
+
+	return Value{Kind: KindString, String: lit.text}, nil
+}
+
+// parseTime accepts RFC 3339 times and dates, which are midnight UTC
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	return time.Parse("2006-01-02", s)
+}
@@ -0,0 +1,169 @@
+// Package ical writes RFC 5545 iCalendar data. It takes care of the
+// content line format, escaping of text values and folding of long lines,
+// callers only pick the components and properties.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// maxLineOctets is the longest a content line may be, without CRLF
+	maxLineOctets = 75
+
+	utcLayout   = "20060102T150405Z"
+	localLayout = "20060102T150405"
+)
+
+// Writer writes content lines. The first error is kept and returned by
+// Flush, later writes are dropped.
+type Writer struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewWriter returns a Writer to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Begin starts a component like VCALENDAR or VTODO
+func (w *Writer) Begin(component string) {
+	w.Line("BEGIN", component)
+}
+
+// End ends a component started with Begin
+func (w *Writer) End(component string) {
+	w.Line("END", component)
+}
+
+// Line writes a property whose value is already encoded. name may carry
+// parameters, e.g. "DTSTART;TZID=Europe/Berlin".
+func (w *Writer) Line(name, value string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.WriteString(Fold(name + ":" + value))
+}
+
+// Text writes a property of type TEXT, which is escaped
+func (w *Writer) Text(name, text string) {
+	w.Line(name, EscapeText(text))
+}
+
+// Time writes a DATE-TIME property in UTC
+func (w *Writer) Time(name string, t time.Time) {
+	w.Line(name, FormatUTC(t))
+}
+
+// LocalTime writes a DATE-TIME property as the wall clock time of t with
+// the TZID of its location, in UTC for UTC times
+func (w *Writer) LocalTime(name string, t time.Time) {
+	if t.Location() == time.UTC {
+		w.Time(name, t)
+		return
+	}
+	w.Line(name+";TZID="+t.Location().String(), t.Format(localLayout))
+}
+
+// Flush writes buffered lines and returns the first error
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// FormatUTC formats t as a UTC DATE-TIME, e.g. 20210501T180000Z
+func FormatUTC(t time.Time) string {
+	return t.UTC().Format(utcLayout)
+}
+
+// FormatDuration formats d as a DURATION value, e.g. -PT1H30M. Durations
+// are given in days, hours, minutes and seconds, weeks are not used.
+func FormatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+
+	seconds := int64(d / time.Second)
+	if days := seconds / 86400; days > 0 {
+		b.WriteString(itoa(days) + "D")
+		seconds %= 86400
+	}
+	if seconds > 0 || b.Len() <= 2 {
+		b.WriteByte('T')
+		if h := seconds / 3600; h > 0 {
+			b.WriteString(itoa(h) + "H")
+		}
+		if m := seconds % 3600 / 60; m > 0 {
+			b.WriteString(itoa(m) + "M")
+		}
+		if s := seconds % 60; s > 0 || seconds == 0 {
+			b.WriteString(itoa(s) + "S")
+		}
+	}
+
+	return b.String()
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// EscapeText escapes a TEXT value, other control characters than line
+// breaks are not allowed in content lines and are dropped
+func EscapeText(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 0x20 && r != '\n' && r != '\r' && r != '\t' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+
+	return textEscaper.Replace(s)
+}
+
+// Fold splits a content line into lines of at most 75 octets, ended with
+// CRLF. Continuation lines start with a space and UTF-8 sequences are not
+// split.
+func Fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if cut == 0 {
+			// invalid UTF-8 without a rune start to cut at
+			cut = limit
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// the leading space counts towards the length
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
@@ -0,0 +1,291 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"pay rent", "pay rent"},
+		{"rent; bills, taxes", `rent\; bills\, taxes`},
+		{`C:\temp`, `C:\\temp`},
+		{`already \n escaped`, `already \\n escaped`},
+		{"two\nlines", `two\nlines`},
+		{"windows\r\nline", `windows\nline`},
+		{"old mac\rline", `old mac\nline`},
+		{"blank\n\nline", `blank\n\nline`},
+		// colons and quotes need no escaping in TEXT
+		{`time: "now"`, `time: "now"`},
+		{"tab\tstays", "tab\tstays"},
+		{"bell\a, nul\x00 and del\x7f", `bell\, nul and del`},
+		{"émoji 🎉", "émoji 🎉"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.text); got != tt.want {
+			t.Errorf("EscapeText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	a74 := strings.Repeat("a", 74)
+	a75 := strings.Repeat("a", 75)
+
+	tests := []struct {
+		name, line, want string
+	}{
+		{"short", "SUMMARY:rent", "SUMMARY:rent\r\n"},
+		{"empty", "", "\r\n"},
+		{"75 octets", a75, a75 + "\r\n"},
+		{"76 octets", a75 + "b", a75 + "\r\n b\r\n"},
+		// continuation lines hold 74 octets after their space
+		{"150 octets", a75 + a74 + "b", a75 + "\r\n " + a74 + "\r\n b\r\n"},
+		{"149 octets", a75 + a74, a75 + "\r\n " + a74 + "\r\n"},
+		// é is 2 octets, it moves to the next line instead of being split
+		{"2 octet rune at the limit", a74 + "é", a74 + "\r\n é\r\n"},
+		{"2 octet rune before the limit", strings.Repeat("a", 73) + "éb", strings.Repeat("a", 73) + "é\r\n b\r\n"},
+		// 🎉 is 4 octets
+		{"4 octet rune across the limit", strings.Repeat("a", 72) + "🎉", strings.Repeat("a", 72) + "\r\n 🎉\r\n"},
+		// invalid UTF-8 has no rune start to cut at
+		{"invalid utf-8", strings.Repeat("\x80", 80), strings.Repeat("\x80", 75) + "\r\n " + strings.Repeat("\x80", 5) + "\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.line); got != tt.want {
+				t.Errorf("Fold(%q)\n= %q\nwant %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// unfold joins folded lines as RFC 5545 3.1 describes
+func unfold(s string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(s, "\r\n"), "\r\n ", "")
+}
+
+func TestFoldLimits(t *testing.T) {
+	pieces := []string{"a", "é", "€", "🎉", ", ", `\;`}
+	for _, piece := range pieces {
+		for n := 0; n <= 200; n++ {
+			line := "DESCRIPTION:" + strings.Repeat(piece, n)
+			folded := Fold(line)
+
+			if !strings.HasSuffix(folded, "\r\n") {
+				t.Fatalf("Fold(%q) does not end with CRLF", line)
+			}
+			physical := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+			for i, l := range physical {
+				if len(l) > maxLineOctets {
+					t.Fatalf("Fold(%q) has a line of %d octets", line, len(l))
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Fatalf("Fold(%q) has a continuation line without a space: %q", line, l)
+				}
+				if !utf8.ValidString(l) {
+					t.Fatalf("Fold(%q) split a rune: %q", line, l)
+				}
+			}
+			if got := unfold(folded); got != line {
+				t.Fatalf("unfolding Fold(%q) = %q", line, got)
+			}
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{time.Second, "PT1S"},
+		{90 * time.Minute, "PT1H30M"},
+		{-90 * time.Minute, "-PT1H30M"},
+		{time.Hour + 5*time.Second, "PT1H5S"},
+		{24 * time.Hour, "P1D"},
+		{-7 * 24 * time.Hour, "-P7D"},
+		{26*time.Hour + 30*time.Second, "P1DT2H30S"},
+		// fractions of seconds are dropped
+		{1500 * time.Millisecond, "PT1S"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatOffset(t *testing.T) {
+	tests := []struct {
+		offset int
+		want   string
+	}{
+		{0, "+0000"},
+		{3600, "+0100"},
+		{-5 * 3600, "-0500"},
+		{5*3600 + 45*60, "+0545"},
+		{-(3*3600 + 30*60), "-0330"},
+		// local mean times before standard time had seconds
+		{-(44*60 + 30), "-004430"},
+	}
+	for _, tt := range tests {
+		if got := formatOffset(tt.offset); got != tt.want {
+			t.Errorf("formatOffset(%d) = %q, want %q", tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	due := time.Date(2026, 11, 1, 9, 30, 0, 0, berlin)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Begin("VTODO")
+	w.Text("SUMMARY", "rent; bills")
+	w.Time("DTSTAMP", due)
+	w.LocalTime("DUE", due)
+	w.LocalTime("DTSTART", due.UTC())
+	w.Line("TRIGGER", FormatDuration(-time.Hour))
+	w.Text("DESCRIPTION", strings.Repeat("x", 70))
+	w.End("VTODO")
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "BEGIN:VTODO\r\n" +
+		"SUMMARY:rent\\; bills\r\n" +
+		"DTSTAMP:20261101T083000Z\r\n" +
+		"DUE;TZID=Europe/Berlin:20261101T093000\r\n" +
+		"DTSTART:20261101T083000Z\r\n" +
+		"TRIGGER:-PT1H\r\n" +
+		"DESCRIPTION:" + strings.Repeat("x", 63) + "\r\n " + strings.Repeat("x", 7) + "\r\n" +
+		"END:VTODO\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func TestWriterError(t *testing.T) {
+	failure := errors.New("disk full")
+	w := NewWriter(failingWriter{failure})
+
+	// more than the buffer holds, so writing fails before the flush
+	for i := 0; i < 1000; i++ {
+		w.Text("DESCRIPTION", "rent")
+	}
+	if err := w.Flush(); !errors.Is(err, failure) {
+		t.Errorf("Flush() error = %v, want %v", err, failure)
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  time.Time
+		lines []string
+	}{
+		{
+			name: "Europe/Berlin",
+			from: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+			lines: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Europe/Berlin",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20250330T020000",
+				"TZOFFSETFROM:+0100",
+				"TZOFFSETTO:+0200",
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+				"TZNAME:CEST",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20251026T030000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0100",
+				"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+				"TZNAME:CET",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			name: "America/New_York",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			lines: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:America/New_York",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20250309T020000",
+				"TZOFFSETFROM:-0500",
+				"TZOFFSETTO:-0400",
+				"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU",
+				"TZNAME:EDT",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20251102T020000",
+				"TZOFFSETFROM:-0400",
+				"TZOFFSETTO:-0500",
+				"RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=1SU",
+				"TZNAME:EST",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			// without daylight saving time
+			name: "Asia/Tokyo",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			lines: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Asia/Tokyo",
+				"BEGIN:STANDARD",
+				"DTSTART:19700101T000000",
+				"TZOFFSETFROM:+0900",
+				"TZOFFSETTO:+0900",
+				"TZNAME:JST",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			w := NewWriter(&buf)
+			w.Timezone(loc, tt.from)
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			want := strings.Join(tt.lines, "\r\n") + "\r\n"
+			if got := buf.String(); got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// transition is a change of the UTC offset of a location
+type transition struct {
+	at       time.Time
+	from, to int
+}
+
+// Timezone writes the VTIMEZONE of loc, which times of LocalTime refer to
+// by TZID, for times from from on. Go doesn't expose the rules of a
+// location, so they are derived from the offset changes in the year before
+// from: two changes are written as yearly rules, like the last Sunday of
+// March and October, others as they are.
+func (w *Writer) Timezone(loc *time.Location, from time.Time) {
+	year := from.Year() - 1
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	name, offset := start.Zone()
+
+	w.Begin("VTIMEZONE")
+	w.Line("TZID", loc.String())
+
+	transitions := transitionsIn(loc, year)
+	if len(transitions) != 2 {
+		w.Begin("STANDARD")
+		w.Line("DTSTART", "19700101T000000")
+		w.Line("TZOFFSETFROM", formatOffset(offset))
+		w.Line("TZOFFSETTO", formatOffset(offset))
+		w.Text("TZNAME", name)
+		w.End("STANDARD")
+	}
+
+	for _, t := range transitions {
+		component := "STANDARD"
+		if t.to > t.from {
+			component = "DAYLIGHT"
+		}
+		// DTSTART is the wall clock time the change happens at, before it
+		local := t.at.In(time.FixedZone("", t.from))
+		zone, _ := t.at.In(loc).Zone()
+
+		w.Begin(component)
+		w.Line("DTSTART", local.Format(localLayout))
+		w.Line("TZOFFSETFROM", formatOffset(t.from))
+		w.Line("TZOFFSETTO", formatOffset(t.to))
+		if len(transitions) == 2 {
+			w.Line("RRULE", yearlyRule(local))
+		}
+		w.Text("TZNAME", zone)
+		w.End(component)
+	}
+
+	w.End("VTIMEZONE")
+}
+
+// transitionsIn finds the offset changes of loc in year, days are checked
+// one by one and a change is searched for to the second
+func transitionsIn(loc *time.Location, year int) []transition {
+	var transitions []transition
+
+	day := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(1, 0, 0)
+	_, offset := day.In(loc).Zone()
+	for day.Before(end) {
+		next := day.Add(24 * time.Hour)
+		if _, o := next.In(loc).Zone(); o != offset {
+			lo, hi := day, next
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, m := mid.In(loc).Zone(); m == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			transitions = append(transitions, transition{at: hi, from: offset, to: o})
+			offset = o
+		}
+		day = next
+	}
+
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].at.Before(transitions[j].at) })
+
+	return transitions
+}
+
+// yearlyRule returns a yearly rule which picks the weekday of t in its
+// month, counted from the end when it is in the last week
+func yearlyRule(t time.Time) string {
+	weekday := [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}[t.Weekday()]
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	n := (t.Day()-1)/7 + 1
+	if t.Day()+7 > daysInMonth {
+		n = -1
+	}
+
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", t.Month(), n, weekday)
+}
+
+// formatOffset formats a UTC offset in seconds as +HHMM, or +HHMMSS when
+// it has seconds
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	s := fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+	if offset%60 != 0 {
+		s += fmt.Sprintf("%02d", offset%60)
+	}
+
+	return s
+}
@@ -0,0 +1,42 @@
+// Package idempotency remembers the responses of requests by a key the
+// client sends along, so a retried request gets the first response back
+// instead of being executed again.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrMismatch is returned when a key is used again for another request
+	ErrMismatch = errors.New("idempotency key was used for another request")
+	// ErrInFlight is returned when the request holding a key did not finish
+	// before the context of a retry was done
+	ErrInFlight = errors.New("request with the idempotency key is still in progress")
+)
+
+// Response is a stored response, it must not be changed once stored
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Store keeps responses by key. A request claims its key with Begin and
+// hands it back with Finish or Abort.
+type Store interface {
+	// Begin claims key for a request identified by hash. It returns the
+	// stored response when a request with key and hash already finished,
+	// waiting for one still in progress until ctx is done, and ErrMismatch
+	// when key was claimed with another hash. A nil response and error
+	// mean the caller holds the key.
+	Begin(ctx context.Context, key, hash string) (*Response, error)
+	// Finish stores the response of the request holding key for ttl
+	Finish(key string, res *Response, ttl time.Duration) error
+	// Abort releases key without a response, the next request with it is
+	// executed
+	Abort(key string) error
+}
@@ -0,0 +1,107 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often the memory store drops expired responses
+const pruneInterval = time.Minute
+
+type entry struct {
+	hash string
+	// done is closed when the request holding the key finishes or aborts
+	done      chan struct{}
+	res       *Response
+	expiresAt time.Time
+}
+
+// MemoryStore keeps responses in process memory, they are lost on restart
+// and not shared between gateway instances
+type MemoryStore struct {
+	mu       sync.Mutex
+	entries  map[string]*entry
+	prunedAt time.Time
+}
+
+// NewMemoryStore ...
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		entries: make(map[string]*entry),
+	}
+}
+
+func (s *MemoryStore) Begin(ctx context.Context, key, hash string) (*Response, error) {
+	for {
+		s.mu.Lock()
+		now := time.Now()
+		s.prune(now)
+
+		e, ok := s.entries[key]
+		if ok && e.res != nil && now.After(e.expiresAt) {
+			delete(s.entries, key)
+			ok = false
+		}
+		if !ok {
+			s.entries[key] = &entry{hash: hash, done: make(chan struct{})}
+			s.mu.Unlock()
+			return nil, nil
+		}
+		if e.hash != hash {
+			s.mu.Unlock()
+			return nil, ErrMismatch
+		}
+		if e.res != nil {
+			s.mu.Unlock()
+			return e.res, nil
+		}
+		s.mu.Unlock()
+
+		// the key is claimed again if the request holding it aborts
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, ErrInFlight
+		}
+	}
+}
+
+func (s *MemoryStore) Finish(key string, res *Response, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.res == nil {
+		e.res = res
+		e.expiresAt = time.Now().Add(ttl)
+		close(e.done)
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Abort(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.res == nil {
+		delete(s.entries, key)
+		close(e.done)
+	}
+
+	return nil
+}
+
+// prune drops expired responses, at most once every pruneInterval
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.prunedAt) < pruneInterval {
+		return
+	}
+	s.prunedAt = now
+
+	for key, e := range s.entries {
+		if e.res != nil && now.After(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var created = &Response{
+	Status: http.StatusCreated,
+	Header: http.Header{"Content-Type": {"application/json"}},
+	Body:   []byte(`{"id":"1"}`),
+}
+
+// claim begins key for hash and fails unless the caller holds it
+func claim(t *testing.T, s Store, key, hash string) {
+	t.Helper()
+
+	res, err := s.Begin(context.Background(), key, hash)
+	if res != nil || err != nil {
+		t.Fatalf("Begin(%q) = %v, %v, want the key claimed", key, res, err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+	if err := s.Finish("key", created, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.Begin(context.Background(), "key", "hash")
+	if err != nil || !reflect.DeepEqual(res, created) {
+		t.Errorf("Begin() = %v, %v, want the stored response", res, err)
+	}
+
+	// keys don't share responses
+	claim(t, s, "other", "hash")
+}
+
+func TestMismatch(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+
+	// while in flight and after it finished
+	if _, err := s.Begin(context.Background(), "key", "other"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Begin() in flight error = %v, want ErrMismatch", err)
+	}
+	if err := s.Finish("key", created, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Begin(context.Background(), "key", "other"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("Begin() error = %v, want ErrMismatch", err)
+	}
+}
+
+func TestInFlight(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := s.Begin(ctx, "key", "hash"); !errors.Is(err, ErrInFlight) {
+		t.Errorf("Begin() error = %v, want ErrInFlight", err)
+	}
+}
+
+func TestRetryWaitsForFirst(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+
+	type result struct {
+		res *Response
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		res, err := s.Begin(context.Background(), "key", "hash")
+		done <- result{res, err}
+	}()
+
+	select {
+	case r := <-done:
+		t.Fatalf("retry returned %v, %v before the first request finished", r.res, r.err)
+	case <-time.After(10 * time.Millisecond):
+	}
+	if err := s.Finish("key", created, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if r := <-done; r.err != nil || !reflect.DeepEqual(r.res, created) {
+		t.Errorf("retry = %v, %v, want the response of the first request", r.res, r.err)
+	}
+}
+
+func TestAbort(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+
+	// a waiting retry takes over the key
+	done := make(chan error)
+	go func() {
+		res, err := s.Begin(context.Background(), "key", "hash")
+		if err == nil && res != nil {
+			err = errors.New("got a response of an aborted request")
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if err := s.Abort("key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// an aborted key takes another request
+	if err := s.Abort("key"); err != nil {
+		t.Fatal(err)
+	}
+	claim(t, s, "key", "other")
+}
+
+func TestExpiry(t *testing.T) {
+	s := NewMemoryStore()
+	claim(t, s, "key", "hash")
+	if err := s.Finish("key", created, time.Nanosecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+
+	// an expired key can be used for another request
+	claim(t, s, "key", "other")
+}
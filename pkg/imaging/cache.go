@@ -0,0 +1,220 @@
+package imaging
+
+import (
+	"container/list"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cache keeps generated variants as files in a directory. Keys should
+// change with the content of a variant, entries are never invalidated.
+// Once the variants take more than the size budget, the least recently
+// used are removed.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu sync.Mutex
+	// generating holds a lock per key being generated, so concurrent
+	// requests for a missing variant generate it once
+	generating map[string]*keyLock
+	// entries index the elements of lru by key, the most recently used
+	// variant is at the front
+	entries map[string]*list.Element
+	lru     *list.List
+	size    int64
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+type cacheEntry struct {
+	key  string
+	size int64
+}
+
+// NewCache returns a cache in dir, which is created on the first write,
+// keeping at most maxSize bytes of variants, zero means no limit.
+// Variants left in dir by an earlier run are kept, the oldest are the
+// first to go.
+func NewCache(dir string, maxSize int64) *Cache {
+	c := &Cache{
+		dir:        dir,
+		maxSize:    maxSize,
+		generating: make(map[string]*keyLock),
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	c.load()
+
+	return c
+}
+
+// load indexes the variants already in dir, a missing or unreadable dir
+// leaves the cache empty
+func (c *Cache) load() {
+	type file struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+
+	shards, _ := filepath.Glob(filepath.Join(c.dir, "??", "*"))
+	for _, path := range shards {
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || len(info.Name()) < 2 ||
+			filepath.Base(filepath.Dir(path)) != info.Name()[:2] {
+			continue
+		}
+		files = append(files, file{info.Name(), info.Size(), info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range files {
+		c.add(f.key, f.size)
+	}
+}
+
+// Open returns the file holding the variant stored under key, writing it
+// with gen first if it is not cached yet. key must be safe to use as a
+// file name. The file stays readable until it is closed, even if the
+// variant is evicted in the meantime.
+func (c *Cache) Open(key string, gen func(w io.Writer) error) (*os.File, error) {
+	if f := c.open(key); f != nil {
+		return f, nil
+	}
+
+	unlock := c.lock(key)
+	defer unlock()
+
+	// another request may have generated it while we waited
+	if f := c.open(key); f != nil {
+		return f, nil
+	}
+
+	if err := os.MkdirAll(filepath.Join(c.dir, "tmp"), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Join(c.dir, "tmp"), "variant-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = gen(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path := c.path(key)
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	// opened before it is indexed, so it can't be evicted under us
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.add(key, info.Size())
+	c.mu.Unlock()
+
+	return f, nil
+}
+
+// open opens the cached variant of key and marks it as recently used, it
+// returns nil if there is none
+func (c *Cache) open(key string) *os.File {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	f, err := os.Open(c.path(key))
+	if err != nil {
+		// the dir was wiped, the variant is generated again
+		c.remove(e)
+		return nil
+	}
+	c.lru.MoveToFront(e)
+
+	return f
+}
+
+// add indexes the variant of key and evicts the least recently used
+// variants over the budget, c.mu must be held
+func (c *Cache) add(key string, size int64) {
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, size: size})
+	c.size += size
+
+	for c.maxSize > 0 && c.size > c.maxSize {
+		e := c.lru.Back()
+		c.remove(e)
+		os.Remove(c.path(e.Value.(*cacheEntry).key))
+	}
+}
+
+// remove drops e from the index, c.mu must be held
+func (c *Cache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key)
+}
+
+func (c *Cache) lock(key string) (unlock func()) {
+	c.mu.Lock()
+	l, ok := c.generating[key]
+	if !ok {
+		l = &keyLock{}
+		c.generating[key] = l
+	}
+	l.refs++
+	c.mu.Unlock()
+
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+
+		c.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(c.generating, key)
+		}
+		c.mu.Unlock()
+	}
+}
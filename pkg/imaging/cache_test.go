@@ -0,0 +1,199 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// keys are file names, their first two characters shard the directory
+var keys = []string{"aa01", "bb02", "cc03", "dd04"}
+
+// variant returns a gen writing size bytes, counting its calls in calls
+func variant(size int, calls *int) func(io.Writer) error {
+	return func(w io.Writer) error {
+		*calls++
+		_, err := w.Write(bytes.Repeat([]byte("x"), size))
+		return err
+	}
+}
+
+// read opens key in c and returns its content
+func read(t *testing.T, c *Cache, key string, gen func(io.Writer) error) string {
+	t.Helper()
+
+	f, err := c.Open(key, gen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+// expectCached checks which keys have a variant on disk
+func expectCached(t *testing.T, c *Cache, want ...string) {
+	t.Helper()
+
+	wanted := map[string]bool{}
+	for _, key := range want {
+		wanted[key] = true
+	}
+	for _, key := range keys {
+		_, err := os.Stat(c.path(key))
+		if cached := err == nil; cached != wanted[key] {
+			t.Errorf("%s cached = %v, want %v", key, cached, wanted[key])
+		}
+	}
+}
+
+func TestCacheGeneratesOnce(t *testing.T) {
+	c := NewCache(t.TempDir(), 0)
+	var calls int
+
+	for i := 0; i < 3; i++ {
+		if got := read(t, c, keys[0], variant(10, &calls)); got != strings.Repeat("x", 10) {
+			t.Fatalf("read %q", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("generated %d times, want once", calls)
+	}
+
+	// failures are not cached
+	if _, err := c.Open(keys[1], func(io.Writer) error { return errors.New("broken") }); err == nil {
+		t.Error("Open() with a failing gen succeeded")
+	}
+	expectCached(t, c, keys[0])
+}
+
+func TestCacheConcurrentGeneratesOnce(t *testing.T) {
+	c := NewCache(t.TempDir(), 0)
+
+	var mu sync.Mutex
+	calls := 0
+	gen := func(w io.Writer) error {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		_, err := w.Write([]byte("variant"))
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f, err := c.Open(keys[0], gen)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			f.Close()
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("generated %d times, want once", calls)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewCache(t.TempDir(), 30)
+	var calls int
+
+	read(t, c, keys[0], variant(10, &calls))
+	read(t, c, keys[1], variant(10, &calls))
+	read(t, c, keys[2], variant(10, &calls))
+	// keys[0] is used again, keys[1] is the least recently used now
+	read(t, c, keys[0], variant(10, &calls))
+	read(t, c, keys[3], variant(10, &calls))
+
+	expectCached(t, c, keys[0], keys[2], keys[3])
+	if c.size != 30 || c.lru.Len() != 3 {
+		t.Errorf("%d bytes in %d variants, want 30 in 3", c.size, c.lru.Len())
+	}
+
+	// an evicted variant is generated again
+	read(t, c, keys[1], variant(10, &calls))
+	if calls != 5 {
+		t.Errorf("generated %d times, want 5", calls)
+	}
+	expectCached(t, c, keys[0], keys[1], keys[3])
+}
+
+func TestCacheVariantOverBudget(t *testing.T) {
+	c := NewCache(t.TempDir(), 30)
+	var calls int
+
+	read(t, c, keys[0], variant(10, &calls))
+	f, err := c.Open(keys[1], variant(40, &calls))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// served once and not kept, nor is anything else
+	expectCached(t, c)
+	if b, err := ioutil.ReadAll(f); err != nil || len(b) != 40 {
+		t.Errorf("read %d bytes, %v, want 40", len(b), err)
+	}
+	if c.size != 0 {
+		t.Errorf("%d bytes cached, want none", c.size)
+	}
+}
+
+func TestCacheLoadsExistingVariants(t *testing.T) {
+	dir := t.TempDir()
+	var calls int
+
+	c := NewCache(dir, 0)
+	for _, key := range keys[:3] {
+		read(t, c, key, variant(10, &calls))
+	}
+	// the oldest variant on disk is the first to go
+	now := time.Now()
+	for i, key := range keys[:3] {
+		modTime := now.Add(time.Duration(i-3) * time.Minute)
+		if err := os.Chtimes(c.path(key), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c = NewCache(dir, 25)
+	expectCached(t, c, keys[1], keys[2])
+	read(t, c, keys[1], variant(10, &calls))
+	if calls != 3 {
+		t.Errorf("generated %d times, want the variants of the earlier cache reused", calls)
+	}
+}
+
+func TestCacheDirWiped(t *testing.T) {
+	dir := t.TempDir()
+	c := NewCache(dir, 0)
+	var calls int
+
+	read(t, c, keys[0], variant(10, &calls))
+	if err := os.RemoveAll(c.path(keys[0])); err != nil {
+		t.Fatal(err)
+	}
+	read(t, c, keys[0], variant(10, &calls))
+
+	if calls != 2 || c.size != 10 {
+		t.Errorf("generated %d times with %d bytes cached, want twice with 10", calls, c.size)
+	}
+}
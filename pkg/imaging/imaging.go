@@ -0,0 +1,231 @@
+// Package imaging resizes JPEG, PNG and GIF images with the standard
+// library codecs.
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+)
+
+// Fit says how an image is fitted into a box when both width and height
+// are given
+type Fit string
+
+const (
+	// FitContain scales the image to fit inside the box, keeping its aspect
+	// ratio, so one side may end up shorter than asked for
+	FitContain Fit = "contain"
+	// FitCover scales the image to cover the box, keeping its aspect ratio,
+	// and crops what sticks out on the centre
+	FitCover Fit = "cover"
+	// FitFill stretches the image to the box
+	FitFill Fit = "fill"
+)
+
+// ErrUnsupportedFormat is returned for images which are not JPEG, PNG or GIF
+var ErrUnsupportedFormat = errors.New("imaging: unsupported format")
+
+// jpegQuality is the quality resized JPEGs are encoded with
+const jpegQuality = 85
+
+// sizes are the widths and heights variants are made in, so the number of
+// variants of an image stays small
+var sizes = []int{
+	16, 24, 32, 48, 64, 96, 128, 160, 192, 256, 320, 384, 480, 512, 640, 768,
+	960, 1024, 1280, 1536, 1920, 2048, 2560, 3072, 3840, 4096,
+}
+
+// SnapSize rounds a requested width or height up to the next of a fixed
+// set of sizes, above 4096 up to the next multiple of 1024
+func SnapSize(n int) int {
+	if i := sort.SearchInts(sizes, n); i < len(sizes) {
+		return sizes[i]
+	}
+
+	return (n + 1023) / 1024 * 1024
+}
+
+// Options of Resize. A zero Width or Height is derived from the other one
+// keeping the aspect ratio.
+type Options struct {
+	Width  int
+	Height int
+	Fit    Fit
+}
+
+// ParseFit returns the fit named s, FitContain for an empty s
+func ParseFit(s string) (Fit, error) {
+	switch fit := Fit(s); fit {
+	case "":
+		return FitContain, nil
+	case FitContain, FitCover, FitFill:
+		return fit, nil
+	default:
+		return "", fmt.Errorf("fit must be one of %s, %s or %s", FitContain, FitCover, FitFill)
+	}
+}
+
+// DecodeConfig returns the dimensions and format of an image without
+// decoding it
+func DecodeConfig(r io.Reader) (image.Config, string, error) {
+	cfg, format, err := image.DecodeConfig(r)
+	if errors.Is(err, image.ErrFormat) {
+		return cfg, format, ErrUnsupportedFormat
+	}
+
+	return cfg, format, err
+}
+
+// Decode decodes an image, for animated GIFs only the first frame
+func Decode(r io.Reader) (image.Image, string, error) {
+	img, format, err := image.Decode(r)
+	if errors.Is(err, image.ErrFormat) {
+		return nil, format, ErrUnsupportedFormat
+	}
+
+	return img, format, err
+}
+
+// Encode writes img in format, as returned by Decode
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		return png.Encode(w, img)
+	case "gif":
+		return gif.Encode(w, img, nil)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+// Resize returns src resized as opts says. Images are only ever scaled
+// down, a box larger than src leaves it at its size.
+func Resize(src image.Image, opts Options) image.Image {
+	crop, w, h := geometry(src.Bounds(), opts)
+	if crop == src.Bounds() && w == crop.Dx() && h == crop.Dy() {
+		return src
+	}
+
+	return resample(src, crop, w, h)
+}
+
+// geometry returns the part of src to keep and the size to scale it to
+func geometry(src image.Rectangle, opts Options) (crop image.Rectangle, w, h int) {
+	sw, sh := src.Dx(), src.Dy()
+	crop = src
+	if sw == 0 || sh == 0 {
+		return crop, sw, sh
+	}
+
+	switch {
+	case opts.Width <= 0 && opts.Height <= 0:
+		return crop, sw, sh
+	case opts.Height <= 0:
+		w = min(opts.Width, sw)
+		h = scale(sh, w, sw)
+	case opts.Width <= 0:
+		h = min(opts.Height, sh)
+		w = scale(sw, h, sh)
+	case opts.Fit == FitFill:
+		w, h = min(opts.Width, sw), min(opts.Height, sh)
+	case opts.Fit == FitCover:
+		// crop src to the aspect ratio of the box, then scale the crop
+		cw, ch := sw, scale(sw, opts.Height, opts.Width)
+		if ch > sh {
+			cw, ch = scale(sh, opts.Width, opts.Height), sh
+		}
+		x, y := src.Min.X+(sw-cw)/2, src.Min.Y+(sh-ch)/2
+		crop = image.Rect(x, y, x+cw, y+ch)
+		w, h = min(opts.Width, cw), min(opts.Height, ch)
+	default:
+		w, h = opts.Width, scale(sh, opts.Width, sw)
+		if h > opts.Height {
+			w, h = scale(sw, opts.Height, sh), opts.Height
+		}
+		if w > sw || h > sh {
+			w, h = sw, sh
+		}
+	}
+
+	return crop, w, h
+}
+
+// resample scales the crop of src to w x h. Every destination pixel is the
+// average of the source pixels it covers, which keeps downscaled images
+// free of aliasing.
+func resample(src image.Image, crop image.Rectangle, w, h int) *image.RGBA {
+	// averaging premultiplied colors keeps transparent pixels from bleeding
+	// their color into their neighbours
+	rgba := image.NewRGBA(image.Rect(0, 0, crop.Dx(), crop.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, crop.Min, draw.Src)
+
+	sw, sh := crop.Dx(), crop.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0, y1 := span(y, h, sh)
+		for x := 0; x < w; x++ {
+			x0, x1 := span(x, w, sw)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8((r + n/2) / n)
+			d[1] = uint8((g + n/2) / n)
+			d[2] = uint8((b + n/2) / n)
+			d[3] = uint8((a + n/2) / n)
+		}
+	}
+
+	return dst
+}
+
+// span returns the source pixels covered by destination pixel i of n,
+// when size source pixels are scaled to n
+func span(i, n, size int) (from, to int) {
+	from = i * size / n
+	to = (i + 1) * size / n
+	if to <= from {
+		to = from + 1
+	}
+
+	return from, to
+}
+
+// scale returns v scaled by num/den, rounded and at least 1
+func scale(v, num, den int) int {
+	s := (v*num + den/2) / den
+	if s < 1 {
+		return 1
+	}
+
+	return s
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
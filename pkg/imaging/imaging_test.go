@@ -0,0 +1,135 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// checker returns a w x h image of 2x2 black and white squares
+func checker(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if (x/2+y/2)%2 == 0 {
+				img.Set(x, y, color.White)
+			} else {
+				img.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	return img
+}
+
+func TestParseFit(t *testing.T) {
+	tests := map[string]Fit{"": FitContain, "contain": FitContain, "cover": FitCover, "fill": FitFill}
+	for s, want := range tests {
+		if fit, err := ParseFit(s); err != nil || fit != want {
+			t.Errorf("ParseFit(%q) = %q, %v, want %q", s, fit, err, want)
+		}
+	}
+	if _, err := ParseFit("stretch"); err == nil {
+		t.Error("ParseFit(stretch) succeeded")
+	}
+}
+
+func TestSnapSize(t *testing.T) {
+	tests := map[int]int{1: 16, 16: 16, 17: 24, 100: 128, 1000: 1024, 4096: 4096, 4097: 5120, 6000: 6144}
+	for n, want := range tests {
+		if got := SnapSize(n); got != want {
+			t.Errorf("SnapSize(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestResizeSize(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		w, h int
+	}{
+		{"width", Options{Width: 100}, 100, 50},
+		{"height", Options{Height: 25}, 50, 25},
+		{"contain wide box", Options{Width: 100, Height: 100, Fit: FitContain}, 100, 50},
+		{"contain tall box", Options{Width: 100, Height: 10, Fit: FitContain}, 20, 10},
+		{"cover", Options{Width: 50, Height: 50, Fit: FitCover}, 50, 50},
+		{"fill", Options{Width: 30, Height: 60, Fit: FitFill}, 30, 60},
+		{"no upscaling", Options{Width: 400}, 200, 100},
+		{"no upscaling in a box", Options{Width: 400, Height: 400, Fit: FitContain}, 200, 100},
+		{"cover larger than src", Options{Width: 150, Height: 300, Fit: FitCover}, 50, 100},
+		{"no options", Options{}, 200, 100},
+	}
+	src := checker(200, 100)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if b := Resize(src, tt.opts).Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+				t.Errorf("Resize() is %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.w, tt.h)
+			}
+		})
+	}
+}
+
+func TestResizeCoverCropsCentre(t *testing.T) {
+	// a white square between black borders, cover to a square keeps the
+	// square only
+	src := image.NewRGBA(image.Rect(0, 0, 30, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 30; x++ {
+			c := color.Black
+			if x >= 10 && x < 20 {
+				c = color.White
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	dst := Resize(src, Options{Width: 5, Height: 5, Fit: FitCover})
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			if r, _, _, _ := dst.At(x, y).RGBA(); r != 0xffff {
+				t.Fatalf("pixel %d,%d is %v, want white", x, y, dst.At(x, y))
+			}
+		}
+	}
+}
+
+func TestResizeAverages(t *testing.T) {
+	// every 2x2 square of the checker is one pixel, squares of 4x4 average
+	// to grey
+	dst := Resize(checker(8, 8), Options{Width: 2})
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			if got := color.RGBAModel.Convert(dst.At(x, y)).(color.RGBA); got != (color.RGBA{128, 128, 128, 255}) {
+				t.Errorf("pixel %d,%d is %v, want grey", x, y, got)
+			}
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	for _, format := range []string{"png", "jpeg", "gif"} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, Resize(checker(64, 32), Options{Width: 16}), format); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, got, err := DecodeConfig(bytes.NewReader(buf.Bytes()))
+			if err != nil || got != format || cfg.Width != 16 || cfg.Height != 8 {
+				t.Errorf("DecodeConfig() = %dx%d %q, %v, want 16x8 %q", cfg.Width, cfg.Height, got, err, format)
+			}
+			if _, _, err = Decode(&buf); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	if _, _, err := Decode(bytes.NewReader([]byte("not an image"))); err != ErrUnsupportedFormat {
+		t.Errorf("Decode() error = %v, want ErrUnsupportedFormat", err)
+	}
+	if err := Encode(&bytes.Buffer{}, checker(1, 1), "bmp"); err != ErrUnsupportedFormat {
+		t.Errorf("Encode() error = %v, want ErrUnsupportedFormat", err)
+	}
+}
@@ -0,0 +1,484 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// fakeServer stands in for postgres. It knows the statements of the
+// migrator and takes everything else for a migration body, which it
+// records when its transaction commits.
+type fakeServer struct {
+	// lock is the advisory lock, full while a connection holds it
+	lock chan struct{}
+
+	mu       sync.Mutex
+	holder   *fakeConn
+	versions map[int64]fakeVersion
+	executed []string
+	// fail makes the body with this text fail
+	fail string
+	// unlocked collects bodies executed without holding the lock
+	unlocked []string
+}
+
+type fakeVersion struct {
+	name     string
+	checksum string
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{
+		lock:     make(chan struct{}, 1),
+		versions: make(map[int64]fakeVersion),
+	}
+}
+
+func (s *fakeServer) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{srv: s}, nil
+}
+
+func (s *fakeServer) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+func (s *fakeServer) open() *sql.DB {
+	return sql.OpenDB(s)
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("fake: use the connector")
+}
+
+type fakeConn struct {
+	srv *fakeServer
+	// pending are the changes of the open transaction
+	pending []func()
+	inTx    bool
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake: prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.inTx = true
+	c.pending = nil
+	return fakeTx{c}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	query = strings.TrimSpace(query)
+	s := c.srv
+
+	switch {
+	case strings.HasPrefix(query, "SELECT pg_advisory_lock("):
+		s.lock <- struct{}{}
+		s.mu.Lock()
+		s.holder = c
+		s.mu.Unlock()
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "SELECT pg_advisory_unlock("):
+		s.mu.Lock()
+		s.holder = nil
+		s.mu.Unlock()
+		<-s.lock
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS "+tableName),
+		strings.HasPrefix(query, "ALTER TABLE "+tableName):
+		return driver.RowsAffected(0), nil
+	case strings.HasPrefix(query, "INSERT INTO "+tableName):
+		version := args[0].Value.(int64)
+		v := fakeVersion{name: args[1].Value.(string), checksum: args[2].Value.(string)}
+		c.apply(func() { s.versions[version] = v })
+		return driver.RowsAffected(1), nil
+	case strings.HasPrefix(query, "DELETE FROM "+tableName):
+		version := args[0].Value.(int64)
+		c.apply(func() { delete(s.versions, version) })
+		return driver.RowsAffected(1), nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.holder != c {
+		s.unlocked = append(s.unlocked, query)
+	}
+	if query == s.fail {
+		return nil, fmt.Errorf("fake: %s failed", query)
+	}
+	c.pending = append(c.pending, func() { s.executed = append(s.executed, query) })
+
+	return driver.RowsAffected(0), nil
+}
+
+// apply runs a change now, or on commit inside a transaction
+func (c *fakeConn) apply(change func()) {
+	if c.inTx {
+		c.pending = append(c.pending, change)
+		return
+	}
+	c.srv.mu.Lock()
+	change()
+	c.srv.mu.Unlock()
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.HasPrefix(query, "SELECT version, applied_at, checksum FROM "+tableName) {
+		return nil, fmt.Errorf("fake: unexpected query %q", query)
+	}
+
+	c.srv.mu.Lock()
+	defer c.srv.mu.Unlock()
+
+	rows := &fakeRows{}
+	for version, v := range c.srv.versions {
+		rows.values = append(rows.values, []driver.Value{version, appliedAt, v.checksum})
+	}
+
+	return rows, nil
+}
+
+type fakeTx struct {
+	c *fakeConn
+}
+
+func (tx fakeTx) Commit() error {
+	tx.c.srv.mu.Lock()
+	for _, change := range tx.c.pending {
+		change()
+	}
+	tx.c.srv.mu.Unlock()
+	tx.c.pending, tx.c.inTx = nil, false
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.c.pending, tx.c.inTx = nil, false
+	return nil
+}
+
+type fakeRows struct {
+	values [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"version", "applied_at", "checksum"}
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var appliedAt = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"000001_create_todos.up.sql":     {Data: []byte("create todos")},
+		"000001_create_todos.down.sql":   {Data: []byte("drop todos")},
+		"000002_add_user_id.up.sql":      {Data: []byte("add user_id")},
+		"000002_add_user_id.down.sql":    {Data: []byte("drop user_id")},
+		"000010_create_lists.up.sql":     {Data: []byte("create lists")},
+		"000010_create_lists.down.sql":   {Data: []byte("drop lists")},
+		"000011_add_list_archive.up.sql": {Data: []byte("add archived_at")},
+	}
+}
+
+func newMigrator(t *testing.T, db *sql.DB, fsys fstest.MapFS) *Migrator {
+	t.Helper()
+
+	m, err := New(db, fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func versions(migrations []Migration) []int64 {
+	res := make([]int64, 0, len(migrations))
+	for _, m := range migrations {
+		res = append(res, m.Version)
+	}
+	return res
+}
+
+func (s *fakeServer) appliedVersions() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]int64, 0, len(s.versions))
+	for version := range s.versions {
+		res = append(res, version)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+func TestNew(t *testing.T) {
+	m := newMigrator(t, nil, testMigrations())
+	if got, want := versions(m.migrations), []int64{1, 2, 10, 11}; !reflect.DeepEqual(got, want) {
+		t.Errorf("versions = %v, want %v", got, want)
+	}
+	if m.migrations[0].Checksum == "" || m.migrations[0].Checksum == m.migrations[1].Checksum {
+		t.Errorf("checksums %q and %q should differ", m.migrations[0].Checksum, m.migrations[1].Checksum)
+	}
+
+	tests := []struct {
+		name string
+		file string
+	}{
+		{"bad name", "create_todos.up.sql"},
+		{"two names", "000001_make_todos.down.sql"},
+		{"no up", "000003_add_tags.down.sql"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := testMigrations()
+			fsys[tt.file] = &fstest.MapFile{Data: []byte("x")}
+			if _, err := New(nil, fsys); err == nil {
+				t.Errorf("New() with %s succeeded", tt.file)
+			}
+		})
+	}
+}
+
+func TestUp(t *testing.T) {
+	srv := newFakeServer()
+	m := newMigrator(t, srv.open(), testMigrations())
+	ctx := context.Background()
+
+	done, err := m.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := versions(done), []int64{1, 2, 10, 11}; !reflect.DeepEqual(got, want) {
+		t.Errorf("applied %v, want %v", got, want)
+	}
+	want := []string{"create todos", "add user_id", "create lists", "add archived_at"}
+	if !reflect.DeepEqual(srv.executed, want) {
+		t.Errorf("executed %v, want %v", srv.executed, want)
+	}
+	if srv.versions[2].checksum != m.migrations[1].Checksum {
+		t.Errorf("stored checksum %q, want %q", srv.versions[2].checksum, m.migrations[1].Checksum)
+	}
+
+	// nothing is left to do
+	if done, err = m.Up(ctx); err != nil || len(done) != 0 {
+		t.Errorf("second Up() = %v, %v, want nothing", versions(done), err)
+	}
+	if len(srv.executed) != len(want) {
+		t.Errorf("second Up() executed %v", srv.executed[len(want):])
+	}
+}
+
+func TestUpStopsAtFailure(t *testing.T) {
+	srv := newFakeServer()
+	srv.fail = "create lists"
+	m := newMigrator(t, srv.open(), testMigrations())
+
+	done, err := m.Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "10_create_lists up") {
+		t.Fatalf("Up() error = %v, want the failed migration", err)
+	}
+	if got, want := versions(done), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("applied %v, want %v", got, want)
+	}
+	// the failed migration was rolled back with its version
+	if got, want := srv.appliedVersions(), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stored versions %v, want %v", got, want)
+	}
+}
+
+func TestDown(t *testing.T) {
+	srv := newFakeServer()
+	fsys := testMigrations()
+	delete(fsys, "000011_add_list_archive.up.sql")
+	m := newMigrator(t, srv.open(), fsys)
+	ctx := context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	srv.executed = nil
+
+	done, err := m.Down(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := versions(done), []int64{10, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rolled back %v, want %v", got, want)
+	}
+	if want := []string{"drop lists", "drop user_id"}; !reflect.DeepEqual(srv.executed, want) {
+		t.Errorf("executed %v, want %v", srv.executed, want)
+	}
+	if got, want := srv.appliedVersions(), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stored versions %v, want %v", got, want)
+	}
+
+	// more steps than applied migrations roll back what there is
+	if done, err = m.Down(ctx, 5); err != nil || !reflect.DeepEqual(versions(done), []int64{1}) {
+		t.Errorf("Down(5) = %v, %v, want [1]", versions(done), err)
+	}
+}
+
+func TestDownWithoutDownMigration(t *testing.T) {
+	srv := newFakeServer()
+	m := newMigrator(t, srv.open(), testMigrations())
+	ctx := context.Background()
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Down(ctx, 1); err == nil {
+		t.Fatal("Down() of a migration without down succeeded")
+	}
+	if got, want := srv.appliedVersions(), []int64{1, 2, 10, 11}; !reflect.DeepEqual(got, want) {
+		t.Errorf("stored versions %v, want %v", got, want)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	srv := newFakeServer()
+	srv.versions[1] = fakeVersion{name: "create_todos"}
+	srv.versions[2] = fakeVersion{name: "add_user_id"}
+	m := newMigrator(t, srv.open(), testMigrations())
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Status{
+		{Version: 1, Name: "create_todos", Applied: true, AppliedAt: appliedAt},
+		{Version: 2, Name: "add_user_id", Applied: true, AppliedAt: appliedAt},
+		{Version: 10, Name: "create_lists"},
+		{Version: 11, Name: "add_list_archive"},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("Status() = %+v, want %+v", statuses, want)
+	}
+	if len(srv.executed) != 0 {
+		t.Errorf("Status() executed %v", srv.executed)
+	}
+}
+
+func TestAdvisoryLock(t *testing.T) {
+	srv := newFakeServer()
+	ctx := context.Background()
+
+	// instances starting at once apply every migration once
+	const instances = 4
+	var wg sync.WaitGroup
+	errs := make(chan error, instances)
+	for i := 0; i < instances; i++ {
+		m := newMigrator(t, srv.open(), testMigrations())
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.Up(ctx)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{"create todos", "add user_id", "create lists", "add archived_at"}
+	if !reflect.DeepEqual(srv.executed, want) {
+		t.Errorf("executed %v, want %v", srv.executed, want)
+	}
+	if len(srv.unlocked) != 0 {
+		t.Errorf("executed %v without the lock", srv.unlocked)
+	}
+	if len(srv.lock) != 0 {
+		t.Error("lock is still held")
+	}
+
+	// a failure releases the lock too
+	srv.fail = "drop user_id"
+	m := newMigrator(t, srv.open(), testMigrations())
+	if _, err := m.Down(ctx, 3); err == nil {
+		t.Fatal("Down() succeeded")
+	}
+	if len(srv.lock) != 0 {
+		t.Error("lock is still held after a failure")
+	}
+}
+
+func TestChecksumDrift(t *testing.T) {
+	srv := newFakeServer()
+	ctx := context.Background()
+
+	fsys := testMigrations()
+	delete(fsys, "000011_add_list_archive.up.sql")
+	if _, err := newMigrator(t, srv.open(), fsys).Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	srv.executed = nil
+
+	// an applied migration is edited and a new one added
+	fsys = testMigrations()
+	fsys["000002_add_user_id.up.sql"] = &fstest.MapFile{Data: []byte("add user_id not null")}
+	m := newMigrator(t, srv.open(), fsys)
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Up() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+	if _, err := m.Down(ctx, 1); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("Down() error = %v, want %v", err, ErrChecksumMismatch)
+	}
+	if len(srv.executed) != 0 {
+		t.Errorf("executed %v despite the drift", srv.executed)
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range statuses {
+		if s.Changed != (s.Version == 2) {
+			t.Errorf("version %d changed = %v", s.Version, s.Changed)
+		}
+	}
+
+	// versions applied before checksums were kept are trusted
+	srv.versions[2] = fakeVersion{name: "add_user_id"}
+	if _, err = m.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"add archived_at"}; !reflect.DeepEqual(srv.executed, want) {
+		t.Errorf("executed %v, want %v", srv.executed, want)
+	}
+}
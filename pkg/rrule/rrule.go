@@ -0,0 +1,404 @@
+// Package rrule parses and expands a subset of RFC 5545 recurrence rules:
+// FREQ of DAILY, WEEKLY, MONTHLY or YEARLY with INTERVAL, BYDAY, COUNT,
+// UNTIL and WKST, e.g. "FREQ=MONTHLY;BYDAY=-1FR;COUNT=12".
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is how often a rule repeats
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencyNames = [...]string{"", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+func (f Frequency) String() string {
+	if f < Daily || f > Yearly {
+		return strconv.Itoa(int(f))
+	}
+	return frequencyNames[f]
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Day is a BYDAY entry. N picks the nth weekday of the month, or of the
+// year for YEARLY rules, counting from the end when negative. Zero is every
+// such weekday.
+type Day struct {
+	N       int
+	Weekday time.Weekday
+}
+
+func (d Day) String() string {
+	if d.N == 0 {
+		return weekdayNames[d.Weekday]
+	}
+	return strconv.Itoa(d.N) + weekdayNames[d.Weekday]
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []Day
+	// Count limits the number of occurrences, the start included, zero
+	// means no limit
+	Count int
+	// Until is the last time an occurrence may be at, zero means no limit
+	Until     time.Time
+	WeekStart time.Weekday
+}
+
+// Parse parses a rule like "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", an
+// "RRULE:" prefix is allowed
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("rrule: empty rule")
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("rrule: invalid part %q", part)
+		}
+		name, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		if seen[name] {
+			return nil, fmt.Errorf("rrule: %s is given twice", name)
+		}
+		seen[name] = true
+
+		var err error
+		switch name {
+		case "FREQ":
+			f, ok := frequencies[value]
+			if !ok {
+				return nil, fmt.Errorf("rrule: unsupported FREQ %s", value)
+			}
+			r.Freq = f
+		case "INTERVAL":
+			r.Interval, err = positive(name, value)
+		case "COUNT":
+			r.Count, err = positive(name, value)
+		case "UNTIL":
+			r.Until, err = parseUntil(value)
+		case "WKST":
+			d, ok := weekdays[value]
+			if !ok {
+				return nil, fmt.Errorf("rrule: invalid WKST %s", value)
+			}
+			r.WeekStart = d
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Freq == 0 {
+		return nil, errors.New("rrule: FREQ is required")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, errors.New("rrule: COUNT and UNTIL can't be combined")
+	}
+	for _, d := range r.ByDay {
+		if d.N != 0 && r.Freq != Monthly && r.Freq != Yearly {
+			return nil, fmt.Errorf("rrule: BYDAY %s needs a MONTHLY or YEARLY FREQ", d)
+		}
+		if (r.Freq == Monthly && abs(d.N) > 5) || abs(d.N) > 53 {
+			return nil, fmt.Errorf("rrule: invalid BYDAY %s", d)
+		}
+	}
+
+	return r, nil
+}
+
+// String returns the rule in its canonical form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, d := range r.ByDay {
+			days = append(days, d.String())
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(untilLayout))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayNames[r.WeekStart])
+	}
+
+	return strings.Join(parts, ";")
+}
+
+const (
+	untilLayout     = "20060102T150405Z"
+	untilDateLayout = "20060102"
+)
+
+// parseUntil accepts a UTC date-time or a date, which includes the whole
+// day
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse(untilLayout, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(untilDateLayout, value); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %s, use YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
+
+func parseByDay(value string) ([]Day, error) {
+	var days []Day
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("rrule: invalid BYDAY %s", v)
+		}
+		weekday, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("rrule: invalid BYDAY %s", v)
+		}
+
+		d := Day{Weekday: weekday}
+		if n := v[:len(v)-2]; n != "" {
+			var err error
+			if d.N, err = strconv.Atoi(n); err != nil || d.N == 0 {
+				return nil, fmt.Errorf("rrule: invalid BYDAY %s", v)
+			}
+		}
+		days = append(days, d)
+	}
+
+	return days, nil
+}
+
+func positive(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("rrule: %s must be a positive number", name)
+	}
+	return n, nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// maxEmptyPeriods ends the expansion of rules which stopped producing
+// occurrences, like the fifth Monday of every 12th month
+const maxEmptyPeriods = 1000
+
+// Iterator walks the occurrences of a rule in order
+type Iterator struct {
+	rule  *Rule
+	start time.Time
+	// period is the index of the next period to expand
+	period  int
+	pending []time.Time
+	emitted int
+	done    bool
+}
+
+// Iterator returns the occurrences of the series starting at start, which
+// is always the first one. Occurrences have the time of day of start in
+// its location, so they stay at the same wall clock time across daylight
+// saving changes.
+func (r *Rule) Iterator(start time.Time) *Iterator {
+	return &Iterator{
+		rule:    r,
+		start:   start,
+		pending: []time.Time{start},
+	}
+}
+
+// Next returns the next occurrence, false when there are no more
+func (it *Iterator) Next() (time.Time, bool) {
+	if it.done {
+		return time.Time{}, false
+	}
+
+	for empty := 0; len(it.pending) == 0; empty++ {
+		if empty == maxEmptyPeriods {
+			it.done = true
+			return time.Time{}, false
+		}
+		for _, t := range it.rule.expand(it.start, it.period) {
+			if t.After(it.start) {
+				it.pending = append(it.pending, t)
+			}
+		}
+		it.period++
+	}
+
+	t := it.pending[0]
+	it.pending = it.pending[1:]
+	r := it.rule
+	if (r.Count > 0 && it.emitted == r.Count) || (!r.Until.IsZero() && t.After(r.Until)) {
+		it.done = true
+		return time.Time{}, false
+	}
+	it.emitted++
+
+	return t, true
+}
+
+// After returns the first occurrence after t of the series starting at
+// start, false when the series ends before
+func (r *Rule) After(start, t time.Time) (time.Time, bool) {
+	it := r.Iterator(start)
+	for {
+		next, ok := it.Next()
+		if !ok || next.After(t) {
+			return next, ok
+		}
+	}
+}
+
+// expand returns the occurrences of a period of the series, in order. The
+// dates are computed as UTC calendar dates and placed at the time of day
+// of start afterwards.
+func (r *Rule) expand(start time.Time, period int) []time.Time {
+	y, m, d := start.Date()
+	first := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	step := period * r.Interval
+
+	var dates []time.Time
+	switch r.Freq {
+	case Daily:
+		day := first.AddDate(0, 0, step)
+		if r.matchesWeekday(day.Weekday()) {
+			dates = append(dates, day)
+		}
+	case Weekly:
+		offset := (int(first.Weekday()) - int(r.WeekStart) + 7) % 7
+		week := first.AddDate(0, 0, 7*step-offset)
+		for i := 0; i < 7; i++ {
+			day := week.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 {
+				if day.Weekday() == first.Weekday() {
+					dates = append(dates, day)
+				}
+			} else if r.matchesWeekday(day.Weekday()) {
+				dates = append(dates, day)
+			}
+		}
+	case Monthly:
+		month := time.Date(y, m+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) == 0 {
+			// months without the day of start are skipped
+			if day := month.AddDate(0, 0, d-1); day.Month() == month.Month() {
+				dates = append(dates, day)
+			}
+		} else {
+			dates = r.byDayIn(month, month.AddDate(0, 1, 0))
+		}
+	case Yearly:
+		year := time.Date(y+step, 1, 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByDay) == 0 {
+			// Feb 29 only recurs in leap years
+			if day := time.Date(y+step, m, d, 0, 0, 0, 0, time.UTC); day.Day() == d {
+				dates = append(dates, day)
+			}
+		} else {
+			dates = r.byDayIn(year, year.AddDate(1, 0, 0))
+		}
+	}
+
+	occurrences := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		occurrences = append(occurrences, time.Date(date.Year(), date.Month(), date.Day(),
+			start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location()))
+	}
+
+	return occurrences
+}
+
+func (r *Rule) matchesWeekday(w time.Weekday) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, d := range r.ByDay {
+		if d.Weekday == w {
+			return true
+		}
+	}
+	return false
+}
+
+// byDayIn returns the dates in [from, to) picked by BYDAY, in order
+func (r *Rule) byDayIn(from, to time.Time) []time.Time {
+	picked := make(map[time.Time]bool)
+	for _, d := range r.ByDay {
+		var matches []time.Time
+		for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+			if day.Weekday() == d.Weekday {
+				matches = append(matches, day)
+			}
+		}
+
+		switch {
+		case d.N == 0:
+			for _, day := range matches {
+				picked[day] = true
+			}
+		case d.N > 0 && d.N <= len(matches):
+			picked[matches[d.N-1]] = true
+		case d.N < 0 && -d.N <= len(matches):
+			picked[matches[len(matches)+d.N]] = true
+		}
+	}
+
+	dates := make([]time.Time, 0, len(picked))
+	for day := range picked {
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	return dates
+}
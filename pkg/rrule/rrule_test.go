@@ -0,0 +1,283 @@
+package rrule
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rule string
+		// want is the canonical form, or the start of the error
+		want string
+		err  bool
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: "RRULE:freq=weekly;byday=mo,th", want: "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{rule: "FREQ=MONTHLY;INTERVAL=1;BYDAY=-1FR;COUNT=12", want: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=12"},
+		{rule: "FREQ=YEARLY;BYDAY=+2MO,-53SU", want: "FREQ=YEARLY;BYDAY=2MO,-53SU"},
+		{rule: "FREQ=WEEKLY;WKST=SU;INTERVAL=2", want: "FREQ=WEEKLY;INTERVAL=2;WKST=SU"},
+		// a date includes the whole day
+		{rule: "FREQ=DAILY;UNTIL=20261231", want: "FREQ=DAILY;UNTIL=20261231T235959Z"},
+		{rule: "FREQ=DAILY;UNTIL=20261231T080000Z", want: "FREQ=DAILY;UNTIL=20261231T080000Z"},
+
+		{rule: "", want: "rrule: empty rule", err: true},
+		{rule: "RRULE:", want: "rrule: empty rule", err: true},
+		{rule: "INTERVAL=2", want: "rrule: FREQ is required", err: true},
+		{rule: "FREQ", want: `rrule: invalid part "FREQ"`, err: true},
+		{rule: "FREQ=DAILY;", want: `rrule: invalid part ""`, err: true},
+		{rule: "FREQ=HOURLY", want: "rrule: unsupported FREQ HOURLY", err: true},
+		{rule: "FREQ=DAILY;freq=WEEKLY", want: "rrule: FREQ is given twice", err: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", want: "rrule: INTERVAL must be a positive number", err: true},
+		{rule: "FREQ=DAILY;COUNT=-1", want: "rrule: COUNT must be a positive number", err: true},
+		{rule: "FREQ=DAILY;COUNT=2;UNTIL=20261231", want: "rrule: COUNT and UNTIL can't be combined", err: true},
+		{rule: "FREQ=DAILY;UNTIL=2026-12-31", want: "rrule: invalid UNTIL", err: true},
+		{rule: "FREQ=WEEKLY;WKST=XX", want: "rrule: invalid WKST XX", err: true},
+		{rule: "FREQ=WEEKLY;BYDAY=1MO", want: "rrule: BYDAY 1MO needs a MONTHLY or YEARLY FREQ", err: true},
+		{rule: "FREQ=MONTHLY;BYDAY=6MO", want: "rrule: invalid BYDAY 6MO", err: true},
+		{rule: "FREQ=YEARLY;BYDAY=54MO", want: "rrule: invalid BYDAY 54MO", err: true},
+		{rule: "FREQ=MONTHLY;BYDAY=0MO", want: "rrule: invalid BYDAY 0MO", err: true},
+		{rule: "FREQ=MONTHLY;BYDAY=XX", want: "rrule: invalid BYDAY XX", err: true},
+		{rule: "FREQ=MONTHLY;BYDAY=MO,", want: "rrule: invalid BYDAY ", err: true},
+		// only the parts in the package doc are supported
+		{rule: "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", want: "rrule: unsupported part BYSETPOS", err: true},
+		{rule: "FREQ=YEARLY;BYMONTH=3", want: "rrule: unsupported part BYMONTH", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if tt.err {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+					t.Fatalf("Parse(%q) error = %v, want %s", tt.rule, err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.rule, got, tt.want)
+			}
+			// the canonical form parses to the same rule
+			again, err := Parse(r.String())
+			if err != nil || !reflect.DeepEqual(again, r) {
+				t.Errorf("Parse(%q) = %+v, %v, want %+v", r.String(), again, err, r)
+			}
+		})
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loc
+}
+
+const occurrenceLayout = "2006-01-02 15:04 MST"
+
+func TestOccurrences(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		// n occurrences are taken, fewer when the rule ends before
+		n    int
+		want []string
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: time.Date(2026, 10, 30, 9, 0, 0, 0, time.UTC),
+			n:     3,
+			want:  []string{"2026-10-30 09:00 UTC", "2026-11-02 09:00 UTC", "2026-11-05 09:00 UTC"},
+		},
+		{
+			name:  "daily by day",
+			rule:  "FREQ=DAILY;INTERVAL=2;BYDAY=MO,WE,FR;COUNT=5",
+			start: time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want: []string{"2026-10-19 09:00 UTC", "2026-10-21 09:00 UTC", "2026-10-23 09:00 UTC",
+				"2026-11-02 09:00 UTC", "2026-11-04 09:00 UTC"},
+		},
+		{
+			name: "weekly by day",
+			rule: "FREQ=WEEKLY;BYDAY=MO,TH",
+			// a Tuesday, the start is an occurrence even off the days
+			start: time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC),
+			n:     4,
+			want:  []string{"2026-10-20 09:00 UTC", "2026-10-22 09:00 UTC", "2026-10-26 09:00 UTC", "2026-10-29 09:00 UTC"},
+		},
+		{
+			// the examples of WKST in RFC 5545
+			name:  "weekly week start monday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			start: time.Date(1997, 8, 5, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"1997-08-05 09:00 UTC", "1997-08-10 09:00 UTC", "1997-08-19 09:00 UTC", "1997-08-24 09:00 UTC"},
+		},
+		{
+			name:  "weekly week start sunday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			start: time.Date(1997, 8, 5, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"1997-08-05 09:00 UTC", "1997-08-17 09:00 UTC", "1997-08-19 09:00 UTC", "1997-08-31 09:00 UTC"},
+		},
+		{
+			name:  "monthly last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR;COUNT=4",
+			start: time.Date(2026, 1, 30, 17, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"2026-01-30 17:00 UTC", "2026-02-27 17:00 UTC", "2026-03-27 17:00 UTC", "2026-04-24 17:00 UTC"},
+		},
+		{
+			name:  "monthly second tuesday",
+			rule:  "FREQ=MONTHLY;BYDAY=2TU",
+			start: time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC),
+			n:     3,
+			want:  []string{"2026-10-13 09:00 UTC", "2026-11-10 09:00 UTC", "2026-12-08 09:00 UTC"},
+		},
+		{
+			name:  "monthly skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			n:     3,
+			want:  []string{"2026-01-31 09:00 UTC", "2026-03-31 09:00 UTC", "2026-05-31 09:00 UTC"},
+		},
+		{
+			name:  "yearly leap day",
+			rule:  "FREQ=YEARLY",
+			start: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			n:     3,
+			want:  []string{"2024-02-29 09:00 UTC", "2028-02-29 09:00 UTC", "2032-02-29 09:00 UTC"},
+		},
+		{
+			name:  "yearly first monday",
+			rule:  "FREQ=YEARLY;BYDAY=1MO",
+			start: time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC),
+			n:     3,
+			want:  []string{"2026-01-05 09:00 UTC", "2027-01-04 09:00 UTC", "2028-01-03 09:00 UTC"},
+		},
+		{
+			// COUNT includes the start
+			name:  "count",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"2026-10-18 09:00 UTC", "2026-10-19 09:00 UTC"},
+		},
+		{
+			name:  "until date",
+			rule:  "FREQ=DAILY;UNTIL=20261020",
+			start: time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"2026-10-18 23:00 UTC", "2026-10-19 23:00 UTC", "2026-10-20 23:00 UTC"},
+		},
+		{
+			name:  "until time",
+			rule:  "FREQ=DAILY;UNTIL=20261020T080000Z",
+			start: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"2026-10-18 09:00 UTC", "2026-10-19 09:00 UTC"},
+		},
+		{
+			name:  "until is inclusive",
+			rule:  "FREQ=WEEKLY;UNTIL=20261025T090000Z",
+			start: time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC),
+			n:     10,
+			want:  []string{"2026-10-18 09:00 UTC", "2026-10-25 09:00 UTC"},
+		},
+		{
+			// the wall clock time stays when daylight saving time ends
+			name:  "dst end",
+			rule:  "FREQ=DAILY",
+			start: time.Date(2026, 10, 31, 9, 0, 0, 0, newYork),
+			n:     3,
+			want:  []string{"2026-10-31 09:00 EDT", "2026-11-01 09:00 EST", "2026-11-02 09:00 EST"},
+		},
+		{
+			// a time skipped when daylight saving time starts moves an
+			// hour later, the series keeps its time afterwards
+			name:  "dst start",
+			rule:  "FREQ=WEEKLY",
+			start: time.Date(2026, 3, 22, 2, 30, 0, 0, berlin),
+			n:     3,
+			want:  []string{"2026-03-22 02:30 CET", "2026-03-29 03:30 CEST", "2026-04-05 02:30 CEST"},
+		},
+		{
+			// dates are counted in the location of start
+			name:  "dst monthly",
+			rule:  "FREQ=MONTHLY;BYDAY=-1SU",
+			start: time.Date(2026, 2, 22, 23, 30, 0, 0, berlin),
+			n:     3,
+			want:  []string{"2026-02-22 23:30 CET", "2026-03-29 23:30 CEST", "2026-04-26 23:30 CEST"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			it := r.Iterator(tt.start)
+			for len(got) < tt.n {
+				next, ok := it.Next()
+				if !ok {
+					break
+				}
+				got = append(got, next.Format(occurrenceLayout))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s from %s\ngot  %q\nwant %q", tt.rule, tt.start.Format(occurrenceLayout), got, tt.want)
+			}
+
+			// an ended iterator stays ended
+			if len(got) < tt.n {
+				if _, ok := it.Next(); ok {
+					t.Error("Next() went on after the end")
+				}
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{start.Add(-time.Hour), "2026-10-19 09:00 UTC"},
+		// occurrences at t are not after it
+		{start, "2026-10-22 09:00 UTC"},
+		{time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC), "2026-10-26 09:00 UTC"},
+		{time.Date(2026, 10, 29, 9, 0, 0, 0, time.UTC), ""},
+	}
+	for _, tt := range tests {
+		next, ok := r.After(start, tt.t)
+		got := ""
+		if ok {
+			got = next.Format(occurrenceLayout)
+		}
+		if got != tt.want {
+			t.Errorf("After(%s) = %q, want %q", tt.t.Format(occurrenceLayout), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package scheduler fires jobs at given times. Jobs are kept in a priority
+// queue by time and only the earliest one is waited for, so any number of
+// them costs a single timer. The queue lives in memory, owners persist
+// their jobs and schedule them again after a restart.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+type job struct {
+	id    string
+	at    time.Time
+	index int
+}
+
+// Scheduler calls fire with the id of each job once its time has come.
+// Jobs whose time passed already fire right away.
+type Scheduler struct {
+	clock Clock
+	fire  func(id string)
+
+	mu    sync.Mutex
+	queue jobQueue
+	jobs  map[string]*job
+	// wake interrupts Run when the earliest job changed
+	wake chan struct{}
+}
+
+// New returns a scheduler which calls fire from Run, one job at a time
+func New(clock Clock, fire func(id string)) *Scheduler {
+	return &Scheduler{
+		clock: clock,
+		fire:  fire,
+		jobs:  make(map[string]*job),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// Schedule fires id at at, replacing an earlier schedule of it
+func (s *Scheduler) Schedule(id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j, ok := s.jobs[id]; ok {
+		j.at = at
+		heap.Fix(&s.queue, j.index)
+	} else {
+		j = &job{id: id, at: at}
+		heap.Push(&s.queue, j)
+		s.jobs[id] = j
+	}
+	s.notify()
+}
+
+// Cancel drops id if it is scheduled
+func (s *Scheduler) Cancel(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	heap.Remove(&s.queue, j.index)
+	delete(s.jobs, id)
+	s.notify()
+}
+
+// Len returns the number of scheduled jobs
+func (s *Scheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.queue)
+}
+
+// Run fires the jobs as they become due until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		for _, id := range s.due() {
+			s.fire(id)
+		}
+
+		// without jobs only a schedule or ctx wakes us up
+		var (
+			timer   Timer
+			timeout <-chan time.Time
+		)
+		s.mu.Lock()
+		if len(s.queue) > 0 {
+			timer = s.clock.NewTimer(s.queue[0].at.Sub(s.clock.Now()))
+			timeout = timer.C()
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-timeout:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// due pops the jobs whose time has come
+func (s *Scheduler) due() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var ids []string
+	for len(s.queue) > 0 && !s.queue[0].at.After(now) {
+		j := heap.Pop(&s.queue).(*job)
+		delete(s.jobs, j.id)
+		ids = append(ids, j.id)
+	}
+
+	return ids
+}
+
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// jobQueue is a heap of jobs, earliest first
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].id < q[j].id
+}
+
+func (q jobQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *jobQueue) Push(x interface{}) {
+	j := x.(*job)
+	j.index = len(*q)
+	*q = append(*q, j)
+}
+
+func (q *jobQueue) Pop() interface{} {
+	old := *q
+	j := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return j
+}
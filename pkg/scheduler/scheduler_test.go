@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when Advance is called and fires the timers which
+// became due
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers map[*fakeTimer]bool
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, timers: make(map[*fakeTimer]bool)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+	} else {
+		c.timers[t] = true
+	}
+
+	return t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for t := range c.timers {
+		if !t.at.After(c.now) {
+			t.c <- c.now
+			delete(c.timers, t)
+		}
+	}
+}
+
+type fakeTimer struct {
+	clock *fakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.clock.timers[t]
+	delete(t.clock.timers, t)
+
+	return active
+}
+
+var epoch = time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC)
+
+// run starts s with a fake clock and returns the channel fired ids are
+// sent to
+func run(t *testing.T, clock *fakeClock, schedule func(s *Scheduler)) (*Scheduler, <-chan string) {
+	fired := make(chan string, 16)
+	s := New(clock, func(id string) { fired <- id })
+	if schedule != nil {
+		schedule(s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return s, fired
+}
+
+func expectFired(t *testing.T, fired <-chan string, want ...string) {
+	t.Helper()
+
+	for _, id := range want {
+		select {
+		case got := <-fired:
+			if got != id {
+				t.Fatalf("fired %q, want %q", got, id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%q did not fire", id)
+		}
+	}
+}
+
+// expectIdle makes sure nothing fired, jobs fire in order of time, so
+// checking before a later job fired is enough
+func expectIdle(t *testing.T, fired <-chan string) {
+	t.Helper()
+
+	select {
+	case id := <-fired:
+		t.Fatalf("%q fired unexpectedly", id)
+	default:
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	clock := newFakeClock(epoch)
+	s, fired := run(t, clock, nil)
+
+	s.Schedule("a", epoch.Add(time.Minute))
+	s.Schedule("b", epoch.Add(2*time.Minute))
+
+	clock.Advance(time.Minute)
+	expectFired(t, fired, "a")
+	expectIdle(t, fired)
+
+	clock.Advance(time.Minute)
+	expectFired(t, fired, "b")
+
+	if n := s.Len(); n != 0 {
+		t.Errorf("Len() = %d after firing, want 0", n)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	clock := newFakeClock(epoch)
+	s, fired := run(t, clock, nil)
+
+	s.Schedule("a", epoch.Add(time.Minute))
+	s.Schedule("b", epoch.Add(2*time.Minute))
+	s.Cancel("a")
+	// cancelling twice or something unknown is fine
+	s.Cancel("a")
+	s.Cancel("c")
+
+	if n := s.Len(); n != 1 {
+		t.Fatalf("Len() = %d, want 1", n)
+	}
+
+	clock.Advance(2 * time.Minute)
+	expectFired(t, fired, "b")
+	expectIdle(t, fired)
+}
+
+func TestReschedule(t *testing.T) {
+	clock := newFakeClock(epoch)
+	s, fired := run(t, clock, nil)
+
+	s.Schedule("a", epoch.Add(time.Minute))
+	s.Schedule("b", epoch.Add(2*time.Minute))
+	s.Schedule("a", epoch.Add(3*time.Minute))
+
+	if n := s.Len(); n != 2 {
+		t.Fatalf("Len() = %d, want 2", n)
+	}
+
+	clock.Advance(2 * time.Minute)
+	expectFired(t, fired, "b")
+	expectIdle(t, fired)
+
+	clock.Advance(time.Minute)
+	expectFired(t, fired, "a")
+
+	// moving a job earlier wakes the scheduler up
+	s.Schedule("c", epoch.Add(time.Hour))
+	s.Schedule("c", epoch.Add(4*time.Minute))
+	clock.Advance(time.Minute)
+	expectFired(t, fired, "c")
+}
+
+func TestFireOnRestart(t *testing.T) {
+	clock := newFakeClock(epoch)
+
+	// jobs which became due while nothing ran, as reloaded after a restart
+	_, fired := run(t, clock, func(s *Scheduler) {
+		s.Schedule("late", epoch.Add(-time.Hour))
+		s.Schedule("now", epoch)
+		s.Schedule("later", epoch.Add(time.Hour))
+	})
+
+	expectFired(t, fired, "late", "now")
+	expectIdle(t, fired)
+
+	clock.Advance(time.Hour)
+	expectFired(t, fired, "later")
+}
+
+func TestSameTimeOrder(t *testing.T) {
+	clock := newFakeClock(epoch)
+	s, fired := run(t, clock, nil)
+
+	at := epoch.Add(time.Minute)
+	for _, id := range []string{"c", "a", "d", "b"} {
+		s.Schedule(id, at)
+	}
+	s.Schedule("e", at.Add(-time.Second))
+
+	clock.Advance(time.Minute)
+	expectFired(t, fired, "e", "a", "b", "c", "d")
+}
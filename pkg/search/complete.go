@@ -0,0 +1,413 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxSuggestions is the most suggestions Suggest returns
+const MaxSuggestions = 20
+
+const (
+	// maxKeyLen is how many runes of a phrase the trie holds, longer
+	// prefixes are checked against the phrases found at that depth
+	maxKeyLen = 24
+	// topSize is how many of the best phrases below it a node keeps, with
+	// room for a phrase matching at several of its words
+	topSize = 2 * MaxSuggestions
+)
+
+// Suggestion is a completion of a prefix, Count is how many documents share
+// the text
+type Suggestion struct {
+	Text  string
+	Kind  string
+	Count int
+}
+
+// Completer suggests phrases, e.g. titles, completing a prefix of their
+// start or of one of their words. A document has one or more phrases, e.g.
+// a title or tags. Every document belongs to a scope, e.g. a user, and only
+// the phrases of one scope are suggested. Each node of the trie keeps its
+// best phrases, so suggestions don't depend on how many phrases share a
+// prefix. It is safe for concurrent use.
+type Completer struct {
+	mu     sync.RWMutex
+	scopes map[string]*trie
+	docs   map[string]docPhrases
+}
+
+type docPhrases struct {
+	scope string
+	ps    []*phrase
+}
+
+type trie struct {
+	root    *trieNode
+	phrases map[string]*phrase
+}
+
+type trieNode struct {
+	children map[rune]*trieNode
+	// phrases whose keys end here, with the byte offset of the key in the
+	// normalized phrase, zero being its start
+	phrases map[*phrase]int
+	// top are the best entries below the node, best first
+	top []entry
+}
+
+type phrase struct {
+	text, norm, kind string
+	count            int
+}
+
+// entry is a phrase matching at a word offset
+type entry struct {
+	p   *phrase
+	off int
+}
+
+// better ranks the phrases matching at their start first, then the most
+// used ones, then the shortest
+func (e entry) better(o entry) bool {
+	switch {
+	case (e.off == 0) != (o.off == 0):
+		return e.off == 0
+	case e.p.count != o.p.count:
+		return e.p.count > o.p.count
+	case len(e.p.norm) != len(o.p.norm):
+		return len(e.p.norm) < len(o.p.norm)
+	case e.p.norm != o.p.norm:
+		return e.p.norm < o.p.norm
+	case e.p.kind != o.p.kind:
+		return e.p.kind < o.p.kind
+	}
+	return e.off < o.off
+}
+
+// NewCompleter returns an empty completer
+func NewCompleter() *Completer {
+	return &Completer{
+		scopes: make(map[string]*trie),
+		docs:   make(map[string]docPhrases),
+	}
+}
+
+// Put sets the phrase of document id, replacing its previous ones. An empty
+// text removes the document.
+func (c *Completer) Put(id, scope, kind, text string) {
+	c.PutAll(id, scope, kind, []string{text})
+}
+
+// PutAll sets the phrases of document id, all of one kind, replacing its
+// previous ones. Empty texts are left out, without any the document is
+// removed.
+func (c *Completer) PutAll(id, scope, kind string, texts []string) {
+	var (
+		clean, norms []string
+		seen         = make(map[string]bool, len(texts))
+	)
+	for _, text := range texts {
+		text = strings.Join(strings.Fields(text), " ")
+		norm := strings.ToLower(text)
+		if norm == "" || seen[norm] {
+			continue
+		}
+		seen[norm] = true
+		clean = append(clean, text)
+		norms = append(norms, norm)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.docs[id]; ok {
+		if old.same(scope, kind, norms) {
+			for i, p := range old.ps {
+				p.text = clean[i]
+			}
+			return
+		}
+		c.remove(id, old)
+	}
+	if len(norms) == 0 {
+		return
+	}
+
+	t, ok := c.scopes[scope]
+	if !ok {
+		t = &trie{root: &trieNode{}, phrases: make(map[string]*phrase)}
+		c.scopes[scope] = t
+	}
+
+	doc := docPhrases{scope: scope, ps: make([]*phrase, 0, len(norms))}
+	for i, norm := range norms {
+		key := kind + "\x00" + norm
+		p, ok := t.phrases[key]
+		if !ok {
+			p = &phrase{norm: norm, kind: kind}
+			t.phrases[key] = p
+		}
+		p.text = clean[i]
+		p.count++
+
+		// a new phrase is added, a used one moves up
+		for _, off := range wordStarts(norm) {
+			t.root.add(entry{p: p, off: off}, norm[off:], 0)
+		}
+		doc.ps = append(doc.ps, p)
+	}
+
+	c.docs[id] = doc
+}
+
+// Delete removes the phrases of document id
+func (c *Completer) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if old, ok := c.docs[id]; ok {
+		c.remove(id, old)
+	}
+}
+
+// same reports if d has the normalized phrases norms of kind in scope
+func (d docPhrases) same(scope, kind string, norms []string) bool {
+	if d.scope != scope || len(d.ps) != len(norms) {
+		return false
+	}
+	for i, p := range d.ps {
+		if p.kind != kind || p.norm != norms[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Suggest returns at most limit, and at most MaxSuggestions, phrases of
+// scope completing prefix. A prefix ending with a space only completes
+// whole words.
+func (c *Completer) Suggest(scope, prefix string, limit int) []Suggestion {
+	if limit <= 0 || limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+	prefix = normalizePrefix(prefix)
+	if prefix == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	t, ok := c.scopes[scope]
+	if !ok {
+		return nil
+	}
+
+	node := t.root
+	depth := 0
+	for _, r := range prefix {
+		if depth == maxKeyLen {
+			break
+		}
+		if node = node.children[r]; node == nil {
+			return nil
+		}
+		depth++
+	}
+
+	entries := node.top
+	if utf8.RuneCountInString(prefix) > maxKeyLen {
+		entries = node.matching(prefix)
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	suggestions := make([]Suggestion, 0, len(entries))
+	for _, e := range entries {
+		suggestions = append(suggestions, Suggestion{
+			Text:  e.p.text,
+			Kind:  e.p.kind,
+			Count: e.p.count,
+		})
+	}
+
+	return suggestions
+}
+
+// remove must be called with mu held
+func (c *Completer) remove(id string, d docPhrases) {
+	delete(c.docs, id)
+
+	t := c.scopes[d.scope]
+	for _, p := range d.ps {
+		p.count--
+		if p.count == 0 {
+			delete(t.phrases, p.kind+"\x00"+p.norm)
+		}
+		for _, off := range wordStarts(p.norm) {
+			t.root.demote(p, p.norm[off:], 0)
+		}
+	}
+	if len(t.phrases) == 0 {
+		delete(c.scopes, d.scope)
+	}
+}
+
+// add stores e under key below n and offers it to the nodes on the way
+func (n *trieNode) add(e entry, key string, depth int) {
+	n.offer(e)
+
+	if key == "" || depth == maxKeyLen {
+		if n.phrases == nil {
+			n.phrases = make(map[*phrase]int)
+		}
+		// keys cut at maxKeyLen may end on the same node, keep the first
+		if cur, ok := n.phrases[e.p]; !ok || e.off < cur {
+			n.phrases[e.p] = e.off
+		}
+		return
+	}
+
+	r, size := utf8.DecodeRuneInString(key)
+	child, ok := n.children[r]
+	if !ok {
+		if n.children == nil {
+			n.children = make(map[rune]*trieNode)
+		}
+		child = &trieNode{}
+		n.children[r] = child
+	}
+	child.add(e, key[size:], depth+1)
+}
+
+// demote updates the nodes on the key of p below n after p was used less
+// or, with no uses left, removes it. Nodes left empty are pruned, it
+// reports if n itself is.
+func (n *trieNode) demote(p *phrase, key string, depth int) bool {
+	if key == "" || depth == maxKeyLen {
+		if p.count == 0 {
+			delete(n.phrases, p)
+		}
+	} else {
+		r, size := utf8.DecodeRuneInString(key)
+		if child, ok := n.children[r]; ok && child.demote(p, key[size:], depth+1) {
+			delete(n.children, r)
+		}
+	}
+
+	for _, e := range n.top {
+		if e.p == p {
+			n.refresh()
+			break
+		}
+	}
+
+	return len(n.children) == 0 && len(n.phrases) == 0
+}
+
+// offer puts e into the top entries of n, if it ranks high enough
+func (n *trieNode) offer(e entry) {
+	for i, cur := range n.top {
+		if cur.p == e.p {
+			if cur.better(e) {
+				e = cur
+			}
+			n.top = append(n.top[:i], n.top[i+1:]...)
+			break
+		}
+	}
+
+	i := sort.Search(len(n.top), func(i int) bool { return e.better(n.top[i]) })
+	if i == topSize {
+		return
+	}
+	if len(n.top) < topSize {
+		n.top = append(n.top, entry{})
+	}
+	copy(n.top[i+1:], n.top[i:])
+	n.top[i] = e
+}
+
+// refresh recomputes the top entries of n from its phrases and the top
+// entries of its children
+func (n *trieNode) refresh() {
+	all := make([]entry, 0, len(n.phrases)+len(n.children)*topSize)
+	for p, off := range n.phrases {
+		all = append(all, entry{p: p, off: off})
+	}
+	for _, child := range n.children {
+		all = append(all, child.top...)
+	}
+
+	n.top = bestEntries(all, topSize)
+}
+
+// matching returns the best entries below n whose key starts with prefix
+func (n *trieNode) matching(prefix string) []entry {
+	var all []entry
+	var walk func(n *trieNode)
+	walk = func(n *trieNode) {
+		for p, off := range n.phrases {
+			if strings.HasPrefix(p.norm[off:], prefix) {
+				all = append(all, entry{p: p, off: off})
+			}
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(n)
+
+	return bestEntries(all, MaxSuggestions)
+}
+
+// bestEntries sorts entries and returns at most n of them, once per phrase
+func bestEntries(entries []entry, n int) []entry {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].better(entries[j]) })
+
+	best := make([]entry, 0, n)
+	seen := make(map[*phrase]bool, n)
+	for _, e := range entries {
+		if len(best) == n {
+			break
+		}
+		if !seen[e.p] {
+			seen[e.p] = true
+			best = append(best, e)
+		}
+	}
+
+	return best
+}
+
+// normalizePrefix lower cases s and collapses its white space, keeping a
+// trailing space
+func normalizePrefix(s string) string {
+	norm := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if norm != "" {
+		if r, _ := utf8.DecodeLastRuneInString(s); unicode.IsSpace(r) {
+			norm += " "
+		}
+	}
+
+	return norm
+}
+
+// wordStarts returns the byte offsets of the words of a normalized phrase,
+// the phrase start included
+func wordStarts(norm string) []int {
+	offsets := []int{0}
+	for _, t := range tokenize(norm) {
+		if t.start > 0 {
+			offsets = append(offsets, t.start)
+		}
+	}
+
+	return offsets
+}
@@ -0,0 +1,126 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// snippetLen is roughly how long a snippet is in bytes, values up to
+	// it are highlighted whole
+	snippetLen = 160
+	// snippetLead is how much text a snippet shows before its first match
+	snippetLead = 40
+)
+
+// Highlight is an HTML escaped snippet of a field with the matched words
+// wrapped in <mark> tags
+type Highlight struct {
+	Field   string
+	Snippet string
+}
+
+// highlight returns a snippet of each field of doc matching groups, for
+// fields with several values the one with the most matches is used
+func (idx *Index) highlight(doc *document, groups []group) []Highlight {
+	stems := make(map[string]bool)
+	for _, g := range groups {
+		for stem := range g {
+			if _, ok := doc.freqs[stem]; ok {
+				stems[stem] = true
+			}
+		}
+	}
+
+	var highlights []Highlight
+	for i, values := range doc.values {
+		var (
+			best        string
+			bestTokens  []token
+			bestMatches int
+		)
+		for _, value := range values {
+			tokens := tokenize(value)
+			matches := 0
+			for _, t := range tokens {
+				if stems[Stem(t.word)] {
+					matches++
+				}
+			}
+			if matches > bestMatches {
+				best, bestTokens, bestMatches = value, tokens, matches
+			}
+		}
+		if bestMatches == 0 {
+			continue
+		}
+
+		highlights = append(highlights, Highlight{
+			Field:   idx.fields[i].Name,
+			Snippet: snippet(best, bestTokens, stems),
+		})
+	}
+
+	return highlights
+}
+
+// snippet cuts a window of about snippetLen bytes around the first match
+// out of text, on word boundaries
+func snippet(text string, tokens []token, stems map[string]bool) string {
+	matched := make([]bool, len(tokens))
+	first := -1
+	for i, t := range tokens {
+		if stems[Stem(t.word)] {
+			matched[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+
+	from, to := 0, len(tokens)
+	if len(text) > snippetLen {
+		from = first
+		for from > 0 && tokens[first].start-tokens[from-1].start <= snippetLead {
+			from--
+		}
+		to = from
+		for to < len(tokens) && tokens[to].end-tokens[from].start <= snippetLen {
+			to++
+		}
+		if to <= first {
+			to = first + 1
+		}
+	}
+
+	start, end := 0, len(text)
+	if from > 0 {
+		start = tokens[from].start
+	}
+	if to < len(tokens) {
+		end = tokens[to-1].end
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	pos := start
+	for i := from; i < to; i++ {
+		if !matched[i] {
+			continue
+		}
+		t := tokens[i]
+		sb.WriteString(html.EscapeString(text[pos:t.start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[t.start:t.end]))
+		sb.WriteString("</mark>")
+		pos = t.end
+	}
+	sb.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,414 @@
+// Package search is an in-memory full-text index. Documents are made of
+// named fields, words are stemmed for English, queries match words by
+// prefix too and results are ranked with BM25 and come with highlighted
+// snippets.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BM25 parameters, the usual defaults
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+const (
+	// prefixWeight scales the score of words matched by prefix only, so
+	// exact matches rank first
+	prefixWeight = 0.5
+	// minPrefixLen is the shortest query word which is matched by prefix
+	minPrefixLen = 2
+	// maxExpansions limits the words one query word matches by prefix
+	maxExpansions = 50
+	// maxWordLen skips words which are more likely data than text
+	maxWordLen = 64
+)
+
+// Field of the documents in an index, Weight scales how much matches in it
+// count, e.g. a title over a description
+type Field struct {
+	Name   string
+	Weight float64
+}
+
+// Hit is a document matching a query
+type Hit struct {
+	ID         string
+	Score      float64
+	Highlights []Highlight
+}
+
+// Index holds documents of scopes, e.g. users, and searches the documents
+// of one scope. Ranking statistics span all of them. It is safe for
+// concurrent use.
+type Index struct {
+	fields     []Field
+	fieldIndex map[string]int
+
+	mu   sync.RWMutex
+	docs map[string]*document
+	// postings lists the documents containing a stem
+	postings map[string]map[string]struct{}
+	// words maps every indexed word to its stem and the number of
+	// documents it is in, sorted keeps them in order for prefix lookups
+	words  map[string]*wordInfo
+	sorted []string
+	// fieldLen is the total length of each field in words
+	fieldLen []int
+}
+
+type document struct {
+	scope  string
+	values [][]string
+	// lengths is the length of each field in words
+	lengths []int
+	// freqs is how often a stem occurs in each field
+	freqs map[string][]int
+	words map[string]struct{}
+}
+
+type wordInfo struct {
+	stem string
+	docs int
+}
+
+// NewIndex returns an empty index of documents with fields
+func NewIndex(fields ...Field) *Index {
+	idx := &Index{
+		fields:     fields,
+		fieldIndex: make(map[string]int, len(fields)),
+		docs:       make(map[string]*document),
+		postings:   make(map[string]map[string]struct{}),
+		words:      make(map[string]*wordInfo),
+		fieldLen:   make([]int, len(fields)),
+	}
+	for i, f := range fields {
+		idx.fieldIndex[f.Name] = i
+	}
+
+	return idx
+}
+
+// Put indexes the values of fields of a document of scope. Fields left out
+// of values keep what was indexed for them before, unknown fields are
+// ignored.
+func (idx *Index) Put(id, scope string, values map[string][]string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	merged := make([][]string, len(idx.fields))
+	if old, ok := idx.docs[id]; ok {
+		copy(merged, old.values)
+		idx.remove(id, old)
+	}
+	for name, v := range values {
+		if i, ok := idx.fieldIndex[name]; ok {
+			merged[i] = v
+		}
+	}
+
+	idx.add(id, scope, merged)
+}
+
+// Delete removes a document from the index
+func (idx *Index) Delete(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if old, ok := idx.docs[id]; ok {
+		idx.remove(id, old)
+	}
+}
+
+// Len returns the number of indexed documents
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	return len(idx.docs)
+}
+
+// Search returns the documents of scope matching every word of query, best
+// first, starting at offset and at most limit of them, with the total
+// number of matches. A limit of zero returns all of them.
+func (idx *Index) Search(scope, query string, offset, limit int) ([]Hit, int) {
+	return idx.search(scope, query, func(hits []Hit) int { return offset }, limit)
+}
+
+// SearchAfter is Search starting after the hit with score and id, hits
+// with equal scores are ordered by id
+func (idx *Index) SearchAfter(scope, query string, score float64, id string, limit int) ([]Hit, int) {
+	return idx.search(scope, query, func(hits []Hit) int {
+		return sort.Search(len(hits), func(i int) bool {
+			return hits[i].Score < score || hits[i].Score == score && hits[i].ID > id
+		})
+	}, limit)
+}
+
+// search ranks the matches of query and returns limit of them from the one
+// start returns the index of
+func (idx *Index) search(scope, query string, start func(hits []Hit) int, limit int) ([]Hit, int) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	groups := idx.parse(query)
+	if len(groups) == 0 {
+		return nil, 0
+	}
+
+	// every group must match, so start from its smallest one
+	var candidates map[string]struct{}
+	for _, g := range groups {
+		if docs := idx.matching(g); candidates == nil || len(docs) < len(candidates) {
+			candidates = docs
+		}
+	}
+	for id := range candidates {
+		if idx.docs[id].scope != scope {
+			delete(candidates, id)
+			continue
+		}
+		for _, g := range groups {
+			if !idx.matches(id, g) {
+				delete(candidates, id)
+				break
+			}
+		}
+	}
+
+	hits := make([]Hit, 0, len(candidates))
+	for id := range candidates {
+		hits = append(hits, Hit{
+			ID:    id,
+			Score: idx.score(idx.docs[id], groups),
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	total := len(hits)
+	offset := start(hits)
+	if offset >= total {
+		return []Hit{}, total
+	}
+	hits = hits[offset:]
+	if limit > 0 && limit < len(hits) {
+		hits = hits[:limit]
+	}
+
+	for i := range hits {
+		hits[i].Highlights = idx.highlight(idx.docs[hits[i].ID], groups)
+	}
+
+	return hits, total
+}
+
+// group is the stems one query word matches, with their weight
+type group map[string]float64
+
+// parse turns a query into a group per distinct word
+func (idx *Index) parse(query string) []group {
+	var words []string
+	seen := make(map[string]bool)
+	for _, t := range tokenize(query) {
+		if !seen[t.word] && !isLongerThan(t.word, maxWordLen) {
+			seen[t.word] = true
+			words = append(words, t.word)
+		}
+	}
+
+	var kept []string
+	for _, w := range words {
+		if !stopWords[w] {
+			kept = append(kept, w)
+		}
+	}
+	if len(kept) > 0 {
+		words = kept
+	}
+
+	groups := make([]group, 0, len(words))
+	for _, w := range words {
+		g := group{Stem(w): 1}
+		if len(w) >= minPrefixLen {
+			idx.expand(w, g)
+		}
+		groups = append(groups, g)
+	}
+
+	return groups
+}
+
+// expand adds the stems of indexed words starting with prefix to g
+func (idx *Index) expand(prefix string, g group) {
+	n := 0
+	for i := sort.SearchStrings(idx.sorted, prefix); i < len(idx.sorted) && n < maxExpansions; i++ {
+		word := idx.sorted[i]
+		if !strings.HasPrefix(word, prefix) {
+			break
+		}
+		stem := idx.words[word].stem
+		if _, ok := g[stem]; !ok {
+			g[stem] = prefixWeight
+			n++
+		}
+	}
+}
+
+func (idx *Index) matching(g group) map[string]struct{} {
+	docs := make(map[string]struct{})
+	for stem := range g {
+		for id := range idx.postings[stem] {
+			docs[id] = struct{}{}
+		}
+	}
+
+	return docs
+}
+
+func (idx *Index) matches(id string, g group) bool {
+	for stem := range g {
+		if _, ok := idx.postings[stem][id]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// score sums the best BM25 score of each group, the field frequencies are
+// combined by weight before saturation as in BM25F
+func (idx *Index) score(doc *document, groups []group) float64 {
+	n := float64(len(idx.docs))
+
+	var total float64
+	for _, g := range groups {
+		var best float64
+		for stem, weight := range g {
+			freqs, ok := doc.freqs[stem]
+			if !ok {
+				continue
+			}
+
+			var tf float64
+			for i, f := range freqs {
+				if f == 0 {
+					continue
+				}
+				avg := float64(idx.fieldLen[i]) / n
+				if avg == 0 {
+					avg = 1
+				}
+				norm := 1 - b + b*float64(doc.lengths[i])/avg
+				tf += idx.fields[i].Weight * float64(f) / norm
+			}
+
+			df := float64(len(idx.postings[stem]))
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			if s := weight * idf * tf * (k1 + 1) / (tf + k1); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+
+	return total
+}
+
+// add must be called with mu held
+func (idx *Index) add(id, scope string, values [][]string) {
+	doc := &document{
+		scope:   scope,
+		values:  values,
+		lengths: make([]int, len(idx.fields)),
+		freqs:   make(map[string][]int),
+		words:   make(map[string]struct{}),
+	}
+
+	for i, field := range values {
+		for _, value := range field {
+			for _, t := range tokenize(value) {
+				if isLongerThan(t.word, maxWordLen) {
+					continue
+				}
+				doc.lengths[i]++
+
+				stem := Stem(t.word)
+				freqs, ok := doc.freqs[stem]
+				if !ok {
+					freqs = make([]int, len(idx.fields))
+					doc.freqs[stem] = freqs
+				}
+				freqs[i]++
+
+				if _, ok := doc.words[t.word]; !ok {
+					doc.words[t.word] = struct{}{}
+					idx.addWord(t.word, stem)
+				}
+			}
+		}
+		idx.fieldLen[i] += doc.lengths[i]
+	}
+
+	for stem := range doc.freqs {
+		docs, ok := idx.postings[stem]
+		if !ok {
+			docs = make(map[string]struct{})
+			idx.postings[stem] = docs
+		}
+		docs[id] = struct{}{}
+	}
+
+	idx.docs[id] = doc
+}
+
+// remove must be called with mu held
+func (idx *Index) remove(id string, doc *document) {
+	for stem := range doc.freqs {
+		delete(idx.postings[stem], id)
+		if len(idx.postings[stem]) == 0 {
+			delete(idx.postings, stem)
+		}
+	}
+	for word := range doc.words {
+		idx.removeWord(word)
+	}
+	for i, l := range doc.lengths {
+		idx.fieldLen[i] -= l
+	}
+
+	delete(idx.docs, id)
+}
+
+func (idx *Index) addWord(word, stem string) {
+	if w, ok := idx.words[word]; ok {
+		w.docs++
+		return
+	}
+
+	idx.words[word] = &wordInfo{stem: stem, docs: 1}
+	i := sort.SearchStrings(idx.sorted, word)
+	idx.sorted = append(idx.sorted, "")
+	copy(idx.sorted[i+1:], idx.sorted[i:])
+	idx.sorted[i] = word
+}
+
+func (idx *Index) removeWord(word string) {
+	w := idx.words[word]
+	if w.docs--; w.docs > 0 {
+		return
+	}
+
+	delete(idx.words, word)
+	i := sort.SearchStrings(idx.sorted, word)
+	idx.sorted = append(idx.sorted[:i], idx.sorted[i+1:]...)
+}
@@ -0,0 +1,247 @@
+package search
+
+// Stem reduces an English word to its stem with the Porter algorithm, so
+// "connected", "connecting" and "connection" all become "connect". word
+// must be lower case, words of up to two letters and words with anything
+// but ascii letters are returned as they are.
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := stemmer{b: []byte(word)}
+	s.step1a()
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+
+	return string(s.b)
+}
+
+// stemmer follows the reference implementation by Martin Porter, j is the
+// end of the stem the current rule looks at
+type stemmer struct {
+	b []byte
+	j int
+}
+
+// cons tells if b[i] is a consonant
+func (s *stemmer) cons(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.cons(i-1)
+	default:
+		return true
+	}
+}
+
+// m measures the number of vowel-consonant sequences in b[0:j]
+func (s *stemmer) m() int {
+	n, i := 0, 0
+	for ; i < s.j && s.cons(i); i++ {
+	}
+	for i < s.j {
+		for ; i < s.j && !s.cons(i); i++ {
+		}
+		if i >= s.j {
+			break
+		}
+		n++
+		for ; i < s.j && s.cons(i); i++ {
+		}
+	}
+
+	return n
+}
+
+// vowelInStem tells if b[0:j] contains a vowel
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i < s.j; i++ {
+		if !s.cons(i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// doublec tells if b ends with a double consonant at i
+func (s *stemmer) doublec(i int) bool {
+	return i >= 1 && s.b[i] == s.b[i-1] && s.cons(i)
+}
+
+// cvc tells if b[i-2:i+1] is consonant-vowel-consonant and the last
+// consonant is not w, x or y, as in -hop but not -how
+func (s *stemmer) cvc(i int) bool {
+	if i < 2 || !s.cons(i) || s.cons(i-1) || !s.cons(i-2) {
+		return false
+	}
+
+	switch s.b[i] {
+	case 'w', 'x', 'y':
+		return false
+	}
+
+	return true
+}
+
+// ends tells if b ends with suffix and sets j to where the suffix starts
+func (s *stemmer) ends(suffix string) bool {
+	n := len(suffix)
+	if n > len(s.b) || string(s.b[len(s.b)-n:]) != suffix {
+		return false
+	}
+	s.j = len(s.b) - n
+
+	return true
+}
+
+// setTo replaces b[j:] with r
+func (s *stemmer) setTo(r string) {
+	s.b = append(s.b[:s.j], r...)
+}
+
+// r replaces the suffix with r when the stem before it has m > 0
+func (s *stemmer) r(r string) {
+	if s.m() > 0 {
+		s.setTo(r)
+	}
+}
+
+// step1a handles plurals: caresses -> caress, ponies -> poni, cats -> cat
+func (s *stemmer) step1a() {
+	switch {
+	case s.ends("sses"):
+		s.setTo("ss")
+	case s.ends("ies"):
+		s.setTo("i")
+	case s.ends("ss"):
+	case s.ends("s"):
+		s.setTo("")
+	}
+}
+
+// step1b handles -ed and -ing: agreed -> agree, plastered -> plaster,
+// motoring -> motor, hopping -> hop, filing -> file
+func (s *stemmer) step1b() {
+	if s.ends("eed") {
+		if s.m() > 0 {
+			s.setTo("ee")
+		}
+		return
+	}
+
+	if !(s.ends("ed") || s.ends("ing")) || !s.vowelInStem() {
+		return
+	}
+	s.setTo("")
+
+	s.j = len(s.b)
+	switch {
+	case s.ends("at"):
+		s.setTo("ate")
+	case s.ends("bl"):
+		s.setTo("ble")
+	case s.ends("iz"):
+		s.setTo("ize")
+	case s.doublec(len(s.b) - 1):
+		switch s.b[len(s.b)-1] {
+		case 'l', 's', 'z':
+		default:
+			s.b = s.b[:len(s.b)-1]
+		}
+	default:
+		s.j = len(s.b)
+		if s.m() == 1 && s.cvc(len(s.b)-1) {
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c turns a terminal y into i when there is another vowel in the stem
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+var step2Suffixes = []struct{ suffix, replacement string }{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"},
+	{"izer", "ize"}, {"bli", "ble"}, {"alli", "al"}, {"entli", "ent"},
+	{"eli", "e"}, {"ousli", "ous"}, {"ization", "ize"}, {"ation", "ate"},
+	{"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"}, {"fulness", "ful"},
+	{"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+	{"logi", "log"},
+}
+
+// step2 maps double suffixes to single ones: -ization -> -ize
+func (s *stemmer) step2() {
+	for _, rule := range step2Suffixes {
+		if s.ends(rule.suffix) {
+			s.r(rule.replacement)
+			return
+		}
+	}
+}
+
+var step3Suffixes = []struct{ suffix, replacement string }{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"},
+	{"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 handles -ic-, -full, -ness etc.
+func (s *stemmer) step3() {
+	for _, rule := range step3Suffixes {
+		if s.ends(rule.suffix) {
+			s.r(rule.replacement)
+			return
+		}
+	}
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment",
+	"ent", "ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 drops -ant, -ence etc. in context <c>vcvc<v>
+func (s *stemmer) step4() {
+	for _, suffix := range step4Suffixes {
+		if !s.ends(suffix) {
+			continue
+		}
+		if suffix == "ion" && (s.j == 0 || s.b[s.j-1] != 's' && s.b[s.j-1] != 't') {
+			return
+		}
+		if s.m() > 1 {
+			s.b = s.b[:s.j]
+		}
+		return
+	}
+}
+
+// step5 drops a final -e and turns -ll into -l when m > 1
+func (s *stemmer) step5() {
+	s.j = len(s.b)
+	if s.b[len(s.b)-1] == 'e' {
+		s.j = len(s.b) - 1
+		if m := s.m(); m > 1 || m == 1 && !s.cvc(len(s.b)-2) {
+			s.b = s.b[:len(s.b)-1]
+		}
+	}
+
+	s.j = len(s.b)
+	if s.b[len(s.b)-1] == 'l' && s.doublec(len(s.b)-1) && s.m() > 1 {
+		s.b = s.b[:len(s.b)-1]
+	}
+}
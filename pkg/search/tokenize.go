@@ -0,0 +1,63 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a lower cased word of a text and its byte offsets
+type token struct {
+	word       string
+	start, end int
+}
+
+// stopWords are dropped from queries which have other words, they match
+// too many documents to narrow anything down
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "if": true, "in": true,
+	"into": true, "is": true, "it": true, "no": true, "not": true, "of": true,
+	"on": true, "or": true, "such": true, "that": true, "the": true,
+	"their": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "to": true, "was": true, "will": true, "with": true,
+}
+
+// tokenize splits text into runs of letters and digits
+func tokenize(text string) []token {
+	var (
+		tokens []token
+		start  = -1
+	)
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+
+	return tokens
+}
+
+func newToken(text string, start, end int) token {
+	return token{
+		word:  strings.ToLower(text[start:end]),
+		start: start,
+		end:   end,
+	}
+}
+
+// isLongerThan tells if s has more than n runes
+func isLongerThan(s string, n int) bool {
+	return len(s) > n && utf8.RuneCountInString(s) > n
+}
@@ -0,0 +1,98 @@
+// Package sortspec parses sort specifications like
+// "priority|desc,due_at|asc": comma separated keys, each with an optional
+// asc or desc direction, checked against the sortable keys of a resource.
+package sortspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	asc  = "asc"
+	desc = "desc"
+)
+
+// Key is one key of a sort specification
+type Key struct {
+	Field string
+	Desc  bool
+}
+
+// Schema is the sortable keys of a resource
+type Schema struct {
+	keys    []string
+	aliases map[string]string
+}
+
+// NewSchema returns a schema accepting keys
+func NewSchema(keys ...string) *Schema {
+	s := &Schema{
+		keys:    keys,
+		aliases: make(map[string]string, len(keys)),
+	}
+	for _, key := range keys {
+		s.aliases[key] = key
+	}
+
+	return s
+}
+
+// Alias accepts alias as another name of key
+func (s *Schema) Alias(alias, key string) *Schema {
+	s.aliases[alias] = key
+	return s
+}
+
+// Keys returns the sortable keys, aliases left out
+func (s *Schema) Keys() []string {
+	return s.keys
+}
+
+// Check returns the key named field, which may be an alias, or an error
+// listing the allowed keys
+func (s *Schema) Check(field string) (string, error) {
+	key, ok := s.aliases[field]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field %q, allowed: %s", field, strings.Join(s.keys, ", "))
+	}
+
+	return key, nil
+}
+
+// Parse parses spec, an empty spec returns no keys. Aliases are replaced
+// by their keys and a key may be given once.
+func (s *Schema) Parse(spec string) ([]Key, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(spec, ",")
+	keys := make([]Key, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		field, direction := strings.TrimSpace(part), asc
+		if i := strings.Index(field, "|"); i >= 0 {
+			field, direction = strings.TrimSpace(field[:i]), strings.ToLower(strings.TrimSpace(field[i+1:]))
+		}
+		if field == "" {
+			return nil, fmt.Errorf("empty sort field in %q", spec)
+		}
+
+		key, err := s.Check(field)
+		if err != nil {
+			return nil, err
+		}
+		if direction != asc && direction != desc {
+			return nil, fmt.Errorf("invalid sort direction %q of %s, use %s or %s", direction, field, asc, desc)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("sort field %s is given twice", key)
+		}
+		seen[key] = true
+
+		keys = append(keys, Key{Field: key, Desc: direction == desc})
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/storage/blob"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// sniffLen is how much content http.DetectContentType looks at
+	sniffLen = 512
+	// attachmentChunkSize is the size of the chunks sent on download
+	attachmentChunkSize = 64 << 10
+	maxFileNameLen      = 255
+)
+
+var errTooLarge = errors.New("attachment is too large")
+
+// UploadTodoAttachment stores the content streamed after the first message
+// and attaches it to the todo
+func (s *todoService) UploadTodoAttachment(stream pb.TodoService_UploadTodoAttachmentServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "attachment is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	req := first.Attachment
+	if req == nil {
+		return status.Error(codes.InvalidArgument, "the first message must carry the attachment")
+	}
+	req.FileName = cleanFileName(req.FileName)
+	if req.FileName == "" {
+		return status.Error(codes.InvalidArgument, "file_name is required")
+	}
+	if req.UploadedBy == "" {
+		return status.Error(codes.InvalidArgument, "uploaded_by is required")
+	}
+
+	todo, err := s.liveTodo(req.TodoId)
+	if err != nil {
+		return s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkTodoOwner(todo, req.UploadedBy, "attach files to a todo"); err != nil {
+		return err
+	}
+
+	limit, byQuota, err := s.uploadLimit(req.UploadedBy)
+	if err != nil {
+		return err
+	}
+
+	content := bufio.NewReaderSize(&sizeLimiter{
+		r:     &chunkReader{stream: stream, buf: first.Data},
+		limit: limit,
+	}, sniffLen)
+
+	head, err := content.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return s.uploadError(err, limit, byQuota)
+	}
+	req.ContentType = contentType(head, req.FileName)
+
+	s.blobMu.RLock()
+	defer s.blobMu.RUnlock()
+
+	req.Sha256, req.Size, err = s.blobs.Put(content)
+	if err != nil {
+		return s.uploadError(err, limit, byQuota)
+	}
+
+	req.Id = uuid.New().String()
+	req.CreatedAt = timestamppb.Now()
+
+	attachment, err := s.storage.Attachment().Create(req)
+	if err != nil {
+		// the content stays behind when nothing else references it, which
+		// is harmless and rare enough to not hold up other uploads for
+		return s.handleAttachmentStorageError(err, "failed to create attachment")
+	}
+
+	return stream.SendAndClose(attachment)
+}
+
+// DownloadTodoAttachment sends the attachment first and then its content
+func (s *todoService) DownloadTodoAttachment(req *pb.GetTodoAttachmentRequest, stream pb.TodoService_DownloadTodoAttachmentServer) error {
+	attachment, _, err := s.todoAttachment(req.Id, req.TodoId)
+	if err != nil {
+		return err
+	}
+
+	content, err := s.blobs.Open(attachment.Sha256)
+	if err != nil {
+		return s.handleAttachmentStorageError(err, "failed to open attachment content")
+	}
+	defer content.Close()
+
+	if err = stream.Send(&pb.AttachmentChunk{Attachment: attachment}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, attachmentChunkSize)
+	for {
+		n, err := content.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.AttachmentChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return s.handleAttachmentStorageError(err, "failed to read attachment content")
+		}
+	}
+}
+
+func (s *todoService) ListTodoAttachments(ctx context.Context, req *pb.ListTodoAttachmentsRequest) (*pb.ListTodoAttachmentsResponse, error) {
+	if _, err := s.liveTodo(req.TodoId); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	query := proto.Clone(req).(*pb.ListTodoAttachmentsRequest)
+	query.Limit, query.Cursor = 0, ""
+	p, err := s.newPager(query, req.Cursor, 0, req.Limit, repo.DefaultSort)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.storage.Attachment().GetAll(req.TodoId)
+	if err != nil {
+		return nil, s.handleAttachmentStorageError(err, "failed to list attachments")
+	}
+	start, end, next := p.slice(attachments, len(attachments), func(i int) ([]interface{}, string) {
+		return []interface{}{attachments[i].CreatedAt}, attachments[i].Id
+	})
+
+	return &pb.ListTodoAttachmentsResponse{
+		Attachments: attachments[start:end],
+		Count:       int64(len(attachments)),
+		NextCursor:  next,
+	}, nil
+}
+
+func (s *todoService) DeleteTodoAttachment(ctx context.Context, req *pb.GetTodoAttachmentRequest) (*emptypb.Empty, error) {
+	attachment, todo, err := s.todoAttachment(req.Id, req.TodoId)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkTodoOwner(todo, req.ActorId, "delete attachments of a todo"); err != nil {
+		return nil, err
+	}
+
+	if err = s.deleteAttachment(attachment); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// deleteAttachments deletes every attachment of a todo
+func (s *todoService) deleteAttachments(todoID string) error {
+	attachments, err := s.storage.Attachment().GetAll(todoID)
+	if err != nil {
+		return s.handleAttachmentStorageError(err, "failed to list attachments")
+	}
+
+	for _, attachment := range attachments {
+		if err = s.deleteAttachment(attachment); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteAttachment deletes the metadata of an attachment and its content,
+// unless another attachment has the same content
+func (s *todoService) deleteAttachment(attachment *pb.TodoAttachment) error {
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+
+	if err := s.storage.Attachment().Delete(attachment.Id); err != nil {
+		return s.handleAttachmentStorageError(err, "failed to delete attachment")
+	}
+
+	refs, err := s.storage.Attachment().References(attachment.Sha256)
+	if err != nil {
+		return s.handleAttachmentStorageError(err, "failed to count attachment references")
+	}
+	if refs > 0 {
+		return nil
+	}
+
+	if err = s.blobs.Delete(attachment.Sha256); err != nil && !errors.Is(err, blob.ErrNotFound) {
+		// the metadata is gone, so this only leaves unused content behind
+		s.log.Error("failed to delete attachment content", logger.Error(err))
+	}
+
+	return nil
+}
+
+// todoAttachment returns an attachment of a live todo and the todo
+func (s *todoService) todoAttachment(id, todoID string) (*pb.TodoAttachment, *pb.TodoModel, error) {
+	attachment, err := s.storage.Attachment().Get(id)
+	if err != nil {
+		return nil, nil, s.handleAttachmentStorageError(err, "failed to get attachment")
+	}
+	if todoID != "" && attachment.TodoId != todoID {
+		return nil, nil, status.Error(codes.NotFound, "attachment not found")
+	}
+	todo, err := s.liveTodo(attachment.TodoId)
+	if err != nil {
+		return nil, nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	return attachment, todo, nil
+}
+
+// uploadLimit returns how many bytes a user may upload now, byQuota tells
+// if the limit comes from the user's quota rather than the file size limit
+func (s *todoService) uploadLimit(userID string) (limit int64, byQuota bool, err error) {
+	limit = s.attachmentMaxSize
+	if s.attachmentUserQuota <= 0 {
+		return limit, false, nil
+	}
+
+	used, err := s.storage.Attachment().UsedBytes(userID)
+	if err != nil {
+		return 0, false, s.handleAttachmentStorageError(err, "failed to count used bytes")
+	}
+
+	if left := s.attachmentUserQuota - used; left < limit {
+		if left <= 0 {
+			return 0, true, errorWithReason(codes.ResourceExhausted, reasonQuotaExceeded,
+				"attachment quota of %d bytes is used up", s.attachmentUserQuota)
+		}
+		return left, true, nil
+	}
+
+	return limit, false, nil
+}
+
+// uploadError converts an error met while reading an upload
+func (s *todoService) uploadError(err error, limit int64, byQuota bool) error {
+	switch {
+	case errors.Is(err, errTooLarge) && byQuota:
+		return errorWithReason(codes.ResourceExhausted, reasonQuotaExceeded,
+			"attachment exceeds the %d bytes left of the quota", limit)
+	case errors.Is(err, errTooLarge):
+		return errorWithReason(codes.ResourceExhausted, reasonFileTooLarge,
+			"attachment exceeds %d bytes", limit)
+	}
+
+	// errors of the stream already carry a status
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	return s.handleAttachmentStorageError(err, "failed to store attachment content")
+}
+
+func (s *todoService) handleAttachmentStorageError(err error, message string) error {
+	if errors.Is(err, repo.ErrNotFound) || errors.Is(err, blob.ErrNotFound) {
+		return status.Error(codes.NotFound, "attachment not found")
+	}
+
+	return s.handleStorageError(err, message)
+}
+
+// contentType sniffs the content type from the head of the content and
+// falls back to the file extension when the content is not recognized
+func contentType(head []byte, fileName string) string {
+	ct := http.DetectContentType(head)
+	if ct != "application/octet-stream" {
+		return ct
+	}
+
+	if byExt := mime.TypeByExtension(filepath.Ext(fileName)); byExt != "" {
+		return byExt
+	}
+
+	return ct
+}
+
+// cleanFileName drops any directories from a client supplied file name
+func cleanFileName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "." || name == "/" {
+		return ""
+	}
+	// long names keep their end, which has the extension
+	if len(name) > maxFileNameLen {
+		name = name[len(name)-maxFileNameLen:]
+		for len(name) > 0 && !utf8.RuneStart(name[0]) {
+			name = name[1:]
+		}
+	}
+
+	return name
+}
+
+// chunkReader reads the data of the messages of an upload stream
+type chunkReader struct {
+	stream pb.TodoService_UploadTodoAttachmentServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.Data
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// sizeLimiter fails with errTooLarge once more than limit bytes are read
+type sizeLimiter struct {
+	r     io.Reader
+	n     int64
+	limit int64
+}
+
+func (l *sizeLimiter) Read(p []byte) (int, error) {
+	if l.n > l.limit {
+		return 0, errTooLarge
+	}
+
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return n, errTooLarge
+	}
+
+	return n, err
+}
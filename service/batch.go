@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"io"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchDeadlineReserve is kept of the deadline of a batch to send the
+// results back, items are not started within it
+const batchDeadlineReserve = 200 * time.Millisecond
+
+func (s *todoService) BulkCreateTodos(stream pb.TodoService_BulkCreateTodosServer) error {
+	res := &pb.BatchTodosResponse{}
+
+	for index := int64(0); ; index++ {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(res)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = batchBudget(stream.Context()); err != nil {
+			addResult(res, index, "", nil, err)
+			continue
+		}
+
+		todo, err := s.CreateTodo(stream.Context(), req)
+		addResult(res, index, todo.GetId(), todo, err)
+	}
+}
+
+func (s *todoService) BatchUpdateTodos(ctx context.Context, req *pb.BatchUpdateTodosRequest) (*pb.BatchTodosResponse, error) {
+	res := &pb.BatchTodosResponse{}
+
+	for index, item := range req.Todos {
+		if err := batchBudget(ctx); err != nil {
+			addResult(res, int64(index), item.Id, nil, err)
+			continue
+		}
+
+		todo, err := s.UpdateTodo(ctx, item)
+		addResult(res, int64(index), item.Id, todo, err)
+	}
+
+	return res, nil
+}
+
+func (s *todoService) BatchDeleteTodos(ctx context.Context, req *pb.BatchDeleteTodosRequest) (*pb.BatchTodosResponse, error) {
+	res := &pb.BatchTodosResponse{}
+
+	for index, id := range req.Ids {
+		if err := batchBudget(ctx); err != nil {
+			addResult(res, int64(index), id, nil, err)
+			continue
+		}
+
+		_, err := s.DeleteTodo(ctx, &pb.DeleteTodoRequest{Id: id, ActorId: req.ActorId})
+		addResult(res, int64(index), id, nil, err)
+	}
+
+	return res, nil
+}
+
+// batchBudget returns an error for the items of a batch which are not
+// started, because the batch was canceled or its deadline is too close to
+// finish them. The items done before are committed and keep their results.
+func batchBudget(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < batchDeadlineReserve {
+		return status.Error(codes.DeadlineExceeded, "the deadline of the batch was reached before this item")
+	}
+
+	return nil
+}
+
+// addResult records the outcome of one batch item, a failed item does not
+// stop the rest of the batch
+func addResult(res *pb.BatchTodosResponse, index int64, id string, todo *pb.TodoModel, err error) {
+	result := &pb.TodoResult{
+		Index: index,
+		Id:    id,
+		Todo:  todo,
+	}
+
+	if err != nil {
+		st := status.Convert(err)
+		result.Code = int32(st.Code())
+		result.Message = st.Message()
+		result.Todo = nil
+		res.Failed++
+	} else {
+		res.Succeeded++
+	}
+
+	res.Results = append(res.Results, result)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// cancelingStorage cancels a batch after a number of todo updates, like a
+// deadline hitting in the middle of the batch
+type cancelingStorage struct {
+	storage.StorageI
+	todos *cancelingTodos
+}
+
+func (s cancelingStorage) Todo() repo.TodoStorageI {
+	return s.todos
+}
+
+type cancelingTodos struct {
+	repo.TodoStorageI
+	writes int
+	cancel context.CancelFunc
+}
+
+func (r *cancelingTodos) Update(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	res, err := r.TodoStorageI.Update(todo)
+	if r.writes--; r.writes == 0 {
+		r.cancel()
+	}
+	return res, err
+}
+
+// newCancelingService returns a service whose ctx is canceled after the
+// writes set on its todo storage
+func newCancelingService(t *testing.T) (*todoService, *cancelingTodos, context.Context) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	strg := storage.NewStorageMemory()
+	todos := &cancelingTodos{TodoStorageI: strg.Todo(), cancel: cancel}
+	s := newReminderService(t, cancelingStorage{StorageI: strg, todos: todos}, &fakeClock{now: time.Now()})
+
+	return s, todos, ctx
+}
+
+func createTodos(t *testing.T, s *todoService, n int) []*pb.TodoModel {
+	t.Helper()
+
+	todos := make([]*pb.TodoModel, n)
+	for i := range todos {
+		todos[i] = createReminded(t, s, time.Now().Add(time.Hour))
+	}
+
+	return todos
+}
+
+// expectCodes checks the codes of the results of a batch by index
+func expectCodes(t *testing.T, res *pb.BatchTodosResponse, want ...codes.Code) {
+	t.Helper()
+
+	if len(res.Results) != len(want) {
+		t.Fatalf("%d results, want %d", len(res.Results), len(want))
+	}
+	for i, result := range res.Results {
+		if result.Index != int64(i) || codes.Code(result.Code) != want[i] {
+			t.Errorf("result %d: index %d, code %v, want %v", i, result.Index, codes.Code(result.Code), want[i])
+		}
+		if (result.Todo != nil) != (want[i] == codes.OK) {
+			t.Errorf("result %d: todo %v with code %v", i, result.Todo, codes.Code(result.Code))
+		}
+	}
+}
+
+func TestBatchUpdateTodosCanceled(t *testing.T) {
+	s, writes, ctx := newCancelingService(t)
+	todos := createTodos(t, s, 4)
+	writes.writes = 2
+
+	req := &pb.BatchUpdateTodosRequest{}
+	for _, todo := range todos {
+		req.Todos = append(req.Todos, &pb.TodoModel{Id: todo.Id, TaskName: "renamed", UpdatedBy: todo.UserId})
+	}
+	res, err := s.BatchUpdateTodos(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the updates before the cancellation are committed and reported
+	expectCodes(t, res, codes.OK, codes.OK, codes.Canceled, codes.Canceled)
+	if res.Succeeded != 2 || res.Failed != 2 {
+		t.Errorf("%d succeeded and %d failed, want 2 and 2", res.Succeeded, res.Failed)
+	}
+	for i, todo := range todos {
+		stored, err := s.storage.Todo().Get(todo.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if renamed := stored.TaskName == "renamed"; renamed != (i < 2) {
+			t.Errorf("todo %d renamed = %v", i, renamed)
+		}
+	}
+}
+
+func TestBatchDeleteTodosCanceled(t *testing.T) {
+	s, writes, ctx := newCancelingService(t)
+	todos := createTodos(t, s, 3)
+	writes.writes = 1
+
+	req := &pb.BatchDeleteTodosRequest{ActorId: "user-1"}
+	for _, todo := range todos {
+		req.Ids = append(req.Ids, todo.Id)
+	}
+	res, err := s.BatchDeleteTodos(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res.Results) != 3 || res.Results[0].Code != int32(codes.OK) ||
+		res.Results[1].Code != int32(codes.Canceled) || res.Results[2].Code != int32(codes.Canceled) {
+		t.Errorf("results = %v, want the first deleted and the rest canceled", res.Results)
+	}
+	for i, todo := range todos {
+		_, err := s.liveTodo(todo.Id)
+		if deleted := err != nil; deleted != (i == 0) {
+			t.Errorf("todo %d deleted = %v", i, deleted)
+		}
+	}
+}
+
+func TestBatchUpdateTodosDeadline(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	todos := createTodos(t, s, 2)
+
+	// too close to the deadline to start any item
+	ctx, cancel := context.WithTimeout(context.Background(), batchDeadlineReserve/2)
+	defer cancel()
+
+	req := &pb.BatchUpdateTodosRequest{}
+	for _, todo := range todos {
+		req.Todos = append(req.Todos, &pb.TodoModel{Id: todo.Id, TaskName: "renamed", UpdatedBy: todo.UserId})
+	}
+	res, err := s.BatchUpdateTodos(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectCodes(t, res, codes.DeadlineExceeded, codes.DeadlineExceeded)
+}
+
+// bulkStream feeds todos to BulkCreateTodos and keeps its response
+type bulkStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	todos []*pb.TodoModel
+	res   *pb.BatchTodosResponse
+}
+
+func (s *bulkStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *bulkStream) Recv() (*pb.TodoModel, error) {
+	if len(s.todos) == 0 {
+		return nil, io.EOF
+	}
+	todo := s.todos[0]
+	s.todos = s.todos[1:]
+	return todo, nil
+}
+
+func (s *bulkStream) SendAndClose(res *pb.BatchTodosResponse) error {
+	s.res = res
+	return nil
+}
+
+func TestBulkCreateTodosDeadline(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchDeadlineReserve/2)
+	defer cancel()
+
+	stream := &bulkStream{ctx: ctx, todos: []*pb.TodoModel{
+		{TaskName: "pay rent", UserId: "user-1"},
+		{TaskName: "pay bills", UserId: "user-1"},
+	}}
+	if err := s.BulkCreateTodos(stream); err != nil {
+		t.Fatal(err)
+	}
+
+	// every item gets a result, none was created
+	expectCodes(t, stream.res, codes.DeadlineExceeded, codes.DeadlineExceeded)
+	if _, count, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{}); err != nil || count != 0 {
+		t.Errorf("%d todos stored, %v, want none", count, err)
+	}
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// mentionPattern matches @username at the start of the text or after a
+// character which can't be part of an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(\w(?:[\w.-]*\w)?)`)
+
+func (s *todoService) CreateTodoComment(ctx context.Context, req *pb.TodoComment) (*pb.TodoComment, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, status.Error(codes.InvalidArgument, "body is required")
+	}
+	if req.AuthorId == "" {
+		return nil, status.Error(codes.InvalidArgument, "author_id is required")
+	}
+
+	if _, err := s.liveTodo(req.TodoId); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	if req.ParentId != "" {
+		parent, err := s.storage.Comment().Get(req.ParentId)
+		if errors.Is(err, repo.ErrNotFound) || err == nil && parent.TodoId != req.TodoId {
+			return nil, status.Errorf(codes.InvalidArgument, "parent comment %s not found", req.ParentId)
+		}
+		if err != nil {
+			return nil, s.handleCommentStorageError(err, "failed to get parent comment")
+		}
+	}
+
+	req.Id = uuid.New().String()
+	req.Mentions = parseMentions(req.Body)
+	req.Edits = nil
+	req.CreatedAt = timestamppb.Now()
+	req.UpdatedAt = req.CreatedAt
+
+	comment, err := s.storage.Comment().Create(req)
+	if err != nil {
+		return nil, s.handleCommentStorageError(err, "failed to create comment")
+	}
+	s.indexComments(comment.TodoId)
+
+	return comment, nil
+}
+
+func (s *todoService) ListTodoComments(ctx context.Context, req *pb.ListTodoCommentsRequest) (*pb.ListTodoCommentsResponse, error) {
+	if req.TodoId == "" && req.Mention == "" {
+		return nil, status.Error(codes.InvalidArgument, "todo_id or mention is required")
+	}
+
+	if req.TodoId != "" {
+		if _, err := s.liveTodo(req.TodoId); err != nil {
+			return nil, s.handleStorageError(err, "failed to get todo")
+		}
+	}
+	req.Mention = strings.ToLower(strings.TrimPrefix(req.Mention, "@"))
+
+	query := proto.Clone(req).(*pb.ListTodoCommentsRequest)
+	query.Page, query.Limit, query.Cursor, query.After, query.WithCount = 0, 0, "", nil, false
+	p, err := s.newPager(query, req.Cursor, req.Page, req.Limit, repo.DefaultSort)
+	if err != nil {
+		return nil, err
+	}
+	withCount := req.WithCount
+	req.After = p.after(repo.CommentFilterFields)
+	req.Page, req.Limit = p.window()
+	req.WithCount = p.counts(withCount)
+
+	comments, count, err := s.storage.Comment().GetAll(req)
+	if err != nil {
+		return nil, s.handleCommentStorageError(err, "failed to list comments")
+	}
+
+	res := &pb.ListTodoCommentsResponse{}
+	if withCount {
+		res.Count = count
+	}
+	keep, more := p.trim(len(comments), count)
+	res.Comments = comments[:keep]
+	if more {
+		last := res.Comments[keep-1]
+		res.NextCursor = p.next(func(field string) interface{} { return repo.CommentField(last, field) }, last.Id)
+	}
+
+	return res, nil
+}
+
+// UpdateTodoComment changes the body of a comment, keeping the previous one
+// in its edits. author_id is the user making the change.
+func (s *todoService) UpdateTodoComment(ctx context.Context, req *pb.TodoComment) (*pb.TodoComment, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, status.Error(codes.InvalidArgument, "body is required")
+	}
+
+	current, err := s.authoredComment(req.Id, req.TodoId, req.AuthorId, "edit")
+	if err != nil {
+		return nil, err
+	}
+	if current.Body == req.Body {
+		return current, nil
+	}
+
+	update := proto.Clone(current).(*pb.TodoComment)
+	update.UpdatedAt = timestamppb.Now()
+	update.Edits = append(update.Edits, &pb.CommentEdit{
+		Body:     current.Body,
+		EditedAt: update.UpdatedAt,
+	})
+	update.Body = req.Body
+	update.Mentions = parseMentions(req.Body)
+
+	comment, err := s.storage.Comment().Update(update)
+	if err != nil {
+		return nil, s.handleCommentStorageError(err, "failed to update comment")
+	}
+	s.indexComments(comment.TodoId)
+
+	return comment, nil
+}
+
+// DeleteTodoComment deletes a comment with all replies below it
+func (s *todoService) DeleteTodoComment(ctx context.Context, req *pb.DeleteTodoCommentRequest) (*emptypb.Empty, error) {
+	comment, err := s.authoredComment(req.Id, req.TodoId, req.ActorId, "delete")
+	if err != nil {
+		return nil, err
+	}
+
+	// walk the thread breadth first, then delete replies before the
+	// comments they answer
+	ids := []string{comment.Id}
+	for i := 0; i < len(ids); i++ {
+		replies, _, err := s.storage.Comment().GetAll(&pb.ListTodoCommentsRequest{
+			TodoId:   comment.TodoId,
+			ParentId: ids[i],
+		})
+		if err != nil {
+			return nil, s.handleCommentStorageError(err, "failed to list replies")
+		}
+		for _, reply := range replies {
+			ids = append(ids, reply.Id)
+		}
+	}
+
+	for i := len(ids) - 1; i >= 0; i-- {
+		err = s.storage.Comment().Delete(ids[i])
+		if err != nil && !errors.Is(err, repo.ErrNotFound) {
+			return nil, s.handleCommentStorageError(err, "failed to delete comment")
+		}
+	}
+	s.indexComments(comment.TodoId)
+
+	return &emptypb.Empty{}, nil
+}
+
+// authoredComment returns a comment of a todo, if actorID is its author
+func (s *todoService) authoredComment(id, todoID, actorID, action string) (*pb.TodoComment, error) {
+	comment, err := s.storage.Comment().Get(id)
+	if err != nil {
+		return nil, s.handleCommentStorageError(err, "failed to get comment")
+	}
+	if todoID != "" && comment.TodoId != todoID {
+		return nil, status.Error(codes.NotFound, "comment not found")
+	}
+	if _, err = s.liveTodo(comment.TodoId); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if actorID == "" || comment.AuthorId != actorID {
+		return nil, status.Errorf(codes.PermissionDenied, "only the author can %s a comment", action)
+	}
+
+	return comment, nil
+}
+
+// parseMentions returns the lowercased usernames mentioned in body, each
+// once, in order of appearance
+func parseMentions(body string) []string {
+	var (
+		mentions []string
+		seen     = make(map[string]bool)
+	)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.ToLower(match[1])
+		if seen[username] {
+			continue
+		}
+		seen[username] = true
+		mentions = append(mentions, username)
+	}
+
+	return mentions
+}
+
+func (s *todoService) handleCommentStorageError(err error, message string) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "comment not found")
+	}
+
+	return s.handleStorageError(err, message)
+}
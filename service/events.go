@@ -0,0 +1,121 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// eventHistorySize is how many recent events are kept to let
+	// reconnecting watchers resume from their last seen event
+	eventHistorySize = 1024
+	// subscriberBufferSize is how far a watcher may fall behind before it
+	// is disconnected, it then resumes from the history
+	subscriberBufferSize = 64
+)
+
+type subscriber struct {
+	userID string
+	events chan *pb.TodoEvent
+}
+
+// eventBroker fans todo change events out to watchers
+type eventBroker struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []*pb.TodoEvent
+	subs    map[*subscriber]struct{}
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{
+		// ids are seeded from the clock so they keep growing across
+		// restarts and an id from a previous run is detected as lost
+		lastID: uint64(time.Now().UnixNano()),
+		subs:   make(map[*subscriber]struct{}),
+	}
+}
+
+func (b *eventBroker) publish(eventType pb.TodoEventType, todo *pb.TodoModel) {
+	b.send(&pb.TodoEvent{
+		Type:       eventType,
+		Todo:       proto.Clone(todo).(*pb.TodoModel),
+		OccurredAt: timestamppb.Now(),
+	})
+}
+
+// send numbers event and fans it out, it must not be changed afterwards
+func (b *eventBroker) send(event *pb.TodoEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.Id = b.lastID
+
+	if len(b.history) == eventHistorySize {
+		copy(b.history, b.history[1:])
+		b.history = b.history[:eventHistorySize-1]
+	}
+	b.history = append(b.history, event)
+
+	for sub := range b.subs {
+		if !sub.wants(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// the watcher is too slow, drop it instead of blocking writers
+			delete(b.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe registers a watcher for userID's todos. When lastID is set, the
+// events after it are returned as backlog; lost reports that some of them
+// are no longer in the history.
+func (b *eventBroker) subscribe(userID string, lastID uint64) (sub *subscriber, backlog []*pb.TodoEvent, lost bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub = &subscriber{
+		userID: userID,
+		events: make(chan *pb.TodoEvent, subscriberBufferSize),
+	}
+	b.subs[sub] = struct{}{}
+
+	if lastID == 0 || lastID >= b.lastID {
+		return sub, nil, lastID > b.lastID
+	}
+
+	if len(b.history) == 0 || b.history[0].Id > lastID+1 {
+		lost = true
+	}
+
+	for _, event := range b.history {
+		if event.Id > lastID && sub.wants(event) {
+			backlog = append(backlog, event)
+		}
+	}
+
+	return sub, backlog, lost
+}
+
+func (b *eventBroker) unsubscribe(sub *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.events)
+	}
+}
+
+func (s *subscriber) wants(event *pb.TodoEvent) bool {
+	return s.userID == "" || event.Todo.GetUserId() == s.userID
+}
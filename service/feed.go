@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// feedTokenSize is the number of random bytes of a feed token
+const feedTokenSize = 32
+
+// CreateFeedToken returns a new calendar feed token of a user, the one the
+// user had before stops working
+func (s *todoService) CreateFeedToken(ctx context.Context, req *pb.FeedTokenRequest) (*pb.FeedToken, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	b := make([]byte, feedTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return nil, status.Error(codes.Internal, "failed to generate feed token")
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	feed := &pb.FeedToken{
+		UserId:    req.UserId,
+		TokenHash: hashFeedToken(token),
+		CreatedAt: timestamppb.Now(),
+	}
+	if err := s.storage.FeedToken().Put(feed); err != nil {
+		return nil, s.handleFeedStorageError(err, "failed to store feed token")
+	}
+
+	feed.Token = token
+	feed.TokenHash = ""
+
+	return feed, nil
+}
+
+func (s *todoService) GetFeedToken(ctx context.Context, req *pb.FeedTokenRequest) (*pb.FeedToken, error) {
+	feed, err := s.storage.FeedToken().Get(req.UserId)
+	if err != nil {
+		return nil, s.handleFeedStorageError(err, "failed to get feed token")
+	}
+	feed.TokenHash = ""
+
+	return feed, nil
+}
+
+func (s *todoService) RevokeFeedToken(ctx context.Context, req *pb.FeedTokenRequest) (*emptypb.Empty, error) {
+	if err := s.storage.FeedToken().Delete(req.UserId); err != nil {
+		return nil, s.handleFeedStorageError(err, "failed to revoke feed token")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ResolveFeedToken returns the feed token with the user it belongs to,
+// NotFound for unknown and revoked tokens
+func (s *todoService) ResolveFeedToken(ctx context.Context, req *pb.ResolveFeedTokenRequest) (*pb.FeedToken, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.NotFound, "feed not found")
+	}
+
+	feed, err := s.storage.FeedToken().GetByHash(hashFeedToken(req.Token))
+	if err != nil {
+		return nil, s.handleFeedStorageError(err, "failed to get feed token")
+	}
+	feed.TokenHash = ""
+
+	return feed, nil
+}
+
+// hashFeedToken returns the hash a token is stored as, the token has
+// enough entropy for a plain sha256
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// handleFeedStorageError is handleStorageError for feed tokens
+func (s *todoService) handleFeedStorageError(err error, message string) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "feed not found")
+	}
+
+	return s.handleStorageError(err, message)
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/cursor"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// querySumSize is how many bytes of the sha256 of a query cursors carry
+const querySumSize = 16
+
+// pager pages through a listing. Pages either start at an offset, from the
+// deprecated page number, or after the item a cursor points to. A cursor
+// holds the sort values and id of the last item of its page, so items added
+// or removed meanwhile don't shift the next page.
+type pager struct {
+	signer *cursor.Signer
+	query  []byte
+	fields []*pb.SortField
+	from   *pb.PageCursor
+	page   int64
+	limit  int64
+}
+
+// newPager reads the paging of a request. query is the request without its
+// paging fields, a cursor is only accepted for the query it was issued for.
+// fields are the sort fields, whose values cursors carry.
+func (s *todoService) newPager(query proto.Message, token string, page, limit int64, fields []*pb.SortField) (*pager, error) {
+	raw, err := proto.MarshalOptions{Deterministic: true}.Marshal(query)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to hash query")
+	}
+	// requests of different listings can encode the same, e.g. only a todo
+	// id, the name of the request tells them apart
+	hash := sha256.New()
+	hash.Write([]byte(query.ProtoReflect().Descriptor().FullName()))
+	hash.Write(raw)
+
+	p := &pager{
+		signer: s.cursors,
+		query:  hash.Sum(nil)[:querySumSize],
+		fields: fields,
+		page:   page,
+		limit:  limit,
+	}
+	if p.page <= 0 {
+		p.page = 1
+	}
+	if token == "" {
+		return p, nil
+	}
+
+	payload, err := s.cursors.Decode(token)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	from := &pb.PageCursor{}
+	if err = proto.Unmarshal(payload, from); err != nil || len(from.Values) != len(fields) {
+		return nil, status.Error(codes.InvalidArgument, cursor.ErrInvalid.Error())
+	}
+	if !bytes.Equal(from.Query, p.query) {
+		return nil, status.Error(codes.InvalidArgument, "cursor belongs to another query, keep the parameters of the first page")
+	}
+	p.from = from
+	p.page = 1
+
+	return p, nil
+}
+
+// after returns the filter of the items after the cursor, nil without one
+func (p *pager) after(kinds map[string]repo.FilterKind) *pb.FilterExpr {
+	if p.from == nil {
+		return nil
+	}
+
+	return repo.After(p.fields, kinds, p.from.Values, p.from.Id)
+}
+
+// window returns the page and limit to fetch. The first page and cursor
+// pages fetch one more item, which tells if there is a next page.
+func (p *pager) window() (page, limit int64) {
+	if p.peeks() {
+		return p.page, p.limit + 1
+	}
+
+	return p.page, p.limit
+}
+
+// peeks reports if the page is fetched with one more item, later page
+// numbers can't be, it would shift their offset
+func (p *pager) peeks() bool {
+	return p.limit > 0 && (p.from != nil || p.page == 1)
+}
+
+// counts reports if the items have to be counted, because the client asked
+// for the count or a later page number needs it to tell if a next page
+// follows
+func (p *pager) counts(asked bool) bool {
+	return asked || p.limit > 0 && !p.peeks()
+}
+
+// trim returns how many of the n fetched items belong to the page, out of
+// count items in total, and if more items follow them
+func (p *pager) trim(n int, count int64) (keep int, more bool) {
+	if p.limit <= 0 {
+		return n, false
+	}
+	if p.peeks() {
+		if int64(n) > p.limit {
+			return int(p.limit), true
+		}
+		return n, false
+	}
+
+	return n, (p.page-1)*p.limit+int64(n) < count
+}
+
+// next returns the cursor of the page after the item get returns the
+// fields of, and which has id
+func (p *pager) next(get func(field string) interface{}, id string) string {
+	values := make([]*pb.FilterValue, 0, len(p.fields))
+	for _, f := range p.fields {
+		values = append(values, repo.FilterValueOf(get(f.Field)))
+	}
+
+	return p.encode(values, id)
+}
+
+func (p *pager) encode(values []*pb.FilterValue, id string) string {
+	payload, err := proto.Marshal(&pb.PageCursor{
+		Query:  p.query,
+		Values: values,
+		Id:     id,
+	})
+	if err != nil {
+		return ""
+	}
+
+	return p.signer.Encode(payload)
+}
+
+// slice pages the n records of items, a slice fetched whole, for the
+// collections of a todo storage returns in one piece. get returns the
+// values of the sort fields and the id of item i. items are sorted like
+// GetAll sorts, so a cursor finds its place again. Pages start after a
+// cursor, deprecated page numbers are not supported. It returns the bounds of
+// the page and the cursor of the next one, empty on the last page.
+func (p *pager) slice(items interface{}, n int, get func(i int) (values []interface{}, id string)) (start, end int, next string) {
+	key := func(i int) ([]*pb.FilterValue, string) {
+		values, id := get(i)
+		res := make([]*pb.FilterValue, 0, len(values))
+		for _, v := range values {
+			res = append(res, repo.FilterValueOf(v))
+		}
+		return res, id
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, aID := key(i)
+		b, bID := key(j)
+		return p.compare(a, aID, b, bID) < 0
+	})
+
+	if p.from != nil {
+		start = sort.Search(n, func(i int) bool {
+			values, id := key(i)
+			return p.compare(values, id, p.from.Values, p.from.Id) > 0
+		})
+	}
+
+	end = n
+	if p.limit > 0 && int64(n-start) > p.limit {
+		end = start + int(p.limit)
+		values, id := key(end - 1)
+		next = p.encode(values, id)
+	}
+
+	return start, end, next
+}
+
+// compare orders two records by the values of the sort fields, then by id
+func (p *pager) compare(a []*pb.FilterValue, aID string, b []*pb.FilterValue, bID string) int {
+	for i, f := range p.fields {
+		cmp := compareFilterValues(a[i], b[i])
+		if f.Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp
+		}
+	}
+
+	return strings.Compare(aID, bID)
+}
+
+// compareFilterValues compares two values of the same kind, unset times
+// come last
+func compareFilterValues(a, b *pb.FilterValue) int {
+	switch {
+	case a.GetNullValue() || b.GetNullValue():
+		return compareBools(a.GetNullValue(), b.GetNullValue())
+	case a.GetTimeValue() != nil:
+		return compareTimes(a.GetTimeValue(), b.GetTimeValue())
+	case a.GetIntValue() < b.GetIntValue():
+		return -1
+	case a.GetIntValue() > b.GetIntValue():
+		return 1
+	}
+
+	return strings.Compare(a.GetStringValue(), b.GetStringValue())
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+
+	return -1
+}
+
+func compareTimes(a, b *timestamppb.Timestamp) int {
+	switch ta, tb := a.AsTime(), b.AsTime(); {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+
+	return 0
+}
+
+// sortOrDefault returns fields, or repo.DefaultSort without any
+func sortOrDefault(fields []*pb.SortField) []*pb.SortField {
+	if len(fields) == 0 {
+		return repo.DefaultSort
+	}
+
+	return fields
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListTodoRevisionsPages(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	todo := createReminded(t, s, time.Now().Add(time.Hour))
+	for _, name := range []string{"b", "c", "d", "e"} {
+		todo.TaskName = name
+		var err error
+		if todo, err = s.UpdateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []int64
+	req := &pb.ListTodoRevisionsRequest{TodoId: todo.Id, Limit: 2}
+	for pages := 1; ; pages++ {
+		res, err := s.ListTodoRevisions(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Count != 5 {
+			t.Errorf("count = %d, want 5", res.Count)
+		}
+		for _, r := range res.Revisions {
+			got = append(got, r.Revision)
+		}
+		if res.NextCursor == "" {
+			if pages != 3 {
+				t.Errorf("got %d pages, want 3", pages)
+			}
+			break
+		}
+		req.Cursor = res.NextCursor
+	}
+	for i, r := range got {
+		if r != int64(i+1) {
+			t.Fatalf("got revisions %v, want 1 to 5 in order", got)
+		}
+	}
+	if len(got) != 5 {
+		t.Fatalf("got revisions %v, want 1 to 5 in order", got)
+	}
+
+	// a cursor only continues the listing it was issued for
+	other := createReminded(t, s, time.Now().Add(time.Hour))
+	first, err := s.ListTodoRevisions(ctx, &pb.ListTodoRevisionsRequest{TodoId: todo.Id, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.ListTodoRevisions(ctx, &pb.ListTodoRevisionsRequest{TodoId: other.Id, Cursor: first.NextCursor})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("cursor of another todo: error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestListSubtasksPages(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	parent := createReminded(t, s, time.Now().Add(time.Hour))
+	want := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		subtask, err := s.CreateTodo(ctx, &pb.TodoModel{TaskName: "step", UserId: "user-1", ParentId: parent.Id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = s.CreateTodo(ctx, &pb.TodoModel{TaskName: "substep", UserId: "user-1", ParentId: subtask.Id}); err != nil {
+			t.Fatal(err)
+		}
+		want[subtask.Id] = true
+	}
+
+	req := &pb.ListSubtasksRequest{Id: parent.Id, Recursive: true, Limit: 2, WithCount: true}
+	seen := make(map[string]bool)
+	for {
+		res, err := s.ListSubtasks(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Count != 3 {
+			t.Errorf("count = %d, want the 3 direct subtasks", res.Count)
+		}
+		for _, node := range res.Subtasks {
+			if seen[node.Todo.Id] || !want[node.Todo.Id] {
+				t.Fatalf("unexpected subtask %s", node.Todo.Id)
+			}
+			seen[node.Todo.Id] = true
+			// pages hold whole subtrees
+			if len(node.Subtasks) != 1 {
+				t.Errorf("subtask %s has %d subtasks, want 1", node.Todo.Id, len(node.Subtasks))
+			}
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		req.Cursor = res.NextCursor
+	}
+	if len(seen) != len(want) {
+		t.Errorf("listed %d subtasks, want %d", len(seen), len(want))
+	}
+}
+
+// countedStorage records if todo listings asked the storage for a count
+type countedStorage struct {
+	storage.StorageI
+	todos *countedTodos
+}
+
+func (s countedStorage) Todo() repo.TodoStorageI {
+	return s.todos
+}
+
+type countedTodos struct {
+	repo.TodoStorageI
+	counted []bool
+}
+
+func (r *countedTodos) GetAll(req *pb.ListTodosRequest) ([]*pb.TodoModel, int64, error) {
+	r.counted = append(r.counted, req.WithCount)
+	return r.TodoStorageI.GetAll(req)
+}
+
+func TestListTodosCountsOnRequest(t *testing.T) {
+	strg := storage.NewStorageMemory()
+	todos := &countedTodos{TodoStorageI: strg.Todo()}
+	s := newReminderService(t, countedStorage{StorageI: strg, todos: todos}, &fakeClock{now: time.Now()})
+	ctx := context.Background()
+	createTodos(t, s, 5)
+
+	tests := []struct {
+		name    string
+		req     *pb.ListTodosRequest
+		count   int64
+		counted bool
+	}{
+		{"first page", &pb.ListTodosRequest{Limit: 2}, 0, false},
+		{"first page with count", &pb.ListTodosRequest{Limit: 2, WithCount: true}, 5, true},
+		// a later page number needs the count to tell if a next page follows
+		{"page number", &pb.ListTodosRequest{Limit: 2, Page: 2}, 0, true},
+		{"everything", &pb.ListTodosRequest{}, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todos.counted = nil
+			res, err := s.ListTodos(ctx, tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Count != tt.count || len(todos.counted) != 1 || todos.counted[0] != tt.counted {
+				t.Errorf("count = %d, counted %v, want %d, counted %v", res.Count, todos.counted, tt.count, tt.counted)
+			}
+			if more := tt.req.Limit > 0; (res.NextCursor != "") != more {
+				t.Errorf("next cursor %q, want one: %v", res.NextCursor, more)
+			}
+		})
+	}
+
+	// the pages of a listing are the same with and without the count
+	var ids []string
+	req := &pb.ListTodosRequest{Limit: 2}
+	for {
+		res, err := s.ListTodos(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, todo := range res.Todos {
+			ids = append(ids, todo.Id)
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		req = &pb.ListTodosRequest{Limit: 2, Cursor: res.NextCursor, WithCount: len(ids) == 2}
+	}
+	if len(ids) != 5 {
+		t.Errorf("listed %d todos, want 5", len(ids))
+	}
+}
+
+func TestCursorOfAnotherListing(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	todo := createReminded(t, s, time.Now().Add(time.Hour))
+	for i := 0; i < 2; i++ {
+		if _, err := s.CreateTodo(ctx, &pb.TodoModel{TaskName: "step", UserId: "user-1", ParentId: todo.Id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	subtasks, err := s.ListSubtasks(ctx, &pb.ListSubtasksRequest{Id: todo.Id, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// both requests only hold the todo id
+	_, err = s.ListTodoAttachments(ctx, &pb.ListTodoAttachmentsRequest{TodoId: todo.Id, Cursor: subtasks.NextCursor})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("error = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/rrule"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultOccurrences = 10
+	maxOccurrences     = 100
+)
+
+// ListOccurrences returns the due dates of a recurring todo and the
+// occurrences after it, up to limit of them
+func (s *todoService) ListOccurrences(ctx context.Context, req *pb.ListOccurrencesRequest) (*pb.ListOccurrencesResponse, error) {
+	todo, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if todo.Recurrence == nil {
+		return nil, failedPrecondition(reasonNotRecurring, "todo %s does not recur", todo.Id)
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultOccurrences
+	}
+	if limit > maxOccurrences {
+		limit = maxOccurrences
+	}
+
+	rule, loc, err := parseRecurrence(todo.Recurrence)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid stored recurrence: %v", err)
+	}
+
+	res := &pb.ListOccurrencesResponse{}
+	due := todo.DueAt.AsTime()
+	it := rule.Iterator(todo.Recurrence.Start.AsTime().In(loc))
+	for len(res.Occurrences) < limit {
+		t, ok := it.Next()
+		if !ok {
+			break
+		}
+		if t.Before(due) {
+			continue
+		}
+		res.Occurrences = append(res.Occurrences, timestamppb.New(t))
+	}
+
+	return res, nil
+}
+
+// checkRecurrence checks the recurrence of a todo a client sets and fills
+// in the fields kept by the service. current is the stored todo on update,
+// its series continues unless the rule or time zone change.
+func checkRecurrence(todo, current *pb.TodoModel) error {
+	rec := todo.Recurrence
+	if rec == nil {
+		return nil
+	}
+	if todo.DueAt == nil {
+		return status.Error(codes.InvalidArgument, "a recurring todo needs due_at")
+	}
+
+	rule, err := rrule.Parse(rec.Rule)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid recurrence: %v", err)
+	}
+	if _, err = time.LoadLocation(rec.Timezone); err != nil {
+		return status.Errorf(codes.InvalidArgument, "unknown timezone %s", rec.Timezone)
+	}
+	rec.Rule = rule.String()
+
+	if prev := current.GetRecurrence(); prev != nil && prev.Rule == rec.Rule && prev.Timezone == rec.Timezone {
+		rec.Start = prev.Start
+		rec.NextId = prev.NextId
+	} else {
+		rec.Start = todo.DueAt
+		rec.NextId = ""
+	}
+
+	return nil
+}
+
+// parseRecurrence returns the rule of a checked recurrence and the
+// location it is expanded in
+func parseRecurrence(rec *pb.Recurrence) (*rrule.Rule, *time.Location, error) {
+	rule, err := rrule.Parse(rec.Rule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := time.LoadLocation(rec.Timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rule, loc, nil
+}
+
+// nextOccurrence creates the next occurrence of a recurring todo which was
+// completed by the change from before to after. The returned todo links
+// to it, a todo is only followed by one occurrence however often it is
+// completed.
+func (s *todoService) nextOccurrence(before, after *pb.TodoModel) (*pb.TodoModel, error) {
+	rec := after.Recurrence
+	if rec == nil || rec.NextId != "" || after.DueAt == nil ||
+		after.TaskStatus != pb.TaskStatus_TASK_STATUS_DONE || before.TaskStatus == pb.TaskStatus_TASK_STATUS_DONE {
+		return after, nil
+	}
+
+	rule, loc, err := parseRecurrence(rec)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "invalid stored recurrence: %v", err)
+	}
+	due, ok := rule.After(rec.Start.AsTime().In(loc), after.DueAt.AsTime())
+	if !ok {
+		// the series is over
+		return after, nil
+	}
+
+	created, err := s.createTodo(&pb.TodoModel{
+		TaskName:     after.TaskName,
+		Description:  after.Description,
+		Priority:     after.Priority,
+		DueAt:        timestamppb.New(due),
+		UserId:       after.UserId,
+		WorkspaceId:  after.WorkspaceId,
+		ParentId:     after.ParentId,
+		ListId:       after.ListId,
+		RemindBefore: after.RemindBefore,
+		Recurrence: &pb.Recurrence{
+			Rule:     rec.Rule,
+			Timezone: rec.Timezone,
+			Start:    rec.Start,
+		},
+	})
+	if err != nil {
+		// e.g. the list was archived meanwhile, which takes no new todos,
+		// the todo is completed anyway
+		s.log.Warn("failed to create next occurrence", logger.String("todo_id", after.Id), logger.Error(err))
+		return after, nil
+	}
+
+	update := proto.Clone(after).(*pb.TodoModel)
+	update.Recurrence.NextId = created.Id
+	todo, err := s.storage.Todo().Update(update)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to link next occurrence")
+	}
+
+	return todo, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// maxReminders limits the remind_before offsets of a todo
+	maxReminders = 10
+	// maxRemindBefore is the earliest a todo is reminded of, a year before
+	// it is due
+	maxRemindBefore = int64(366 * 24 * time.Hour / time.Second)
+)
+
+// RunReminders schedules the stored reminders and delivers them as they
+// become due until ctx is done. Reminders which became due while the
+// service was down are delivered right away.
+func (s *todoService) RunReminders(ctx context.Context) {
+	reminders, err := s.storage.Reminder().GetPending()
+	if err != nil {
+		s.log.Error("failed to load reminders", logger.Error(err))
+	}
+	for _, reminder := range reminders {
+		s.scheduler.Schedule(reminder.Id, reminder.RemindAt.AsTime())
+	}
+	s.log.Info("reminders scheduled", logger.Int("reminders", len(reminders)))
+
+	s.scheduler.Run(ctx)
+}
+
+// syncReminders stores and schedules the reminders a todo should have after
+// a change and drops the others, e.g. when it was completed or its due_at
+// moved. The change is already stored, so a failure only leaves reminders
+// stale, which fireReminder detects.
+func (s *todoService) syncReminders(todo *pb.TodoModel) {
+	s.reminderMu.Lock()
+	defer s.reminderMu.Unlock()
+
+	stored, err := s.storage.Reminder().GetAll(todo.Id)
+	if err != nil {
+		s.log.Error("failed to list reminders", logger.String("todo_id", todo.Id), logger.Error(err))
+		return
+	}
+
+	// reminders are only set up ahead of time, when an offset or due_at
+	// changes into the past it is not reminded of
+	now := s.clock.Now()
+	wanted := make(map[string]*pb.TodoReminder)
+	for _, reminder := range todoReminders(todo) {
+		if reminder.RemindAt.AsTime().After(now) {
+			wanted[reminder.Id] = reminder
+		}
+	}
+
+	for _, reminder := range stored {
+		if proto.Equal(reminder, wanted[reminder.Id]) {
+			delete(wanted, reminder.Id)
+			continue
+		}
+		if _, ok := wanted[reminder.Id]; ok {
+			// rescheduled below
+			continue
+		}
+		if err = s.storage.Reminder().Delete(reminder.Id); err != nil && !errors.Is(err, repo.ErrNotFound) {
+			s.log.Error("failed to delete reminder", logger.String("id", reminder.Id), logger.Error(err))
+			continue
+		}
+		s.scheduler.Cancel(reminder.Id)
+	}
+
+	for _, reminder := range wanted {
+		if err = s.storage.Reminder().Put(reminder); err != nil {
+			s.log.Error("failed to store reminder", logger.String("id", reminder.Id), logger.Error(err))
+			continue
+		}
+		s.scheduler.Schedule(reminder.Id, reminder.RemindAt.AsTime())
+	}
+}
+
+// fireReminder delivers a reminder which became due to every hook and
+// deletes it. Reminders which no longer match their todo are deleted
+// without delivery.
+func (s *todoService) fireReminder(id string) {
+	reminder, todo, ok := s.dueReminder(id)
+	if !ok {
+		return
+	}
+
+	event := &pb.TodoEvent{
+		Type:       pb.TodoEventType_TODO_REMINDER,
+		Todo:       todo,
+		Reminder:   reminder,
+		OccurredAt: timestamppb.New(s.clock.Now()),
+	}
+	for _, hook := range s.reminderHooks {
+		ctx, cancel := context.WithTimeout(context.Background(), s.reminderTimeout)
+		if err := hook.Deliver(ctx, event); err != nil {
+			s.log.Error("failed to deliver reminder", logger.String("id", id), logger.String("hook", hook.Name()), logger.Error(err))
+		}
+		cancel()
+	}
+
+	s.reminderMu.Lock()
+	defer s.reminderMu.Unlock()
+
+	// rescheduled while it was delivered, the new one stays
+	current, err := s.storage.Reminder().Get(id)
+	if err != nil || !proto.Equal(current, reminder) {
+		return
+	}
+	if err = s.storage.Reminder().Delete(id); err != nil && !errors.Is(err, repo.ErrNotFound) {
+		s.log.Error("failed to delete reminder", logger.String("id", id), logger.Error(err))
+	}
+}
+
+// dueReminder returns a reminder to deliver with its todo, false when it
+// was cancelled or went stale
+func (s *todoService) dueReminder(id string) (*pb.TodoReminder, *pb.TodoModel, bool) {
+	s.reminderMu.Lock()
+	defer s.reminderMu.Unlock()
+
+	reminder, err := s.storage.Reminder().Get(id)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, nil, false
+	}
+	if err != nil {
+		s.log.Error("failed to get reminder", logger.String("id", id), logger.Error(err))
+		return nil, nil, false
+	}
+
+	todo, err := s.storage.Todo().Get(reminder.TodoId)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		s.log.Error("failed to get todo", logger.String("todo_id", reminder.TodoId), logger.Error(err))
+		return nil, nil, false
+	}
+	if todo != nil {
+		for _, r := range todoReminders(todo) {
+			if proto.Equal(r, reminder) {
+				return reminder, todo, true
+			}
+		}
+	}
+
+	if err = s.storage.Reminder().Delete(id); err != nil && !errors.Is(err, repo.ErrNotFound) {
+		s.log.Error("failed to delete reminder", logger.String("id", id), logger.Error(err))
+	}
+
+	return nil, nil, false
+}
+
+// todoReminders returns the reminders of a todo which is still to be done,
+// one per remind_before offset
+func todoReminders(todo *pb.TodoModel) []*pb.TodoReminder {
+	if todo.DeletedAt != nil || todo.DueAt == nil ||
+		todo.TaskStatus == pb.TaskStatus_TASK_STATUS_DONE || todo.TaskStatus == pb.TaskStatus_TASK_STATUS_CANCELLED {
+		return nil
+	}
+
+	due := todo.DueAt.AsTime()
+	reminders := make([]*pb.TodoReminder, 0, len(todo.RemindBefore))
+	for _, before := range todo.RemindBefore {
+		reminders = append(reminders, &pb.TodoReminder{
+			Id:           fmt.Sprintf("%s/%d", todo.Id, before),
+			TodoId:       todo.Id,
+			RemindBefore: before,
+			RemindAt:     timestamppb.New(due.Add(-time.Duration(before) * time.Second)),
+		})
+	}
+
+	return reminders
+}
+
+// checkRemindBefore validates the reminder offsets of a todo
+func checkRemindBefore(offsets []int64) error {
+	if len(offsets) > maxReminders {
+		return status.Errorf(codes.InvalidArgument, "a todo has at most %d reminders", maxReminders)
+	}
+
+	seen := make(map[int64]bool, len(offsets))
+	for _, before := range offsets {
+		if before <= 0 || before > maxRemindBefore {
+			return status.Errorf(codes.InvalidArgument, "remind_before must be between 1 and %d seconds", maxRemindBefore)
+		}
+		if seen[before] {
+			return status.Errorf(codes.InvalidArgument, "remind_before %d is given twice", before)
+		}
+		seen[before] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ReminderHook delivers reminders which became due, event is a
+// TODO_REMINDER event with the reminder and its todo
+type ReminderHook interface {
+	Name() string
+	Deliver(ctx context.Context, event *pb.TodoEvent) error
+}
+
+// newReminderHooks returns the hooks named in cfg.ReminderHooks
+func newReminderHooks(cfg config.Config, log logger.Logger, events *eventBroker) ([]ReminderHook, error) {
+	var hooks []ReminderHook
+	for _, name := range strings.Split(cfg.ReminderHooks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "log":
+			hooks = append(hooks, logReminderHook{log: log})
+		case "sse":
+			hooks = append(hooks, sseReminderHook{events: events})
+		case "webhook":
+			if cfg.ReminderWebhookURL == "" {
+				return nil, fmt.Errorf("the webhook reminder hook needs REMINDER_WEBHOOK_URL")
+			}
+			hooks = append(hooks, webhookReminderHook{url: cfg.ReminderWebhookURL, client: http.DefaultClient})
+		default:
+			return nil, fmt.Errorf("unknown reminder hook %q", name)
+		}
+	}
+
+	return hooks, nil
+}
+
+// logReminderHook writes reminders to the service log
+type logReminderHook struct {
+	log logger.Logger
+}
+
+func (h logReminderHook) Name() string {
+	return "log"
+}
+
+func (h logReminderHook) Deliver(ctx context.Context, event *pb.TodoEvent) error {
+	h.log.Info("todo reminder",
+		logger.String("todo_id", event.Todo.Id),
+		logger.String("user_id", event.Todo.UserId),
+		logger.String("task_name", event.Todo.TaskName),
+		logger.String("due_at", event.Todo.DueAt.AsTime().Format(time.RFC3339)),
+	)
+
+	return nil
+}
+
+// sseReminderHook sends reminders to the watchers of the todo, the gateway
+// streams them as reminder events
+type sseReminderHook struct {
+	events *eventBroker
+}
+
+func (h sseReminderHook) Name() string {
+	return "sse"
+}
+
+func (h sseReminderHook) Deliver(ctx context.Context, event *pb.TodoEvent) error {
+	h.events.send(proto.Clone(event).(*pb.TodoEvent))
+	return nil
+}
+
+// webhookReminderHook posts reminders as JSON events to a URL, any status
+// but 2xx is a failed delivery
+type webhookReminderHook struct {
+	url    string
+	client *http.Client
+}
+
+func (h webhookReminderHook) Name() string {
+	return "webhook"
+}
+
+func (h webhookReminderHook) Deliver(ctx context.Context, event *pb.TodoEvent) error {
+	body, err := protojson.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+
+	return nil
+}
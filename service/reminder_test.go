@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/cursor"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/scheduler"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeClock tells a set time, its timers only fire when they are due on
+// creation, which is all RunReminders needs for overdue reminders
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) scheduler.Timer {
+	t := fakeTimer(make(chan time.Time, 1))
+	if d <= 0 {
+		t <- c.Now()
+	}
+	return t
+}
+
+type fakeTimer chan time.Time
+
+func (t fakeTimer) C() <-chan time.Time { return t }
+
+func (t fakeTimer) Stop() bool { return false }
+
+// recordingHook passes the reminders it gets on
+type recordingHook chan *pb.TodoEvent
+
+func (h recordingHook) Name() string { return "recording" }
+
+func (h recordingHook) Deliver(ctx context.Context, event *pb.TodoEvent) error {
+	h <- event
+	return nil
+}
+
+func newReminderService(t *testing.T, strg storage.StorageI, clock scheduler.Clock) *todoService {
+	t.Helper()
+
+	workflows, err := LoadWorkflows("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursors, err := cursor.NewSigner("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Config{TodoMaxDepth: 5, ReminderTimeout: 1000}
+
+	s, err := NewTodoService(cfg, logger.New(logger.LevelError, "test"), strg, nil, workflows, cursors, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// remindAts returns the stored reminders of a todo by remind_before
+func remindAts(t *testing.T, s *todoService, todoID string) map[int64]time.Time {
+	t.Helper()
+
+	reminders, err := s.storage.Reminder().GetAll(todoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ats := make(map[int64]time.Time, len(reminders))
+	for _, reminder := range reminders {
+		ats[reminder.RemindBefore] = reminder.RemindAt.AsTime()
+	}
+
+	return ats
+}
+
+func expectReminders(t *testing.T, s *todoService, todoID string, want map[int64]time.Time) {
+	t.Helper()
+
+	got := remindAts(t, s, todoID)
+	if len(got) != len(want) {
+		t.Fatalf("got reminders %v, want %v", got, want)
+	}
+	for before, at := range want {
+		if !got[before].Equal(at) {
+			t.Fatalf("got reminders %v, want %v", got, want)
+		}
+	}
+	if n := s.scheduler.Len(); n != len(want) {
+		t.Fatalf("%d reminders scheduled, want %d", n, len(want))
+	}
+}
+
+func createReminded(t *testing.T, s *todoService, due time.Time, remindBefore ...int64) *pb.TodoModel {
+	t.Helper()
+
+	todo, err := s.CreateTodo(context.Background(), &pb.TodoModel{
+		TaskName:     "pay rent",
+		UserId:       "user-1",
+		DueAt:        timestamppb.New(due),
+		RemindBefore: remindBefore,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return todo
+}
+
+func TestRemindersFollowTodo(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: now})
+	ctx := context.Background()
+
+	due := now.Add(2 * time.Hour)
+	todo := createReminded(t, s, due, 3600)
+	expectReminders(t, s, todo.Id, map[int64]time.Time{3600: due.Add(-time.Hour)})
+
+	// moving due_at moves the reminder
+	due = now.Add(5 * time.Hour)
+	todo.DueAt = timestamppb.New(due)
+	todo, err := s.UpdateTodo(ctx, todo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectReminders(t, s, todo.Id, map[int64]time.Time{3600: due.Add(-time.Hour)})
+
+	// changing remind_before replaces the reminders
+	todo.RemindBefore = []int64{1800, 4 * 3600}
+	if todo, err = s.UpdateTodo(ctx, todo); err != nil {
+		t.Fatal(err)
+	}
+	expectReminders(t, s, todo.Id, map[int64]time.Time{
+		1800:     due.Add(-30 * time.Minute),
+		4 * 3600: due.Add(-4 * time.Hour),
+	})
+
+	// a reminder which would be in the past is not set up
+	todo.RemindBefore = []int64{1800, 6 * 3600}
+	if todo, err = s.UpdateTodo(ctx, todo); err != nil {
+		t.Fatal(err)
+	}
+	expectReminders(t, s, todo.Id, map[int64]time.Time{1800: due.Add(-30 * time.Minute)})
+
+	// without due_at there is nothing to be reminded of
+	todo.DueAt = nil
+	if todo, err = s.UpdateTodo(ctx, todo); err != nil {
+		t.Fatal(err)
+	}
+	expectReminders(t, s, todo.Id, nil)
+}
+
+func TestRemindersCancelledOnCompletion(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: now})
+
+	todo := createReminded(t, s, now.Add(time.Hour), 60, 600)
+	_, err := s.TransitionTodo(context.Background(), &pb.TransitionTodoRequest{
+		Id:      todo.Id,
+		To:      pb.TaskStatus_TASK_STATUS_DONE,
+		ActorId: todo.UserId,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectReminders(t, s, todo.Id, nil)
+}
+
+func TestRemindersCancelledOnDelete(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: now})
+
+	todo := createReminded(t, s, now.Add(time.Hour), 60)
+	_, err := s.DeleteTodo(context.Background(), &pb.DeleteTodoRequest{Id: todo.Id, ActorId: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectReminders(t, s, todo.Id, nil)
+}
+
+func TestRunRemindersDeliversOverdue(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	strg := storage.NewStorageMemory()
+	clock := &fakeClock{now: now}
+
+	due := now.Add(time.Hour)
+	todo := createReminded(t, newReminderService(t, strg, clock), due, 1800)
+
+	// the service comes back after the reminder became due
+	clock.Set(now.Add(45 * time.Minute))
+	s := newReminderService(t, strg, clock)
+	delivered := make(recordingHook, 1)
+	s.reminderHooks = []ReminderHook{delivered}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.RunReminders(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	select {
+	case event := <-delivered:
+		if event.Type != pb.TodoEventType_TODO_REMINDER || event.Todo.Id != todo.Id {
+			t.Fatalf("delivered %v, want a reminder of %s", event, todo.Id)
+		}
+		if at := event.Reminder.RemindAt.AsTime(); !at.Equal(due.Add(-30 * time.Minute)) {
+			t.Errorf("remind_at = %v, want %v", at, due.Add(-30*time.Minute))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("overdue reminder was not delivered")
+	}
+
+	// a delivered reminder is deleted, so it is not delivered again
+	deadline := time.Now().Add(time.Second)
+	for len(remindAts(t, s, todo.Id)) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("delivered reminder was not deleted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
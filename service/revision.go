@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// revisionFields are the fields of a todo whose changes revisions record
+var revisionFields = []string{
+	"task_name",
+	"description",
+	"task_status",
+	"priority",
+	"due_at",
+	"parent_id",
+	"list_id",
+	"deleted_at",
+	"recurrence",
+	"remind_before",
+}
+
+// revisionSort is the order revisions are listed in
+var revisionSort = []*pb.SortField{{Field: "revision"}}
+
+func (s *todoService) ListTodoRevisions(ctx context.Context, req *pb.ListTodoRevisionsRequest) (*pb.ListTodoRevisionsResponse, error) {
+	if _, err := s.liveTodo(req.TodoId); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	query := proto.Clone(req).(*pb.ListTodoRevisionsRequest)
+	query.Limit, query.Cursor = 0, ""
+	p, err := s.newPager(query, req.Cursor, 0, req.Limit, revisionSort)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.storage.Revision().GetAll(req.TodoId)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list revisions")
+	}
+	start, end, next := p.slice(revisions, len(revisions), func(i int) ([]interface{}, string) {
+		return []interface{}{revisions[i].Revision}, revisions[i].Id
+	})
+
+	return &pb.ListTodoRevisionsResponse{
+		Revisions:  revisions[start:end],
+		Count:      int64(len(revisions)),
+		NextCursor: next,
+	}, nil
+}
+
+// RevertTodo brings the fields of a todo back to how they were after a
+// revision. The revert is an update like any other, it is checked against
+// the parent, list and workflow and is recorded as a new revision. Reverting
+// to the current state changes nothing and returns the todo as it is.
+func (s *todoService) RevertTodo(ctx context.Context, req *pb.RevertTodoRequest) (*pb.TodoModel, error) {
+	current, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkTodoOwner(current, req.ActorId, "revert a todo"); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.storage.Revision().GetAll(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list revisions")
+	}
+	if req.Revision < 1 || req.Revision > int64(len(revisions)) {
+		return nil, status.Errorf(codes.NotFound, "revision %d not found", req.Revision)
+	}
+	target := revisions[req.Revision-1].Todo
+
+	update := proto.Clone(current).(*pb.TodoModel)
+	update.TaskName = target.TaskName
+	update.Description = target.Description
+	update.TaskStatus = target.TaskStatus
+	update.Priority = target.Priority
+	update.DueAt = target.DueAt
+	update.ParentId = target.ParentId
+	update.ListId = target.ListId
+	update.Recurrence = proto.Clone(target.Recurrence).(*pb.Recurrence)
+	update.RemindBefore = target.RemindBefore
+	update.UpdatedBy = req.ActorId
+
+	if len(diffTodos(current, update)) == 0 {
+		if err = s.fillProgress(current); err != nil {
+			return nil, s.handleStorageError(err, "failed to count subtasks")
+		}
+		return current, nil
+	}
+
+	return s.updateTodo(update, &pb.TodoRevision{
+		Action:     pb.RevisionAction_REVISION_REVERTED,
+		RevertedTo: req.Revision,
+	})
+}
+
+// recordRevision adds the change from before to after to the history of a
+// todo as rev, the actor is whoever made the update. Updates without
+// changes are not recorded.
+func (s *todoService) recordRevision(rev *pb.TodoRevision, before, after *pb.TodoModel) error {
+	rev.Changes = diffTodos(before, after)
+	if len(rev.Changes) == 0 {
+		return nil
+	}
+
+	rev.Id = uuid.New().String()
+	rev.TodoId = after.Id
+	rev.ActorId = after.UpdatedBy
+	rev.OccurredAt = after.UpdatedAt
+	rev.Todo = proto.Clone(after).(*pb.TodoModel)
+	rev.Todo.Progress = nil
+
+	if _, err := s.storage.Revision().Create(rev); err != nil {
+		return s.handleStorageError(err, "failed to record revision")
+	}
+
+	return nil
+}
+
+// diffTodos returns the changes of revisionFields from before to after
+func diffTodos(before, after *pb.TodoModel) []*pb.FieldChange {
+	var changes []*pb.FieldChange
+	for _, field := range revisionFields {
+		from := revisionValue(field, revisionField(before, field))
+		to := revisionValue(field, revisionField(after, field))
+		if from != to {
+			changes = append(changes, &pb.FieldChange{
+				Field: field,
+				From:  from,
+				To:    to,
+			})
+		}
+	}
+
+	return changes
+}
+
+// revisionValue formats a value of repo.TodoField as a FieldChange value
+func revisionValue(field string, v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		if field == "priority" {
+			return pb.Priority(v).String()
+		}
+		if v == 0 {
+			return ""
+		}
+		return pb.TaskStatus(v).String()
+	case *timestamppb.Timestamp:
+		if v == nil {
+			return ""
+		}
+		return v.AsTime().Format(time.RFC3339Nano)
+	case *pb.Recurrence:
+		// start and next_id are kept by the service
+		if v == nil {
+			return ""
+		}
+		if v.Timezone == "" {
+			return v.Rule
+		}
+		return v.Rule + " in " + v.Timezone
+	case []int64:
+		// the order of the offsets makes no difference
+		offsets := append([]int64(nil), v...)
+		sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+		values := make([]string, len(offsets))
+		for i, offset := range offsets {
+			values[i] = strconv.FormatInt(offset, 10)
+		}
+		return strings.Join(values, ",")
+	}
+
+	return ""
+}
+
+// revisionField returns a field of revisionFields of todo
+func revisionField(todo *pb.TodoModel, field string) interface{} {
+	switch field {
+	case "recurrence":
+		return todo.Recurrence
+	case "remind_before":
+		return todo.RemindBefore
+	}
+
+	return repo.TodoField(todo, field)
+}
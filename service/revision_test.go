@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"github.com/golang/protobuf/proto"
+)
+
+func countRevisions(t *testing.T, s *todoService, todoID string) int64 {
+	t.Helper()
+
+	res, err := s.ListTodoRevisions(context.Background(), &pb.ListTodoRevisionsRequest{TodoId: todoID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.Count
+}
+
+func TestRevertTodo(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	todo := createReminded(t, s, time.Now().Add(time.Hour))
+	for _, name := range []string{"pay bills", "pay taxes"} {
+		todo.TaskName = name
+		var err error
+		if todo, err = s.UpdateTodo(ctx, todo); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := countRevisions(t, s, todo.Id); n != 3 {
+		t.Fatalf("%d revisions, want 3", n)
+	}
+
+	// the latest revision is the current state, reverting to it is a no-op
+	got, err := s.RevertTodo(ctx, &pb.RevertTodoRequest{Id: todo.Id, Revision: 3, ActorId: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != todo.Version || got.TaskName != "pay taxes" {
+		t.Errorf("no-op revert returned version %d %q, want version %d unchanged",
+			got.Version, got.TaskName, todo.Version)
+	}
+	if n := countRevisions(t, s, todo.Id); n != 3 {
+		t.Errorf("%d revisions after a no-op revert, want 3", n)
+	}
+
+	got, err = s.RevertTodo(ctx, &pb.RevertTodoRequest{Id: todo.Id, Revision: 1, ActorId: "user-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Version != todo.Version+1 || got.TaskName != "pay rent" {
+		t.Errorf("revert returned version %d %q, want version %d %q",
+			got.Version, got.TaskName, todo.Version+1, "pay rent")
+	}
+	revisions, err := s.ListTodoRevisions(ctx, &pb.ListTodoRevisionsRequest{TodoId: todo.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(revisions.Revisions); n != 4 {
+		t.Fatalf("%d revisions after a revert, want 4", n)
+	}
+	last := revisions.Revisions[3]
+	if last.Action != pb.RevisionAction_REVISION_REVERTED || last.RevertedTo != 1 || last.ActorId != "user-1" {
+		t.Errorf("revert recorded as %v to %d by %q", last.Action, last.RevertedTo, last.ActorId)
+	}
+}
+
+func TestRevertTodoRecurrence(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	created := createReminded(t, s, time.Now().Add(time.Hour), 3600)
+	update := proto.Clone(created).(*pb.TodoModel)
+	update.Recurrence = &pb.Recurrence{Rule: "FREQ=WEEKLY;BYDAY=MO", Timezone: "Europe/Berlin"}
+	update.RemindBefore = []int64{600}
+	weekly, err := s.UpdateTodo(ctx, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revisions, err := s.ListTodoRevisions(ctx, &pb.ListTodoRevisionsRequest{TodoId: created.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes := map[string]string{}
+	for _, change := range revisions.Revisions[1].Changes {
+		changes[change.Field] = change.From + " -> " + change.To
+	}
+	want := map[string]string{
+		"recurrence":    " -> " + weekly.Recurrence.Rule + " in Europe/Berlin",
+		"remind_before": "3600 -> 600",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+
+	reverted, err := s.RevertTodo(ctx, &pb.RevertTodoRequest{Id: created.Id, Revision: 1, ActorId: created.UserId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted.Recurrence != nil || !reflect.DeepEqual(reverted.RemindBefore, []int64{3600}) {
+		t.Errorf("reverted to recurrence %v, remind_before %v, want none and [3600]", reverted.Recurrence, reverted.RemindBefore)
+	}
+	expectReminders(t, s, created.Id, map[int64]time.Time{3600: created.DueAt.AsTime().Add(-time.Hour)})
+
+	// and forward again, the series starts over
+	reverted, err = s.RevertTodo(ctx, &pb.RevertTodoRequest{Id: created.Id, Revision: 2, ActorId: created.UserId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reverted.Recurrence.GetRule() != weekly.Recurrence.Rule || reverted.Recurrence.Timezone != "Europe/Berlin" ||
+		!reverted.Recurrence.Start.AsTime().Equal(reverted.DueAt.AsTime()) {
+		t.Errorf("reverted to recurrence %v, want %v", reverted.Recurrence, weekly.Recurrence)
+	}
+	if !reflect.DeepEqual(reverted.RemindBefore, []int64{600}) {
+		t.Errorf("reverted to remind_before %v, want [600]", reverted.RemindBefore)
+	}
+}
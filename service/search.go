@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/search"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fields of the search index, matches in the title count three times as
+// much as elsewhere
+const (
+	searchFieldTitle       = "title"
+	searchFieldDescription = "description"
+	searchFieldComments    = "comments"
+)
+
+// Kinds of suggestions
+const (
+	suggestKindTodo = "todo"
+	suggestKindList = "list"
+	suggestKindTag  = "tag"
+)
+
+const defaultSearchLimit = 10
+
+// searchSort is the order of search hits, best first. Cursors carry the
+// score of the last hit as the bits of the float64.
+var searchSort = []*pb.SortField{{Field: "score", Desc: true}}
+
+func newSearchIndex() *search.Index {
+	return search.NewIndex(
+		search.Field{Name: searchFieldTitle, Weight: 3},
+		search.Field{Name: searchFieldDescription, Weight: 1},
+		search.Field{Name: searchFieldComments, Weight: 1},
+	)
+}
+
+// BuildSearchIndex indexes the stored todos and their comments for search
+// and the todo titles and list names for suggestions, leaving out the
+// trash. It must be called once before serving.
+func (s *todoService) BuildSearchIndex() error {
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{})
+	if err != nil {
+		return err
+	}
+	// owners of the indexed todos, their comments are searched by them
+	owners := make(map[string]string, len(todos))
+	for _, todo := range todos {
+		s.search.Put(todo.Id, todo.UserId, todoSearchFields(todo))
+		s.suggestTodo(todo)
+		owners[todo.Id] = todo.UserId
+	}
+
+	lists, _, err := s.storage.TodoList().GetAll(&pb.ListTodoListsRequest{})
+	if err != nil {
+		return err
+	}
+	for _, list := range lists {
+		s.indexList(list)
+	}
+
+	comments, _, err := s.storage.Comment().GetAll(&pb.ListTodoCommentsRequest{})
+	if err != nil {
+		return err
+	}
+	bodies := make(map[string][]string)
+	for _, comment := range comments {
+		// the comments of todos in the trash aren't searched
+		if _, ok := owners[comment.TodoId]; !ok {
+			continue
+		}
+		bodies[comment.TodoId] = append(bodies[comment.TodoId], comment.Body)
+	}
+	for todoID, b := range bodies {
+		s.search.Put(todoID, owners[todoID], map[string][]string{searchFieldComments: b})
+	}
+
+	s.log.Info("search index built", logger.Int("todos", s.search.Len()))
+
+	return nil
+}
+
+// publish keeps the search index, suggestions and reminders up to date
+// with a change to a todo and notifies the watchers
+func (s *todoService) publish(eventType pb.TodoEventType, todo *pb.TodoModel) {
+	if eventType == pb.TodoEventType_TODO_DELETED {
+		s.search.Delete(todo.Id)
+		s.suggest.Delete(todo.Id)
+		s.suggest.Delete(tagsDocID(todo.Id))
+	} else {
+		s.search.Put(todo.Id, todo.UserId, todoSearchFields(todo))
+		s.suggestTodo(todo)
+	}
+	s.syncReminders(todo)
+
+	s.events.publish(eventType, todo)
+}
+
+// indexComments reindexes the comments of a todo after one of them changed.
+// The change is already stored, so a failure only leaves the index stale.
+func (s *todoService) indexComments(todoID string) {
+	todo, err := s.storage.Todo().Get(todoID)
+	if err != nil {
+		s.log.Error("failed to index comments", logger.String("todo_id", todoID), logger.Error(err))
+		return
+	}
+
+	comments, _, err := s.storage.Comment().GetAll(&pb.ListTodoCommentsRequest{TodoId: todoID})
+	if err != nil {
+		s.log.Error("failed to index comments", logger.String("todo_id", todoID), logger.Error(err))
+		return
+	}
+
+	bodies := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		bodies = append(bodies, comment.Body)
+	}
+	s.search.Put(todoID, todo.UserId, map[string][]string{searchFieldComments: bodies})
+}
+
+func (s *todoService) SearchTodos(ctx context.Context, req *pb.SearchTodosRequest) (*pb.SearchTodosResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	p, err := s.newPager(&pb.SearchTodosRequest{Query: req.Query, UserId: req.UserId}, req.Cursor, req.Page, limit, searchSort)
+	if err != nil {
+		return nil, err
+	}
+	page, fetch := p.window()
+
+	var (
+		hits  []search.Hit
+		total int
+	)
+	if p.from != nil {
+		score := math.Float64frombits(uint64(p.from.Values[0].GetIntValue()))
+		hits, total = s.search.SearchAfter(req.UserId, req.Query, score, p.from.Id, int(fetch))
+	} else {
+		hits, total = s.search.Search(req.UserId, req.Query, int((page-1)*limit), int(fetch))
+	}
+
+	res := &pb.SearchTodosResponse{
+		Hits:  make([]*pb.SearchHit, 0, len(hits)),
+		Count: int64(total),
+	}
+	keep, more := p.trim(len(hits), int64(total))
+	hits = hits[:keep]
+	if more {
+		last := hits[keep-1]
+		score := repo.FilterValueOf(int64(math.Float64bits(last.Score)))
+		res.NextCursor = p.encode([]*pb.FilterValue{score}, last.ID)
+	}
+	todos := make([]*pb.TodoModel, 0, len(hits))
+	for _, hit := range hits {
+		todo, err := s.liveTodo(hit.ID)
+		if errors.Is(err, repo.ErrNotFound) {
+			// deleted since the search
+			continue
+		}
+		if err != nil {
+			return nil, s.handleStorageError(err, "failed to get todo")
+		}
+
+		highlights := make([]*pb.SearchHighlight, 0, len(hit.Highlights))
+		for _, h := range hit.Highlights {
+			highlights = append(highlights, &pb.SearchHighlight{
+				Field:   h.Field,
+				Snippet: h.Snippet,
+			})
+		}
+
+		res.Hits = append(res.Hits, &pb.SearchHit{
+			Todo:       todo,
+			Score:      hit.Score,
+			Highlights: highlights,
+		})
+		todos = append(todos, todo)
+	}
+
+	if err := s.fillProgress(todos...); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return res, nil
+}
+
+// SuggestTodos completes a prefix of the titles and tags of the user's
+// todos and the names of their lists, archived lists are left out
+func (s *todoService) SuggestTodos(ctx context.Context, req *pb.SuggestTodosRequest) (*pb.SuggestTodosResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	suggestions := s.suggest.Suggest(req.UserId, req.Prefix, int(req.Limit))
+
+	res := &pb.SuggestTodosResponse{
+		Suggestions: make([]*pb.Suggestion, 0, len(suggestions)),
+	}
+	for _, sg := range suggestions {
+		res.Suggestions = append(res.Suggestions, &pb.Suggestion{
+			Text:  sg.Text,
+			Kind:  sg.Kind,
+			Count: int64(sg.Count),
+		})
+	}
+
+	return res, nil
+}
+
+// indexList suggests the name of a list to its owner while it isn't
+// archived
+func (s *todoService) indexList(list *pb.TodoList) {
+	if list.ArchivedAt != nil {
+		s.suggest.Delete(list.Id)
+		return
+	}
+
+	s.suggest.Put(list.Id, list.UserId, suggestKindList, list.Name)
+}
+
+// suggestTodo suggests the title and the tags of a todo to its owner
+func (s *todoService) suggestTodo(todo *pb.TodoModel) {
+	s.suggest.Put(todo.Id, todo.UserId, suggestKindTodo, todo.TaskName)
+	s.suggest.PutAll(tagsDocID(todo.Id), todo.UserId, suggestKindTag, repo.TodoTags(todo))
+}
+
+// tagsDocID is the suggestion document of the tags of a todo, apart from
+// the one of its title
+func tagsDocID(todoID string) string {
+	return "tags:" + todoID
+}
+
+func todoSearchFields(todo *pb.TodoModel) map[string][]string {
+	return map[string][]string{
+		searchFieldTitle:       {todo.TaskName},
+		searchFieldDescription: {todo.Description},
+	}
+}
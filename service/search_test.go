@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+)
+
+// suggested returns the suggestions for prefix by kind and text
+func suggested(t *testing.T, s *todoService, userID, prefix string) map[string]int64 {
+	t.Helper()
+
+	res, err := s.SuggestTodos(context.Background(), &pb.SuggestTodosRequest{UserId: userID, Prefix: prefix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int64, len(res.Suggestions))
+	for _, sg := range res.Suggestions {
+		got[sg.Kind+" "+sg.Text] = sg.Count
+	}
+
+	return got
+}
+
+func TestSuggestTags(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	create := func(userID, name, description string) *pb.TodoModel {
+		todo, err := s.CreateTodo(ctx, &pb.TodoModel{TaskName: name, Description: description, UserId: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return todo
+	}
+	first := create("user-1", "Pay rent #home", "")
+	create("user-1", "Call the bank", "for #Home and #work")
+	create("user-2", "Paint the wall #home", "")
+
+	want := map[string]int64{"tag #home": 2}
+	if got := suggested(t, s, "user-1", "#ho"); !reflect.DeepEqual(got, want) {
+		t.Errorf("suggested %v, want %v", got, want)
+	}
+	want = map[string]int64{"tag #work": 1}
+	if got := suggested(t, s, "user-1", "wo"); !reflect.DeepEqual(got, want) {
+		t.Errorf("suggested %v, want %v", got, want)
+	}
+
+	// tags follow the todo
+	first.TaskName = "Pay rent #bills"
+	if _, err := s.UpdateTodo(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	want = map[string]int64{"tag #home": 1}
+	if got := suggested(t, s, "user-1", "#ho"); !reflect.DeepEqual(got, want) {
+		t.Errorf("after update suggested %v, want %v", got, want)
+	}
+
+	if _, err := s.DeleteTodo(ctx, &pb.DeleteTodoRequest{Id: first.Id, ActorId: "user-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := suggested(t, s, "user-1", "#bi"); len(got) != 0 {
+		t.Errorf("after delete suggested %v, want nothing", got)
+	}
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *todoService) ListSubtasks(ctx context.Context, req *pb.ListSubtasksRequest) (*pb.ListSubtasksResponse, error) {
+	if _, err := s.liveTodo(req.Id); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	query := proto.Clone(req).(*pb.ListSubtasksRequest)
+	query.Limit, query.Cursor, query.WithCount = 0, "", false
+	p, err := s.newPager(query, req.Cursor, 0, req.Limit, repo.DefaultSort)
+	if err != nil {
+		return nil, err
+	}
+	list := &pb.ListTodosRequest{
+		ParentId:  req.Id,
+		After:     p.after(repo.TodoFilterFields),
+		WithCount: p.counts(req.WithCount),
+	}
+	list.Page, list.Limit = p.window()
+
+	subtasks, count, err := s.storage.Todo().GetAll(list)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list subtasks")
+	}
+
+	res := &pb.ListSubtasksResponse{}
+	if req.WithCount {
+		res.Count = count
+	}
+	keep, more := p.trim(len(subtasks), count)
+	subtasks = subtasks[:keep]
+	if more {
+		last := subtasks[keep-1]
+		res.NextCursor = p.next(func(field string) interface{} { return repo.TodoField(last, field) }, last.Id)
+	}
+	if res.Subtasks, err = s.nodes(subtasks, req.Recursive); err != nil {
+		return nil, s.handleStorageError(err, "failed to list subtasks")
+	}
+
+	return res, nil
+}
+
+func (s *todoService) subtaskNodes(id string, recursive bool) ([]*pb.TodoNode, error) {
+	subtasks, err := s.subtasks(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.nodes(subtasks, recursive)
+}
+
+// nodes returns subtasks as nodes, with their subtrees when recursive
+func (s *todoService) nodes(subtasks []*pb.TodoModel, recursive bool) ([]*pb.TodoNode, error) {
+	if err := s.fillProgress(subtasks...); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]*pb.TodoNode, 0, len(subtasks))
+	for _, subtask := range subtasks {
+		node := &pb.TodoNode{
+			Todo: subtask,
+		}
+		if recursive {
+			var err error
+			if node.Subtasks, err = s.subtaskNodes(subtask.Id, true); err != nil {
+				return nil, err
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	return nodes, nil
+}
+
+// subtasks returns the direct subtasks of a todo, leaving out the ones in
+// the trash
+func (s *todoService) subtasks(id string) ([]*pb.TodoModel, error) {
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		ParentId: id,
+	})
+	return todos, err
+}
+
+// descendants returns every subtask below a todo, parents before their
+// subtasks. trashed walks the subtasks in the trash instead.
+func (s *todoService) descendants(id string, trashed bool) ([]*pb.TodoModel, error) {
+	var res []*pb.TodoModel
+
+	queue := []string{id}
+	for len(queue) > 0 {
+		subtasks, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+			ParentId: queue[0],
+			Trashed:  trashed,
+		})
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+
+		for _, subtask := range subtasks {
+			res = append(res, subtask)
+			queue = append(queue, subtask.Id)
+		}
+	}
+
+	return res, nil
+}
+
+// height returns the number of levels in the subtree of a todo, 1 for a
+// todo without subtasks
+func (s *todoService) height(id string) (int, error) {
+	subtasks, err := s.subtasks(id)
+	if err != nil {
+		return 0, err
+	}
+
+	height := 0
+	for _, subtask := range subtasks {
+		h, err := s.height(subtask.Id)
+		if err != nil {
+			return 0, err
+		}
+		if h > height {
+			height = h
+		}
+	}
+
+	return height + 1, nil
+}
+
+// checkParent validates the parent of todo: it must exist, must not be the
+// todo itself or one of its subtasks, and the todo with its subtasks must
+// not end up deeper than maxDepth.
+func (s *todoService) checkParent(todo *pb.TodoModel) error {
+	if todo.ParentId == "" {
+		return nil
+	}
+
+	// walk up from the parent, depth ends as the depth of the parent
+	depth := 0
+	for id := todo.ParentId; id != ""; depth++ {
+		if id == todo.Id {
+			return status.Error(codes.InvalidArgument, "parent_id can't be the todo or one of its subtasks")
+		}
+		if depth == s.maxDepth {
+			return status.Errorf(codes.InvalidArgument, "subtasks can be nested at most %d levels deep", s.maxDepth)
+		}
+
+		parent, err := s.liveTodo(id)
+		if errors.Is(err, repo.ErrNotFound) {
+			return status.Errorf(codes.InvalidArgument, "parent todo %s not found", id)
+		}
+		if err != nil {
+			return s.handleStorageError(err, "failed to get parent todo")
+		}
+		if id == todo.ParentId {
+			if err = checkTodoOwner(parent, todo.UserId, "add subtasks to a todo"); err != nil {
+				return err
+			}
+		}
+		id = parent.ParentId
+	}
+
+	height := 1
+	if todo.Id != "" {
+		h, err := s.height(todo.Id)
+		if err != nil {
+			return s.handleStorageError(err, "failed to list subtasks")
+		}
+		height = h
+	}
+
+	if depth+height > s.maxDepth {
+		return status.Errorf(codes.InvalidArgument, "subtasks can be nested at most %d levels deep", s.maxDepth)
+	}
+
+	return nil
+}
+
+// fillProgress sets the subtask progress of todos
+func (s *todoService) fillProgress(todos ...*pb.TodoModel) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.Id)
+	}
+
+	progress, err := s.storage.Todo().SubtaskProgress(ids)
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		todo.Progress = progress[todo.Id]
+	}
+
+	return nil
+}
@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/abdukhashimov/go_gin_example/config"
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/cursor"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/pkg/scheduler"
+	"github.com/abdukhashimov/go_gin_example/pkg/search"
+	"github.com/abdukhashimov/go_gin_example/pkg/sortspec"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"github.com/abdukhashimov/go_gin_example/storage/blob"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Reasons attached to FailedPrecondition errors, the gateway reports them
+// as the error code
+const (
+	reasonInvalidTransition = "INVALID_TRANSITION"
+	reasonHasSubtasks       = "HAS_SUBTASKS"
+	reasonInboxList         = "INBOX_LIST"
+	reasonListArchived      = "LIST_ARCHIVED"
+	reasonFileTooLarge      = "FILE_TOO_LARGE"
+	reasonQuotaExceeded     = "QUOTA_EXCEEDED"
+	reasonNotTrashed        = "NOT_IN_TRASH"
+	reasonParentTrashed     = "PARENT_IN_TRASH"
+	reasonNotRecurring      = "NOT_RECURRING"
+	// reasonVersionConflict is attached to Aborted errors, which carry the
+	// current todo as a detail
+	reasonVersionConflict = "VERSION_CONFLICT"
+)
+
+var (
+	todoSort = sortspec.NewSchema(repo.TodoSortFields...)
+	listSort = sortspec.NewSchema(repo.TodoListSortFields...)
+)
+
+type todoService struct {
+	log       logger.Logger
+	storage   storage.StorageI
+	blobs     blob.Store
+	events    *eventBroker
+	search    *search.Index
+	suggest   *search.Completer
+	cursors   *cursor.Signer
+	workflows Workflows
+	maxDepth  int
+
+	attachmentMaxSize   int64
+	attachmentUserQuota int64
+
+	// blobMu is held for reading while an upload stores its content and
+	// metadata, and for writing while a delete checks if the content is
+	// still referenced, so a delete never removes content an upload reuses
+	blobMu sync.RWMutex
+
+	// inboxMu keeps concurrent requests from creating two inboxes
+	inboxMu sync.Mutex
+
+	clock           scheduler.Clock
+	scheduler       *scheduler.Scheduler
+	reminderHooks   []ReminderHook
+	reminderTimeout time.Duration
+	// reminderMu keeps a reminder from being delivered while its todo
+	// changes
+	reminderMu sync.Mutex
+}
+
+// NewTodoService returns the service, reminders are scheduled by clock
+// once RunReminders is started
+func NewTodoService(cfg config.Config, log logger.Logger, strg storage.StorageI, blobs blob.Store, workflows Workflows, cursors *cursor.Signer, clock scheduler.Clock) (*todoService, error) {
+	s := &todoService{
+		log:       log,
+		storage:   strg,
+		blobs:     blobs,
+		events:    newEventBroker(),
+		search:    newSearchIndex(),
+		suggest:   search.NewCompleter(),
+		cursors:   cursors,
+		workflows: workflows,
+		maxDepth:  cfg.TodoMaxDepth,
+
+		attachmentMaxSize:   cfg.AttachmentMaxSize,
+		attachmentUserQuota: cfg.AttachmentUserQuota,
+
+		clock:           clock,
+		reminderTimeout: time.Duration(cfg.ReminderTimeout) * time.Millisecond,
+	}
+	s.scheduler = scheduler.New(clock, s.fireReminder)
+
+	var err error
+	if s.reminderHooks, err = newReminderHooks(cfg, log, s.events); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *todoService) CreateTodo(ctx context.Context, req *pb.TodoModel) (*pb.TodoModel, error) {
+	if err := checkRecurrence(req, nil); err != nil {
+		return nil, err
+	}
+
+	return s.createTodo(req)
+}
+
+// createTodo stores a new todo, its recurrence is already checked
+func (s *todoService) createTodo(req *pb.TodoModel) (*pb.TodoModel, error) {
+	if err := validateTodo(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkList(req.ListId); err != nil {
+		return nil, err
+	}
+
+	req.Id = uuid.New().String()
+	req.Progress = nil
+	req.DeletedAt = nil
+	if req.TaskStatus == pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		req.TaskStatus = pb.TaskStatus_TASK_STATUS_TODO
+	}
+	if !s.workflows.get(req.WorkspaceId).has(req.TaskStatus) {
+		return nil, status.Errorf(codes.InvalidArgument, "task_status %s is not part of the workflow", statusName(req.TaskStatus))
+	}
+	req.CreatedAt = timestamppb.Now()
+	req.UpdatedAt = req.CreatedAt
+	req.UpdatedBy = req.UserId
+	req.Version = 1
+
+	todo, err := s.storage.Todo().Create(req)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to create todo")
+	}
+	rev := &pb.TodoRevision{Action: pb.RevisionAction_REVISION_CREATED}
+	if err = s.recordRevision(rev, &pb.TodoModel{}, todo); err != nil {
+		return nil, err
+	}
+	s.publish(pb.TodoEventType_TODO_CREATED, todo)
+
+	return todo, nil
+}
+
+func (s *todoService) GetTodo(ctx context.Context, req *pb.GetTodoRequest) (*pb.TodoModel, error) {
+	todo, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	if err = s.fillProgress(todo); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return todo, nil
+}
+
+func (s *todoService) ListTodos(ctx context.Context, req *pb.ListTodosRequest) (*pb.ListTodosResponse, error) {
+	if err := checkSort(req.Sort, todoSort); err != nil {
+		return nil, err
+	}
+	if err := repo.CheckFilter(req.Filter, repo.TodoFilterFields); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query := proto.Clone(req).(*pb.ListTodosRequest)
+	query.Page, query.Limit, query.Cursor, query.After, query.WithCount = 0, 0, "", nil, false
+	p, err := s.newPager(query, req.Cursor, req.Page, req.Limit, sortOrDefault(req.Sort))
+	if err != nil {
+		return nil, err
+	}
+	withCount := req.WithCount
+	req.After = p.after(repo.TodoFilterFields)
+	req.Page, req.Limit = p.window()
+	req.WithCount = p.counts(withCount)
+
+	todos, count, err := s.storage.Todo().GetAll(req)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list todos")
+	}
+
+	res := &pb.ListTodosResponse{}
+	if withCount {
+		res.Count = count
+	}
+	keep, more := p.trim(len(todos), count)
+	res.Todos = todos[:keep]
+	if more {
+		last := res.Todos[keep-1]
+		res.NextCursor = p.next(func(field string) interface{} { return repo.TodoField(last, field) }, last.Id)
+	}
+
+	if err = s.fillProgress(res.Todos...); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return res, nil
+}
+
+func (s *todoService) UpdateTodo(ctx context.Context, req *pb.TodoModel) (*pb.TodoModel, error) {
+	return s.updateTodo(req, &pb.TodoRevision{Action: pb.RevisionAction_REVISION_UPDATED})
+}
+
+// updateTodo stores a change a client made to a todo, which is recorded
+// as rev
+func (s *todoService) updateTodo(req *pb.TodoModel, rev *pb.TodoRevision) (*pb.TodoModel, error) {
+	if err := validateTodo(req); err != nil {
+		return nil, err
+	}
+
+	current, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkTodoOwner(current, req.UpdatedBy, "update a todo"); err != nil {
+		return nil, err
+	}
+	if req.Version != 0 && req.Version != current.Version {
+		return nil, s.currentVersionConflict(req.Id)
+	}
+	req.Version = current.Version
+	if err = checkRecurrence(req, current); err != nil {
+		return nil, err
+	}
+	// the owner, workspace and creation time are set once on create
+	req.UserId = current.UserId
+	req.WorkspaceId = current.WorkspaceId
+	req.CreatedAt = current.CreatedAt
+	req.UpdatedAt = timestamppb.Now()
+	req.Progress = nil
+	req.DeletedAt = nil
+	if req.TaskStatus == pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		req.TaskStatus = current.TaskStatus
+	}
+
+	if req.ParentId != current.ParentId {
+		if err = s.checkParent(req); err != nil {
+			return nil, err
+		}
+	}
+	if req.ListId != current.ListId {
+		if err = s.checkList(req.ListId); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.TaskStatus != current.TaskStatus {
+		if err = s.checkTransition(current, req.TaskStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	todo, err := s.storage.Todo().Update(req)
+	if errors.Is(err, repo.ErrVersionConflict) {
+		// changed since it was read above
+		return nil, s.currentVersionConflict(req.Id)
+	}
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to update todo")
+	}
+
+	if todo.TaskStatus != current.TaskStatus {
+		if err = s.recordTransition(current, todo, ""); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.recordRevision(rev, current, todo); err != nil {
+		return nil, err
+	}
+	if todo, err = s.nextOccurrence(current, todo); err != nil {
+		return nil, err
+	}
+	s.publish(pb.TodoEventType_TODO_UPDATED, todo)
+
+	if err = s.fillProgress(todo); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return todo, nil
+}
+
+// DeleteTodo moves a todo with its subtasks to the trash, PurgeTodo deletes
+// it for good
+func (s *todoService) DeleteTodo(ctx context.Context, req *pb.DeleteTodoRequest) (*emptypb.Empty, error) {
+	todo, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkTodoOwner(todo, req.ActorId, "delete a todo"); err != nil {
+		return nil, err
+	}
+	if req.Version != 0 && req.Version != todo.Version {
+		return nil, s.currentVersionConflict(req.Id)
+	}
+
+	descendants, err := s.descendants(todo.Id, false)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list subtasks")
+	}
+	if len(descendants) > 0 && !req.Recursive {
+		return nil, failedPrecondition(reasonHasSubtasks, "todo has %d subtasks, delete them first or delete recursively", len(descendants))
+	}
+
+	if err = s.trashTree(todo, descendants, timestamppb.Now(), req.ActorId); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// deleteTree deletes a todo with its descendants for good. Subtasks go
+// first, so a failure never leaves orphans behind.
+func (s *todoService) deleteTree(todo *pb.TodoModel, descendants []*pb.TodoModel) error {
+	for i := len(descendants) - 1; i >= 0; i-- {
+		if err := s.deleteTodo(descendants[i]); err != nil {
+			return err
+		}
+	}
+
+	return s.deleteTodo(todo)
+}
+
+func (s *todoService) deleteTodo(todo *pb.TodoModel) error {
+	if err := s.storage.Comment().DeleteAll(todo.Id); err != nil {
+		return s.handleStorageError(err, "failed to delete comments")
+	}
+	if err := s.deleteAttachments(todo.Id); err != nil {
+		return err
+	}
+	// watchers and the search index saw it go to the trash already
+	if err := s.storage.Todo().Delete(todo.Id); err != nil {
+		return s.handleStorageError(err, "failed to delete todo")
+	}
+
+	return nil
+}
+
+func (s *todoService) WatchTodos(req *pb.WatchTodosRequest, stream pb.TodoService_WatchTodosServer) error {
+	sub, backlog, lost := s.events.subscribe(req.UserId, req.LastEventId)
+	defer s.events.unsubscribe(sub)
+
+	if lost {
+		err := stream.Send(&pb.TodoEvent{
+			Type:       pb.TodoEventType_TODO_EVENTS_LOST,
+			OccurredAt: timestamppb.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, event := range backlog {
+		if err := stream.Send(event); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-sub.events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher fell too far behind, resume with last_event_id")
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkSort rejects the fields the storages can't sort by
+func checkSort(fields []*pb.SortField, schema *sortspec.Schema) error {
+	for _, f := range fields {
+		if _, err := schema.Check(f.Field); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// validateTodo checks the fields a client may set on create and update
+func validateTodo(todo *pb.TodoModel) error {
+	if todo.TaskName == "" {
+		return status.Error(codes.InvalidArgument, "task_name is required")
+	}
+	if _, ok := pb.TaskStatus_name[int32(todo.TaskStatus)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown task_status %d", todo.TaskStatus)
+	}
+	if _, ok := pb.Priority_name[int32(todo.Priority)]; !ok {
+		return status.Errorf(codes.InvalidArgument, "unknown priority %d", todo.Priority)
+	}
+	if todo.DueAt != nil {
+		if err := todo.DueAt.CheckValid(); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid due_at: %v", err)
+		}
+	}
+	if err := checkRemindBefore(todo.RemindBefore); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkOwner fails unless actorID is ownerID, items without an owner have
+// none to act on them
+func checkOwner(ownerID, actorID, action string) error {
+	if actorID == "" || ownerID != actorID {
+		return status.Errorf(codes.PermissionDenied, "only the owner can %s", action)
+	}
+
+	return nil
+}
+
+// checkTodoOwner fails unless actorID owns todo, todos created without a
+// user can be changed by anyone
+func checkTodoOwner(todo *pb.TodoModel, actorID, action string) error {
+	if todo.UserId == "" {
+		return nil
+	}
+
+	return checkOwner(todo.UserId, actorID, action)
+}
+
+// failedPrecondition returns a FailedPrecondition error carrying reason as
+// error info
+func failedPrecondition(reason, format string, args ...interface{}) error {
+	return errorWithReason(codes.FailedPrecondition, reason, format, args...)
+}
+
+// errorWithReason returns an error with code carrying reason as error info
+func errorWithReason(code codes.Code, reason, format string, args ...interface{}) error {
+	st := status.Newf(code, format, args...)
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// versionConflict returns an Aborted error carrying the current todo, so
+// the client can merge its change without reading it again
+func versionConflict(current *pb.TodoModel) error {
+	st := status.Newf(codes.Aborted, "todo %s is at version %d", current.Id, current.Version)
+
+	detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reasonVersionConflict,
+	}, current)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// currentVersionConflict reads the todo an update lost the race for and
+// returns its versionConflict
+func (s *todoService) currentVersionConflict(id string) error {
+	current, err := s.liveTodo(id)
+	if err != nil {
+		return s.handleStorageError(err, "failed to get todo")
+	}
+	if err = s.fillProgress(current); err != nil {
+		return s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return versionConflict(current)
+}
+
+// handleStorageError logs the error and converts it into a grpc status
+func (s *todoService) handleStorageError(err error, message string) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "todo not found")
+	}
+	if errors.Is(err, repo.ErrVersionConflict) {
+		return errorWithReason(codes.Aborted, reasonVersionConflict, "todo changed concurrently, try again")
+	}
+
+	s.log.Error(message, logger.Error(err))
+	return status.Error(codes.Internal, message)
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const inboxName = "Inbox"
+
+func (s *todoService) CreateTodoList(ctx context.Context, req *pb.TodoList) (*pb.TodoList, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	req.Id = uuid.New().String()
+	// inboxes are only created by the service
+	req.Inbox = false
+	req.ArchivedAt = nil
+	req.DeletedAt = nil
+	req.CreatedAt = timestamppb.Now()
+	req.UpdatedAt = req.CreatedAt
+
+	list, err := s.storage.TodoList().Create(req)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to create list")
+	}
+	s.indexList(list)
+
+	return list, nil
+}
+
+func (s *todoService) GetTodoList(ctx context.Context, req *pb.GetTodoListRequest) (*pb.TodoList, error) {
+	list, err := s.liveList(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+
+	return list, nil
+}
+
+func (s *todoService) ListTodoLists(ctx context.Context, req *pb.ListTodoListsRequest) (*pb.ListTodoListsResponse, error) {
+	if err := checkSort(req.Sort, listSort); err != nil {
+		return nil, err
+	}
+	if err := repo.CheckFilter(req.Filter, repo.TodoListFilterFields); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	query := proto.Clone(req).(*pb.ListTodoListsRequest)
+	query.Page, query.Limit, query.Cursor, query.After, query.WithCount = 0, 0, "", nil, false
+	p, err := s.newPager(query, req.Cursor, req.Page, req.Limit, sortOrDefault(req.Sort))
+	if err != nil {
+		return nil, err
+	}
+	withCount := req.WithCount
+	req.After = p.after(repo.TodoListFilterFields)
+	req.Page, req.Limit = p.window()
+	req.WithCount = p.counts(withCount)
+
+	lists, count, err := s.storage.TodoList().GetAll(req)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to list lists")
+	}
+
+	res := &pb.ListTodoListsResponse{}
+	if withCount {
+		res.Count = count
+	}
+	keep, more := p.trim(len(lists), count)
+	res.Lists = lists[:keep]
+	if more {
+		last := res.Lists[keep-1]
+		res.NextCursor = p.next(func(field string) interface{} { return repo.TodoListField(last, field) }, last.Id)
+	}
+
+	return res, nil
+}
+
+func (s *todoService) UpdateTodoList(ctx context.Context, req *pb.TodoList) (*pb.TodoList, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	current, err := s.liveList(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+	if err = checkOwner(current.UserId, req.UserId, "update a list"); err != nil {
+		return nil, err
+	}
+
+	update := proto.Clone(current).(*pb.TodoList)
+	update.Name = req.Name
+	update.Description = req.Description
+	update.UpdatedAt = timestamppb.Now()
+
+	list, err := s.storage.TodoList().Update(update)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to update list")
+	}
+	s.indexList(list)
+
+	return list, nil
+}
+
+// DeleteTodoList moves a list to the trash, PurgeTodoList deletes it for
+// good
+func (s *todoService) DeleteTodoList(ctx context.Context, req *pb.DeleteTodoListRequest) (*emptypb.Empty, error) {
+	list, err := s.liveList(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+	if err = checkOwner(list.UserId, req.ActorId, "delete a list"); err != nil {
+		return nil, err
+	}
+	if list.Inbox {
+		return nil, failedPrecondition(reasonInboxList, "the inbox list can't be deleted")
+	}
+
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		ListId: list.Id,
+	})
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list todos")
+	}
+
+	update := proto.Clone(list).(*pb.TodoList)
+	update.UpdatedAt = timestamppb.Now()
+	update.DeletedAt = update.UpdatedAt
+
+	switch req.Todos {
+	case pb.DeleteTodoListRequest_CASCADE:
+		err = s.trashListTodos(todos, update.DeletedAt, req.ActorId)
+	default:
+		err = s.moveListTodos(list, todos, req.ActorId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.storage.TodoList().Update(update); err != nil {
+		return nil, s.handleListStorageError(err, "failed to update list")
+	}
+	s.suggest.Delete(list.Id)
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *todoService) ArchiveTodoList(ctx context.Context, req *pb.ArchiveTodoListRequest) (*pb.TodoList, error) {
+	current, err := s.liveList(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+	if err = checkOwner(current.UserId, req.ActorId, "archive a list"); err != nil {
+		return nil, err
+	}
+	if current.Inbox {
+		return nil, failedPrecondition(reasonInboxList, "the inbox list can't be archived")
+	}
+
+	if req.Archived == (current.ArchivedAt != nil) {
+		return current, nil
+	}
+
+	update := proto.Clone(current).(*pb.TodoList)
+	update.UpdatedAt = timestamppb.Now()
+	update.ArchivedAt = nil
+	if req.Archived {
+		update.ArchivedAt = update.UpdatedAt
+	}
+
+	list, err := s.storage.TodoList().Update(update)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to update list")
+	}
+	s.indexList(list)
+
+	return list, nil
+}
+
+// trashListTodos moves todos with all their subtasks to the trash, deleted
+// at the time of their list. Subtasks may be in the same list and already
+// in the trash.
+func (s *todoService) trashListTodos(todos []*pb.TodoModel, at *timestamppb.Timestamp, actorID string) error {
+	for _, todo := range todos {
+		todo, err := s.liveTodo(todo.Id)
+		if errors.Is(err, repo.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return s.handleStorageError(err, "failed to get todo")
+		}
+
+		descendants, err := s.descendants(todo.Id, false)
+		if err != nil {
+			return s.handleStorageError(err, "failed to list subtasks")
+		}
+		if err = s.trashTree(todo, descendants, at, actorID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveListTodos moves todos to the inbox of the list's owner
+func (s *todoService) moveListTodos(list *pb.TodoList, todos []*pb.TodoModel, actorID string) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	inbox, err := s.inbox(list.UserId)
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		update := proto.Clone(todo).(*pb.TodoModel)
+		update.ListId = inbox.Id
+		update.UpdatedAt = timestamppb.Now()
+		update.UpdatedBy = actorID
+
+		updated, err := s.storage.Todo().Update(update)
+		if err != nil {
+			return s.handleStorageError(err, "failed to move todo to inbox")
+		}
+		rev := &pb.TodoRevision{Action: pb.RevisionAction_REVISION_UPDATED}
+		if err = s.recordRevision(rev, todo, updated); err != nil {
+			return err
+		}
+		s.publish(pb.TodoEventType_TODO_UPDATED, updated)
+	}
+
+	return nil
+}
+
+// inbox returns the inbox list of an owner, creating it on first use
+func (s *todoService) inbox(userID string) (*pb.TodoList, error) {
+	s.inboxMu.Lock()
+	defer s.inboxMu.Unlock()
+
+	inbox, err := s.storage.TodoList().GetInbox(userID)
+	if err == nil {
+		return inbox, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return nil, s.handleListStorageError(err, "failed to get inbox")
+	}
+
+	now := timestamppb.Now()
+	inbox, err = s.storage.TodoList().Create(&pb.TodoList{
+		Id:        uuid.New().String(),
+		Name:      inboxName,
+		UserId:    userID,
+		Inbox:     true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to create inbox")
+	}
+	s.indexList(inbox)
+
+	return inbox, nil
+}
+
+// checkList validates the list a todo is put into, it must exist outside
+// the trash and must not be archived
+func (s *todoService) checkList(listID string) error {
+	if listID == "" {
+		return nil
+	}
+
+	list, err := s.liveList(listID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Errorf(codes.InvalidArgument, "list %s not found", listID)
+	}
+	if err != nil {
+		return s.handleListStorageError(err, "failed to get list")
+	}
+
+	if list.ArchivedAt != nil {
+		return failedPrecondition(reasonListArchived, "list %s is archived", listID)
+	}
+
+	return nil
+}
+
+// handleListStorageError is handleStorageError for lists
+func (s *todoService) handleListStorageError(err error, message string) error {
+	if errors.Is(err, repo.ErrNotFound) {
+		return status.Error(codes.NotFound, "list not found")
+	}
+
+	return s.handleStorageError(err, message)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOnlyOwnerChangesTodo(t *testing.T) {
+	ctx := context.Background()
+	actions := []struct {
+		name string
+		do   func(s *todoService, todo *pb.TodoModel, actorID string) error
+	}{
+		{"update", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			_, err := s.UpdateTodo(ctx, &pb.TodoModel{Id: todo.Id, TaskName: "renamed", UpdatedBy: actorID})
+			return err
+		}},
+		{"delete", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			_, err := s.DeleteTodo(ctx, &pb.DeleteTodoRequest{Id: todo.Id, ActorId: actorID})
+			return err
+		}},
+		{"transition", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			_, err := s.TransitionTodo(ctx, &pb.TransitionTodoRequest{Id: todo.Id, To: pb.TaskStatus_TASK_STATUS_DONE, ActorId: actorID})
+			return err
+		}},
+		{"revert", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			_, err := s.RevertTodo(ctx, &pb.RevertTodoRequest{Id: todo.Id, Revision: 1, ActorId: actorID})
+			return err
+		}},
+		{"add a subtask", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			_, err := s.CreateTodo(ctx, &pb.TodoModel{TaskName: "subtask", ParentId: todo.Id, UserId: actorID})
+			return err
+		}},
+		{"batch update", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			res, err := s.BatchUpdateTodos(ctx, &pb.BatchUpdateTodosRequest{
+				Todos: []*pb.TodoModel{{Id: todo.Id, TaskName: "renamed", UpdatedBy: actorID}},
+			})
+			if err != nil {
+				return err
+			}
+			return status.Error(codes.Code(res.Results[0].Code), res.Results[0].Message)
+		}},
+		{"batch delete", func(s *todoService, todo *pb.TodoModel, actorID string) error {
+			res, err := s.BatchDeleteTodos(ctx, &pb.BatchDeleteTodosRequest{Ids: []string{todo.Id}, ActorId: actorID})
+			if err != nil {
+				return err
+			}
+			return status.Error(codes.Code(res.Results[0].Code), res.Results[0].Message)
+		}},
+	}
+	for _, action := range actions {
+		t.Run(action.name, func(t *testing.T) {
+			s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+			todo := createReminded(t, s, time.Now().Add(time.Hour))
+
+			// another user and an anonymous caller
+			for _, actorID := range []string{"user-2", ""} {
+				if err := action.do(s, todo, actorID); status.Code(err) != codes.PermissionDenied {
+					t.Errorf("%q: error = %v, want PermissionDenied", actorID, err)
+				}
+			}
+			stored, err := s.storage.Todo().Get(todo.Id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Version != todo.Version || stored.DeletedAt != nil {
+				t.Errorf("todo changed by others: version %d, deleted at %v", stored.Version, stored.DeletedAt)
+			}
+
+			if err = action.do(s, todo, todo.UserId); err != nil {
+				t.Errorf("owner: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnyoneChangesTodoWithoutOwner(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	todo, err := s.CreateTodo(ctx, &pb.TodoModel{TaskName: "pay rent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.UpdateTodo(ctx, &pb.TodoModel{Id: todo.Id, TaskName: "renamed", UpdatedBy: "user-2"}); err != nil {
+		t.Errorf("update: %v", err)
+	}
+	if _, err = s.CreateTodo(ctx, &pb.TodoModel{TaskName: "subtask", ParentId: todo.Id}); err != nil {
+		t.Errorf("add a subtask: %v", err)
+	}
+	if _, err = s.DeleteTodo(ctx, &pb.DeleteTodoRequest{Id: todo.Id, Recursive: true}); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+}
+
+func TestListTodosByTag(t *testing.T) {
+	s := newReminderService(t, storage.NewStorageMemory(), &fakeClock{now: time.Now()})
+	ctx := context.Background()
+
+	names := map[string]string{}
+	for _, todo := range []*pb.TodoModel{
+		{TaskName: "deploy #Ops", Description: "see #runbook"},
+		{TaskName: "groceries", Description: "#errands"},
+		{TaskName: "C# upgrade"},
+	} {
+		todo.UserId = "user-1"
+		created, err := s.CreateTodo(ctx, todo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		names[created.Id] = created.TaskName
+	}
+
+	tag := func(op pb.FilterCondition_Operator, tags ...string) *pb.FilterExpr {
+		c := &pb.FilterCondition{Field: "tag", Op: op}
+		for _, tag := range tags {
+			c.Values = append(c.Values, &pb.FilterValue{Value: &pb.FilterValue_StringValue{StringValue: tag}})
+		}
+		return &pb.FilterExpr{Expr: &pb.FilterExpr_Condition{Condition: c}}
+	}
+	tests := []struct {
+		name   string
+		filter *pb.FilterExpr
+		want   []string
+	}{
+		{"equal", tag(pb.FilterCondition_EQ, "#ops"), []string{"deploy #Ops"}},
+		{"without # in any case", tag(pb.FilterCondition_EQ, "RUNBOOK"), []string{"deploy #Ops"}},
+		{"not equal", tag(pb.FilterCondition_NE, "ops"), []string{"groceries", "C# upgrade"}},
+		{"in", tag(pb.FilterCondition_IN, "errands", "ops"), []string{"deploy #Ops", "groceries"}},
+		{"not in", tag(pb.FilterCondition_NOT_IN, "errands", "ops"), []string{"C# upgrade"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := s.ListTodos(ctx, &pb.ListTodosRequest{Filter: tt.filter})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, todo := range res.Todos {
+				got = append(got, names[todo.Id])
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("listed %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, filter := range []*pb.FilterExpr{tag(pb.FilterCondition_GT, "ops"), tag(pb.FilterCondition_EQ, "two words")} {
+		if _, err := s.ListTodos(ctx, &pb.ListTodosRequest{Filter: filter}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListTodos(%v) error = %v, want InvalidArgument", filter, err)
+		}
+	}
+}
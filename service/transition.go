@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *todoService) TransitionTodo(ctx context.Context, req *pb.TransitionTodoRequest) (*pb.TodoModel, error) {
+	if req.To == pb.TaskStatus_TASK_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "to is required")
+	}
+
+	current, err := s.liveTodo(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkTodoOwner(current, req.ActorId, "change the status of a todo"); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkTransition(current, req.To); err != nil {
+		return nil, err
+	}
+
+	var subtasks []*pb.TodoModel
+	if req.Recursive {
+		descendants, err := s.descendants(current.Id, false)
+		if err != nil {
+			return nil, s.handleStorageError(err, "failed to list subtasks")
+		}
+
+		// everything is checked first, so a subtask which can't move
+		// leaves the whole tree unchanged
+		for _, subtask := range descendants {
+			workflow := s.workflows.get(subtask.WorkspaceId)
+			if subtask.TaskStatus == req.To || len(workflow[subtask.TaskStatus]) == 0 {
+				continue
+			}
+			if err = s.checkTransition(subtask, req.To); err != nil {
+				return nil, err
+			}
+			subtasks = append(subtasks, subtask)
+		}
+	}
+
+	for _, subtask := range subtasks {
+		if _, err = s.applyTransition(subtask, req); err != nil {
+			return nil, err
+		}
+	}
+
+	todo, err := s.applyTransition(current, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fillProgress(todo); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return todo, nil
+}
+
+// transitionSort is the order transitions are listed in
+var transitionSort = []*pb.SortField{{Field: "occurred_at"}}
+
+func (s *todoService) ListTodoTransitions(ctx context.Context, req *pb.ListTodoTransitionsRequest) (*pb.ListTodoTransitionsResponse, error) {
+	if _, err := s.liveTodo(req.TodoId); err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+
+	query := proto.Clone(req).(*pb.ListTodoTransitionsRequest)
+	query.Limit, query.Cursor = 0, ""
+	p, err := s.newPager(query, req.Cursor, 0, req.Limit, transitionSort)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions, err := s.storage.Transition().GetAll(req.TodoId)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list transitions")
+	}
+	start, end, next := p.slice(transitions, len(transitions), func(i int) ([]interface{}, string) {
+		return []interface{}{transitions[i].OccurredAt}, transitions[i].Id
+	})
+
+	return &pb.ListTodoTransitionsResponse{
+		Transitions: transitions[start:end],
+		Count:       int64(len(transitions)),
+		NextCursor:  next,
+	}, nil
+}
+
+// checkTransition returns FailedPrecondition when the workflow of the
+// todo's workspace does not allow moving it to status
+func (s *todoService) checkTransition(todo *pb.TodoModel, to pb.TaskStatus) error {
+	if s.workflows.get(todo.WorkspaceId).allows(todo.TaskStatus, to) {
+		return nil
+	}
+
+	return failedPrecondition(reasonInvalidTransition, "todo %s can not move from %s to %s",
+		todo.Id, statusName(todo.TaskStatus), statusName(to))
+}
+
+// applyTransition moves a checked todo to req.To and records it
+func (s *todoService) applyTransition(current *pb.TodoModel, req *pb.TransitionTodoRequest) (*pb.TodoModel, error) {
+	update := proto.Clone(current).(*pb.TodoModel)
+	update.TaskStatus = req.To
+	update.UpdatedAt = timestamppb.Now()
+	update.UpdatedBy = req.ActorId
+
+	todo, err := s.storage.Todo().Update(update)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to update todo")
+	}
+
+	if err = s.recordTransition(current, todo, req.Comment); err != nil {
+		return nil, err
+	}
+	rev := &pb.TodoRevision{Action: pb.RevisionAction_REVISION_TRANSITIONED}
+	if err = s.recordRevision(rev, current, todo); err != nil {
+		return nil, err
+	}
+	if todo, err = s.nextOccurrence(current, todo); err != nil {
+		return nil, err
+	}
+	s.publish(pb.TodoEventType_TODO_UPDATED, todo)
+
+	return todo, nil
+}
+
+// recordTransition adds the status change from before to after to the
+// audit log, the actor is whoever made the update
+func (s *todoService) recordTransition(before, after *pb.TodoModel, comment string) error {
+	_, err := s.storage.Transition().Create(&pb.TodoTransition{
+		Id:         uuid.New().String(),
+		TodoId:     after.Id,
+		From:       before.TaskStatus,
+		To:         after.TaskStatus,
+		ActorId:    after.UpdatedBy,
+		Comment:    comment,
+		OccurredAt: after.UpdatedAt,
+	})
+	if err != nil {
+		return s.handleStorageError(err, "failed to record transition")
+	}
+
+	return nil
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// trashSort is the order of the trash, most recently deleted first
+var trashSort = []*pb.SortField{{Field: "deleted_at", Desc: true}}
+
+// RestoreTodo takes a todo out of the trash with the subtasks deleted
+// together with it
+func (s *todoService) RestoreTodo(ctx context.Context, req *pb.RestoreTodoRequest) (*pb.TodoModel, error) {
+	todo, err := s.storage.Todo().Get(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkOwner(todo.UserId, req.ActorId, "restore a todo"); err != nil {
+		return nil, err
+	}
+	if todo.DeletedAt == nil {
+		return nil, failedPrecondition(reasonNotTrashed, "todo %s is not in the trash", todo.Id)
+	}
+
+	if todo.ParentId != "" {
+		if _, err = s.liveTodo(todo.ParentId); errors.Is(err, repo.ErrNotFound) {
+			return nil, failedPrecondition(reasonParentTrashed, "parent todo %s is in the trash, restore it first", todo.ParentId)
+		} else if err != nil {
+			return nil, s.handleStorageError(err, "failed to get parent todo")
+		}
+	}
+	if todo.ListId != "" {
+		if _, err = s.liveList(todo.ListId); errors.Is(err, repo.ErrNotFound) {
+			return nil, failedPrecondition(reasonParentTrashed, "list %s is in the trash, restore it first", todo.ListId)
+		} else if err != nil {
+			return nil, s.handleListStorageError(err, "failed to get list")
+		}
+	}
+
+	todo, err = s.restoreTree(todo, req.ActorId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.fillProgress(todo); err != nil {
+		return nil, s.handleStorageError(err, "failed to count subtasks")
+	}
+
+	return todo, nil
+}
+
+// PurgeTodo deletes a todo in the trash for good, with its subtasks,
+// comments and attachments
+func (s *todoService) PurgeTodo(ctx context.Context, req *pb.PurgeTodoRequest) (*emptypb.Empty, error) {
+	todo, err := s.storage.Todo().Get(req.Id)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to get todo")
+	}
+	if err = checkOwner(todo.UserId, req.ActorId, "delete a todo"); err != nil {
+		return nil, err
+	}
+	if todo.DeletedAt == nil {
+		return nil, failedPrecondition(reasonNotTrashed, "todo %s is not in the trash, delete it first", todo.Id)
+	}
+
+	if err = s.purgeTodo(todo); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreTodoList takes a list out of the trash with the todos its delete
+// moved there
+func (s *todoService) RestoreTodoList(ctx context.Context, req *pb.RestoreTodoListRequest) (*pb.TodoList, error) {
+	current, err := s.storage.TodoList().Get(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+	if err = checkOwner(current.UserId, req.ActorId, "restore a list"); err != nil {
+		return nil, err
+	}
+	if current.DeletedAt == nil {
+		return nil, failedPrecondition(reasonNotTrashed, "list %s is not in the trash", current.Id)
+	}
+
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		ListId:  current.Id,
+		Trashed: true,
+	})
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list todos")
+	}
+
+	update := proto.Clone(current).(*pb.TodoList)
+	update.DeletedAt = nil
+	update.UpdatedAt = timestamppb.Now()
+
+	list, err := s.storage.TodoList().Update(update)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to update list")
+	}
+	s.indexList(list)
+
+	for _, todo := range todos {
+		// deleted on its own before the list, it stays in the trash
+		if !sameTime(todo.DeletedAt, current.DeletedAt) {
+			continue
+		}
+		if todo.ParentId != "" {
+			parent, err := s.storage.Todo().Get(todo.ParentId)
+			if err != nil {
+				return nil, s.handleStorageError(err, "failed to get parent todo")
+			}
+			// deleted with the list too, it comes back with its parent
+			if sameTime(parent.DeletedAt, current.DeletedAt) {
+				continue
+			}
+		}
+		if _, err = s.restoreTree(todo, req.ActorId); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
+
+// PurgeTodoList deletes a list in the trash for good, with the todos in the
+// trash which are still in it
+func (s *todoService) PurgeTodoList(ctx context.Context, req *pb.PurgeTodoListRequest) (*emptypb.Empty, error) {
+	list, err := s.storage.TodoList().Get(req.Id)
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to get list")
+	}
+	if err = checkOwner(list.UserId, req.ActorId, "delete a list"); err != nil {
+		return nil, err
+	}
+	if list.DeletedAt == nil {
+		return nil, failedPrecondition(reasonNotTrashed, "list %s is not in the trash, delete it first", list.Id)
+	}
+
+	if err = s.purgeList(list); err != nil {
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// ListTrash lists the deleted todos and lists, most recently deleted first.
+// Subtasks deleted with their parent are listed too.
+func (s *todoService) ListTrash(ctx context.Context, req *pb.ListTrashRequest) (*pb.ListTrashResponse, error) {
+	query := proto.Clone(req).(*pb.ListTrashRequest)
+	query.Limit, query.Cursor, query.WithCount = 0, "", false
+	p, err := s.newPager(query, req.Cursor, 0, req.Limit, trashSort)
+	if err != nil {
+		return nil, err
+	}
+
+	// todos and lists are fetched apart and merged, one more item of each
+	// tells if the merged items go on
+	limit := req.Limit
+	if limit > 0 {
+		limit++
+	}
+
+	var filter *pb.FilterExpr
+	if req.UserId != "" {
+		filter = &pb.FilterExpr{Expr: &pb.FilterExpr_Condition{Condition: &pb.FilterCondition{
+			Field:  "user_id",
+			Op:     pb.FilterCondition_EQ,
+			Values: []*pb.FilterValue{repo.FilterValueOf(req.UserId)},
+		}}}
+	}
+
+	todos, todoCount, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		Filter:    filter,
+		After:     p.after(repo.TodoFilterFields),
+		Sort:      trashSort,
+		Limit:     limit,
+		Trashed:   true,
+		WithCount: req.WithCount,
+	})
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to list todos")
+	}
+	lists, listCount, err := s.storage.TodoList().GetAll(&pb.ListTodoListsRequest{
+		Filter:          filter,
+		After:           p.after(repo.TodoListFilterFields),
+		Sort:            trashSort,
+		Limit:           limit,
+		IncludeArchived: true,
+		Trashed:         true,
+		WithCount:       req.WithCount,
+	})
+	if err != nil {
+		return nil, s.handleListStorageError(err, "failed to list lists")
+	}
+
+	items := make([]*pb.TrashItem, 0, len(todos)+len(lists))
+	for _, todo := range todos {
+		items = append(items, &pb.TrashItem{Item: &pb.TrashItem_Todo{Todo: todo}})
+	}
+	for _, list := range lists {
+		items = append(items, &pb.TrashItem{Item: &pb.TrashItem_List{List: list}})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		a, b := trashedAt(items[i]).AsTime(), trashedAt(items[j]).AsTime()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return trashItemID(items[i]) < trashItemID(items[j])
+	})
+
+	res := &pb.ListTrashResponse{}
+	if req.WithCount {
+		res.Count = todoCount + listCount
+	}
+	if req.Limit > 0 && int64(len(items)) > req.Limit {
+		items = items[:req.Limit]
+		last := items[len(items)-1]
+		res.NextCursor = p.encode([]*pb.FilterValue{repo.FilterValueOf(trashedAt(last))}, trashItemID(last))
+	}
+	res.Items = items
+
+	return res, nil
+}
+
+// RunTrashPurge purges the trash every interval until ctx is done, items
+// stay in the trash for retention
+func (s *todoService) RunTrashPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeTrash(time.Now().Add(-retention))
+			if err != nil {
+				s.log.Error("failed to purge trash", logger.Error(err))
+				continue
+			}
+			if purged > 0 {
+				s.log.Info("trash purged", logger.Int("items", purged))
+			}
+		}
+	}
+}
+
+// PurgeTrash deletes the todos and lists which went to the trash before
+// before for good and returns how many of them there were. Subtasks go with
+// their parents, so deleting a parent also purges its subtasks, which were
+// deleted before or together with it.
+func (s *todoService) PurgeTrash(before time.Time) (int, error) {
+	deletedBefore := &pb.FilterExpr{Expr: &pb.FilterExpr_Condition{Condition: &pb.FilterCondition{
+		Field:  "deleted_at",
+		Op:     pb.FilterCondition_LT,
+		Values: []*pb.FilterValue{repo.FilterValueOf(timestamppb.New(before))},
+	}}}
+
+	lists, _, err := s.storage.TodoList().GetAll(&pb.ListTodoListsRequest{
+		Filter:          deletedBefore,
+		IncludeArchived: true,
+		Trashed:         true,
+	})
+	if err != nil {
+		return 0, s.handleListStorageError(err, "failed to list lists")
+	}
+	for _, list := range lists {
+		if err = s.purgeList(list); err != nil {
+			return 0, err
+		}
+	}
+
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		Filter:  deletedBefore,
+		Trashed: true,
+	})
+	if err != nil {
+		return 0, s.handleStorageError(err, "failed to list todos")
+	}
+
+	purged := len(lists)
+	for _, todo := range todos {
+		// purged with its parent or list already
+		if _, err = s.storage.Todo().Get(todo.Id); errors.Is(err, repo.ErrNotFound) {
+			continue
+		}
+		if err = s.purgeTodo(todo); err != nil {
+			return 0, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// trashTree moves a todo and its subtasks to the trash, all of them get
+// deleted at, so a restore brings back exactly this tree
+func (s *todoService) trashTree(todo *pb.TodoModel, descendants []*pb.TodoModel, at *timestamppb.Timestamp, actorID string) error {
+	for i := len(descendants) - 1; i >= 0; i-- {
+		if _, err := s.setDeletedAt(descendants[i], at, actorID); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.setDeletedAt(todo, at, actorID)
+	return err
+}
+
+// restoreTree takes a todo out of the trash with the subtasks which were
+// deleted at the same time, parents before their subtasks
+func (s *todoService) restoreTree(todo *pb.TodoModel, actorID string) (*pb.TodoModel, error) {
+	deletedAt := todo.DeletedAt
+
+	restored, err := s.setDeletedAt(todo, nil, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := []string{todo.Id}
+	for len(queue) > 0 {
+		subtasks, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+			ParentId: queue[0],
+			Trashed:  true,
+		})
+		if err != nil {
+			return nil, s.handleStorageError(err, "failed to list subtasks")
+		}
+		queue = queue[1:]
+
+		for _, subtask := range subtasks {
+			// deleted on its own before, it stays in the trash
+			if !sameTime(subtask.DeletedAt, deletedAt) {
+				continue
+			}
+			if _, err = s.setDeletedAt(subtask, nil, actorID); err != nil {
+				return nil, err
+			}
+			queue = append(queue, subtask.Id)
+		}
+	}
+
+	return restored, nil
+}
+
+// setDeletedAt moves a todo to the trash, or out of it for a nil at
+func (s *todoService) setDeletedAt(todo *pb.TodoModel, at *timestamppb.Timestamp, actorID string) (*pb.TodoModel, error) {
+	update := proto.Clone(todo).(*pb.TodoModel)
+	update.DeletedAt = at
+	update.Progress = nil
+	update.UpdatedAt = at
+	if at == nil {
+		update.UpdatedAt = timestamppb.Now()
+	}
+	update.UpdatedBy = actorID
+
+	updated, err := s.storage.Todo().Update(update)
+	if err != nil {
+		return nil, s.handleStorageError(err, "failed to update todo")
+	}
+
+	rev := &pb.TodoRevision{Action: pb.RevisionAction_REVISION_DELETED}
+	if at == nil {
+		rev.Action = pb.RevisionAction_REVISION_RESTORED
+	}
+	if err = s.recordRevision(rev, todo, updated); err != nil {
+		return nil, err
+	}
+
+	if at != nil {
+		s.publish(pb.TodoEventType_TODO_DELETED, updated)
+	} else {
+		s.publish(pb.TodoEventType_TODO_CREATED, updated)
+		s.indexComments(updated.Id)
+	}
+
+	return updated, nil
+}
+
+// purgeTodo deletes a todo in the trash with its subtasks for good
+func (s *todoService) purgeTodo(todo *pb.TodoModel) error {
+	descendants, err := s.descendants(todo.Id, true)
+	if err != nil {
+		return s.handleStorageError(err, "failed to list subtasks")
+	}
+
+	return s.deleteTree(todo, descendants)
+}
+
+// purgeList deletes a list in the trash for good. The todos still in it are
+// all in the trash, they were deleted before or together with the list.
+func (s *todoService) purgeList(list *pb.TodoList) error {
+	todos, _, err := s.storage.Todo().GetAll(&pb.ListTodosRequest{
+		ListId:  list.Id,
+		Trashed: true,
+	})
+	if err != nil {
+		return s.handleStorageError(err, "failed to list todos")
+	}
+
+	for _, todo := range todos {
+		// subtasks in the list go with their parent
+		if _, err = s.storage.Todo().Get(todo.Id); errors.Is(err, repo.ErrNotFound) {
+			continue
+		}
+		if err = s.purgeTodo(todo); err != nil {
+			return err
+		}
+	}
+
+	if err = s.storage.TodoList().Delete(list.Id); err != nil {
+		return s.handleListStorageError(err, "failed to delete list")
+	}
+
+	return nil
+}
+
+// liveTodo returns a todo which is not in the trash, repo.ErrNotFound for
+// one which is
+func (s *todoService) liveTodo(id string) (*pb.TodoModel, error) {
+	todo, err := s.storage.Todo().Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if todo.DeletedAt != nil {
+		return nil, repo.ErrNotFound
+	}
+
+	return todo, nil
+}
+
+// liveList is liveTodo for lists
+func (s *todoService) liveList(id string) (*pb.TodoList, error) {
+	list, err := s.storage.TodoList().Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if list.DeletedAt != nil {
+		return nil, repo.ErrNotFound
+	}
+
+	return list, nil
+}
+
+func trashedAt(item *pb.TrashItem) *timestamppb.Timestamp {
+	if todo := item.GetTodo(); todo != nil {
+		return todo.DeletedAt
+	}
+
+	return item.GetList().GetDeletedAt()
+}
+
+func trashItemID(item *pb.TrashItem) string {
+	if todo := item.GetTodo(); todo != nil {
+		return todo.Id
+	}
+
+	return item.GetList().GetId()
+}
+
+func sameTime(a, b *timestamppb.Timestamp) bool {
+	return a.GetSeconds() == b.GetSeconds() && a.GetNanos() == b.GetNanos()
+}
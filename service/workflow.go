@@ -0,0 +1,143 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// defaultWorkspace is the key of the workflow used by workspaces which
+// have none of their own
+const defaultWorkspace = "default"
+
+// Workflow is the graph of allowed status changes, it maps a status to the
+// statuses a todo may move to from it. A status without edges is terminal.
+type Workflow map[pb.TaskStatus][]pb.TaskStatus
+
+// Workflows maps a workspace id to its workflow
+type Workflows map[string]Workflow
+
+// DefaultWorkflow is todo -> in_progress -> done, done todos may be
+// reopened and cancelled is terminal. Todos may also be done right away,
+// so checklist items can be ticked off.
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		pb.TaskStatus_TASK_STATUS_TODO: {
+			pb.TaskStatus_TASK_STATUS_IN_PROGRESS,
+			pb.TaskStatus_TASK_STATUS_DONE,
+			pb.TaskStatus_TASK_STATUS_CANCELLED,
+		},
+		pb.TaskStatus_TASK_STATUS_IN_PROGRESS: {
+			pb.TaskStatus_TASK_STATUS_TODO,
+			pb.TaskStatus_TASK_STATUS_DONE,
+			pb.TaskStatus_TASK_STATUS_CANCELLED,
+		},
+		pb.TaskStatus_TASK_STATUS_DONE: {
+			pb.TaskStatus_TASK_STATUS_REOPENED,
+		},
+		pb.TaskStatus_TASK_STATUS_REOPENED: {
+			pb.TaskStatus_TASK_STATUS_IN_PROGRESS,
+			pb.TaskStatus_TASK_STATUS_DONE,
+			pb.TaskStatus_TASK_STATUS_CANCELLED,
+		},
+		pb.TaskStatus_TASK_STATUS_CANCELLED: nil,
+	}
+}
+
+// LoadWorkflows reads workflows from a json file shaped like
+//
+//	{"default": {"todo": ["in_progress"], "in_progress": ["done"], "done": []}}
+//
+// with one entry per workspace id, statuses are named as in the http api.
+// The "default" entry is used for other workspaces and falls back to
+// DefaultWorkflow. An empty path returns just the default workflow.
+func LoadWorkflows(path string) (Workflows, error) {
+	workflows := Workflows{}
+
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var raw map[string]map[string][]string
+		if err = json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("invalid workflows file %s: %w", path, err)
+		}
+
+		for workspace, graph := range raw {
+			workflow := Workflow{}
+			for from, tos := range graph {
+				fromStatus, err := parseStatusName(from)
+				if err != nil {
+					return nil, fmt.Errorf("workflow %s: %w", workspace, err)
+				}
+				workflow[fromStatus] = nil
+				for _, to := range tos {
+					toStatus, err := parseStatusName(to)
+					if err != nil {
+						return nil, fmt.Errorf("workflow %s: %w", workspace, err)
+					}
+					workflow[fromStatus] = append(workflow[fromStatus], toStatus)
+				}
+			}
+			// statuses which are only reached are terminal
+			for _, tos := range workflow {
+				for _, to := range tos {
+					if !workflow.has(to) {
+						workflow[to] = nil
+					}
+				}
+			}
+			if _, ok := workflow[pb.TaskStatus_TASK_STATUS_TODO]; !ok {
+				return nil, fmt.Errorf("workflow %s: todo status is required", workspace)
+			}
+			workflows[workspace] = workflow
+		}
+	}
+
+	if _, ok := workflows[defaultWorkspace]; !ok {
+		workflows[defaultWorkspace] = DefaultWorkflow()
+	}
+
+	return workflows, nil
+}
+
+// get returns the workflow of a workspace
+func (w Workflows) get(workspaceID string) Workflow {
+	if workflow, ok := w[workspaceID]; ok {
+		return workflow
+	}
+	return w[defaultWorkspace]
+}
+
+// has reports whether status is part of the workflow
+func (w Workflow) has(status pb.TaskStatus) bool {
+	_, ok := w[status]
+	return ok
+}
+
+// allows reports whether a todo may move from one status to another
+func (w Workflow) allows(from, to pb.TaskStatus) bool {
+	for _, status := range w[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func parseStatusName(name string) (pb.TaskStatus, error) {
+	status, ok := pb.TaskStatus_value["TASK_STATUS_"+strings.ToUpper(name)]
+	if !ok || status == int32(pb.TaskStatus_TASK_STATUS_UNSPECIFIED) {
+		return 0, fmt.Errorf("unknown status %q", name)
+	}
+	return pb.TaskStatus(status), nil
+}
+
+func statusName(status pb.TaskStatus) string {
+	return strings.ToLower(strings.TrimPrefix(status.String(), "TASK_STATUS_"))
+}
@@ -0,0 +1,24 @@
+// Package blob stores file contents apart from their metadata. Contents are
+// addressed by their SHA-256, so uploading the same file twice stores it
+// once.
+package blob
+
+import (
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned for keys without content
+var ErrNotFound = errors.New("blob: not found")
+
+// Store keeps contents under the hex encoded SHA-256 of their bytes
+type Store interface {
+	// Put stores everything read from r and returns its key and size.
+	// Content which is already stored is not written again. Nothing is
+	// stored when reading r fails.
+	Put(r io.Reader) (key string, size int64, err error)
+	// Open returns the content stored under key
+	Open(key string) (io.ReadCloser, error)
+	// Delete removes the content stored under key
+	Delete(key string) error
+}
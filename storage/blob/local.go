@@ -0,0 +1,102 @@
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps contents as files in a directory, fanned out into
+// subdirectories by the first bytes of their key
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store in dir, creating it if needed
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *LocalStore) Put(r io.Reader) (string, int64, error) {
+	// the key is only known after reading everything, so the content goes
+	// to a temporary file first and is renamed into place
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "upload-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	path := s.path(key)
+	if _, err = os.Stat(path); err == nil {
+		return key, size, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", 0, err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+
+	return key, size, nil
+}
+
+func (s *LocalStore) Open(key string) (io.ReadCloser, error) {
+	if !validKey(key) {
+		return nil, ErrNotFound
+	}
+
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+
+	return f, err
+}
+
+func (s *LocalStore) Delete(key string) error {
+	if !validKey(key) {
+		return ErrNotFound
+	}
+
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key[2:4], key)
+}
+
+// validKey keeps keys from naming files outside the store
+func validKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
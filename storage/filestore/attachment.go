@@ -0,0 +1,56 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// AttachmentRepo stores attachment metadata in a Store
+type AttachmentRepo struct {
+	store *Store
+}
+
+func (r *AttachmentRepo) Create(attachment *pb.TodoAttachment) (*pb.TodoAttachment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, attachmentCollection, attachment); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.attachments.Create(attachment)
+}
+
+func (r *AttachmentRepo) Get(id string) (*pb.TodoAttachment, error) {
+	return r.store.attachments.Get(id)
+}
+
+func (r *AttachmentRepo) GetAll(todoID string) ([]*pb.TodoAttachment, error) {
+	return r.store.attachments.GetAll(todoID)
+}
+
+func (r *AttachmentRepo) Delete(id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.attachments.Get(id); err != nil {
+		return err
+	}
+
+	if err := s.commitItem(opDeleteItem, attachmentCollection, &pb.TodoAttachment{Id: id}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.attachments.Delete(id)
+}
+
+func (r *AttachmentRepo) UsedBytes(userID string) (int64, error) {
+	return r.store.attachments.UsedBytes(userID)
+}
+
+func (r *AttachmentRepo) References(sha256 string) (int64, error) {
+	return r.store.attachments.References(sha256)
+}
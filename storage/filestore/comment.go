@@ -0,0 +1,92 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// CommentRepo stores todo comments in a Store
+type CommentRepo struct {
+	store *Store
+}
+
+func (r *CommentRepo) Create(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, commentCollection, comment); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.comments.Create(comment)
+}
+
+func (r *CommentRepo) Get(id string) (*pb.TodoComment, error) {
+	return r.store.comments.Get(id)
+}
+
+func (r *CommentRepo) GetAll(req *pb.ListTodoCommentsRequest) ([]*pb.TodoComment, int64, error) {
+	return r.store.comments.GetAll(req)
+}
+
+func (r *CommentRepo) Update(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.comments.Get(comment.Id); err != nil {
+		return nil, err
+	}
+
+	if err := s.commitItem(opPutItem, commentCollection, comment); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.comments.Update(comment)
+}
+
+func (r *CommentRepo) Delete(id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.comments.Get(id); err != nil {
+		return err
+	}
+
+	if err := s.commitItem(opDeleteItem, commentCollection, &pb.TodoComment{Id: id}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.comments.Delete(id)
+}
+
+func (r *CommentRepo) DeleteAll(todoID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	comments, _, err := s.comments.GetAll(&pb.ListTodoCommentsRequest{TodoId: todoID})
+	if err != nil {
+		return err
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+	defer s.compactIfNeeded()
+
+	// one record per comment, so replaying the log needs no query
+	for _, comment := range comments {
+		if err = s.commitItem(opDeleteItem, commentCollection, &pb.TodoComment{Id: comment.Id}); err != nil {
+			return err
+		}
+		if err = s.comments.Delete(comment.Id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
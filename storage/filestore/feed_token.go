@@ -0,0 +1,48 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// FeedTokenRepo stores feed tokens in a Store
+type FeedTokenRepo struct {
+	store *Store
+}
+
+func (r *FeedTokenRepo) Put(token *pb.FeedToken) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, feedTokenCollection, token); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.feedTokens.Put(token)
+}
+
+func (r *FeedTokenRepo) Get(userID string) (*pb.FeedToken, error) {
+	return r.store.feedTokens.Get(userID)
+}
+
+func (r *FeedTokenRepo) GetByHash(tokenHash string) (*pb.FeedToken, error) {
+	return r.store.feedTokens.GetByHash(tokenHash)
+}
+
+func (r *FeedTokenRepo) Delete(userID string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.feedTokens.Get(userID); err != nil {
+		return err
+	}
+
+	if err := s.commitItem(opDeleteItem, feedTokenCollection, &pb.FeedToken{UserId: userID}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.feedTokens.Delete(userID)
+}
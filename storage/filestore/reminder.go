@@ -0,0 +1,52 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// ReminderRepo stores pending reminders in a Store
+type ReminderRepo struct {
+	store *Store
+}
+
+func (r *ReminderRepo) Put(reminder *pb.TodoReminder) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, reminderCollection, reminder); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.reminders.Put(reminder)
+}
+
+func (r *ReminderRepo) Get(id string) (*pb.TodoReminder, error) {
+	return r.store.reminders.Get(id)
+}
+
+func (r *ReminderRepo) GetAll(todoID string) ([]*pb.TodoReminder, error) {
+	return r.store.reminders.GetAll(todoID)
+}
+
+func (r *ReminderRepo) GetPending() ([]*pb.TodoReminder, error) {
+	return r.store.reminders.GetPending()
+}
+
+func (r *ReminderRepo) Delete(id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.reminders.Get(id); err != nil {
+		return err
+	}
+
+	if err := s.commitItem(opDeleteItem, reminderCollection, &pb.TodoReminder{Id: id}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.reminders.Delete(id)
+}
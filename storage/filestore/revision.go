@@ -0,0 +1,28 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// RevisionRepo stores todo revisions in a Store. Revisions are numbered on
+// apply, replaying them in log order numbers them the same way again.
+type RevisionRepo struct {
+	store *Store
+}
+
+func (r *RevisionRepo) Create(rev *pb.TodoRevision) (*pb.TodoRevision, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, revisionCollection, rev); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.revisions.Create(rev)
+}
+
+func (r *RevisionRepo) GetAll(todoID string) ([]*pb.TodoRevision, error) {
+	return r.store.revisions.GetAll(todoID)
+}
@@ -0,0 +1,100 @@
+package filestore
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeSnapshot atomically replaces the snapshot at path. The file starts
+// with an opSnapshot record carrying the last sequence number it covers,
+// followed by the records which rebuild the indexes, todos in insertion
+// order first.
+func writeSnapshot(path string, seq uint64, records []record) error {
+	tmp := path + ".tmp"
+
+	file, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	if _, err = writer.Write(record{seq: seq, op: opSnapshot}.encode()); err != nil {
+		file.Close()
+		return err
+	}
+	for _, rec := range records {
+		rec.seq = seq
+		if _, err = writer.Write(rec.encode()); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	if err = writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// loadSnapshot calls apply for every record stored in the snapshot and returns
+// the sequence number the snapshot covers. A missing snapshot is not an error.
+func loadSnapshot(path string, apply func(record) error) (uint64, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+
+	header, _, err := readRecord(reader)
+	if err != nil || header.op != opSnapshot {
+		// snapshots are renamed into place only when complete, so
+		// unlike the log a damaged snapshot cannot be repaired
+		return 0, errors.New("filestore: snapshot is damaged: " + path)
+	}
+
+	for {
+		rec, _, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, errors.New("filestore: snapshot is damaged: " + path)
+		}
+		if err = apply(rec); err != nil {
+			return 0, err
+		}
+	}
+
+	return header.seq, nil
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
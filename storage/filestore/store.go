@@ -0,0 +1,458 @@
+package filestore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/storage/memory"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	walFileName      = "todo.wal"
+	snapshotFileName = "todo.snapshot"
+)
+
+// collections stored next to todos, see opPutItem
+const (
+	transitionCollection = "transition"
+	listCollection       = "list"
+	commentCollection    = "comment"
+	attachmentCollection = "attachment"
+	revisionCollection   = "revision"
+	reminderCollection   = "reminder"
+	feedTokenCollection  = "feed_token"
+)
+
+// Options ...
+type Options struct {
+	// SyncOnCommit fsyncs the log before a write is acknowledged. Turning
+	// it off trades durability of the last few writes for throughput.
+	SyncOnCommit bool
+	// SnapshotInterval is how often the log is folded into a snapshot.
+	// Zero disables periodic snapshots.
+	SnapshotInterval time.Duration
+	// CompactThreshold is the log size in bytes after which a snapshot is
+	// taken right away. Zero disables size based compaction.
+	CompactThreshold int64
+}
+
+// Store is a durable store kept in a directory on disk. Every write is
+// appended to a write-ahead log before it is applied to in-memory indexes
+// that serve all reads. The log is periodically folded into a snapshot and
+// truncated, and on startup the snapshot is loaded and the remaining log
+// replayed.
+type Store struct {
+	// mu serializes writers, so log order always matches apply order
+	mu   sync.Mutex
+	dir  string
+	opts Options
+	log  logger.Logger
+	wal  *wal
+	seq  uint64
+
+	todos       *memory.TodoRepo
+	transitions *memory.TransitionRepo
+	lists       *memory.TodoListRepo
+	comments    *memory.CommentRepo
+	attachments *memory.AttachmentRepo
+	revisions   *memory.RevisionRepo
+	reminders   *memory.ReminderRepo
+	feedTokens  *memory.FeedTokenRepo
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Open opens the store in dir, creating it if needed, and recovers its
+// state from the snapshot and the write-ahead log.
+func Open(dir string, opts Options, log logger.Logger) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:         dir,
+		opts:        opts,
+		log:         log,
+		todos:       memory.NewTodoRepo(),
+		transitions: memory.NewTransitionRepo(),
+		lists:       memory.NewTodoListRepo(),
+		comments:    memory.NewCommentRepo(),
+		attachments: memory.NewAttachmentRepo(),
+		revisions:   memory.NewRevisionRepo(),
+		reminders:   memory.NewReminderRepo(),
+		feedTokens:  memory.NewFeedTokenRepo(),
+		done:        make(chan struct{}),
+	}
+
+	seq, err := loadSnapshot(s.path(snapshotFileName), s.apply)
+	if err != nil {
+		return nil, err
+	}
+	s.seq = seq
+
+	s.wal, err = openWAL(s.path(walFileName), opts.SyncOnCommit, func(rec record) error {
+		// records up to the snapshot sequence are already part of it, they
+		// are left over when we crashed between a snapshot and log truncation
+		if rec.seq <= seq {
+			return nil
+		}
+		s.seq = rec.seq
+		return s.apply(rec)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SnapshotInterval > 0 {
+		s.wg.Add(1)
+		go s.snapshotLoop()
+	}
+
+	return s, nil
+}
+
+// Todo ...
+func (s *Store) Todo() repo.TodoStorageI {
+	return &TodoRepo{store: s}
+}
+
+// Transition ...
+func (s *Store) Transition() repo.TransitionStorageI {
+	return &TransitionRepo{store: s}
+}
+
+// TodoList ...
+func (s *Store) TodoList() repo.TodoListStorageI {
+	return &TodoListRepo{store: s}
+}
+
+// Comment ...
+func (s *Store) Comment() repo.CommentStorageI {
+	return &CommentRepo{store: s}
+}
+
+// Attachment ...
+func (s *Store) Attachment() repo.AttachmentStorageI {
+	return &AttachmentRepo{store: s}
+}
+
+// Revision ...
+func (s *Store) Revision() repo.RevisionStorageI {
+	return &RevisionRepo{store: s}
+}
+
+// Reminder ...
+func (s *Store) Reminder() repo.ReminderStorageI {
+	return &ReminderRepo{store: s}
+}
+
+// FeedToken ...
+func (s *Store) FeedToken() repo.FeedTokenStorageI {
+	return &FeedTokenRepo{store: s}
+}
+
+// Snapshot folds the write-ahead log into a new snapshot and truncates it
+func (s *Store) Snapshot() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.snapshot()
+}
+
+// Close stops background snapshots, writes a final snapshot, so the next
+// start does not need to replay the log, and closes the files.
+func (s *Store) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.snapshot(); err != nil {
+		s.wal.close()
+		return err
+	}
+
+	return s.wal.close()
+}
+
+// commit appends a change to the log. It must be called with mu held.
+func (s *Store) commit(op byte, data []byte) error {
+	if err := s.wal.append(record{seq: s.seq + 1, op: op, data: data}); err != nil {
+		return fmt.Errorf("filestore: failed to append to log: %w", err)
+	}
+	s.seq++
+
+	return nil
+}
+
+// commitItem appends a change of an item of a collection to the log. It
+// must be called with mu held.
+func (s *Store) commitItem(op byte, collection string, msg proto.Message) error {
+	data, err := encodeItem(collection, msg)
+	if err != nil {
+		return err
+	}
+
+	return s.commit(op, data)
+}
+
+// compactIfNeeded takes a snapshot once the log outgrows CompactThreshold.
+// It must be called with mu held, after the committed change was applied.
+func (s *Store) compactIfNeeded() {
+	if s.opts.CompactThreshold <= 0 || s.wal.size < s.opts.CompactThreshold {
+		return
+	}
+
+	if err := s.snapshot(); err != nil {
+		// the change itself is safely in the log, compaction is retried
+		// on the next write or tick
+		s.log.Error("filestore: failed to compact log", logger.Error(err))
+	}
+}
+
+// snapshot must be called with mu held
+func (s *Store) snapshot() error {
+	if s.wal.size == 0 {
+		return nil
+	}
+
+	var records []record
+	for _, todo := range s.todos.All() {
+		data, err := proto.Marshal(todo)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPut, data: data})
+	}
+	for _, t := range s.transitions.All() {
+		data, err := encodeItem(transitionCollection, t)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, list := range s.lists.All() {
+		data, err := encodeItem(listCollection, list)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, comment := range s.comments.All() {
+		data, err := encodeItem(commentCollection, comment)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, attachment := range s.attachments.All() {
+		data, err := encodeItem(attachmentCollection, attachment)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, rev := range s.revisions.All() {
+		data, err := encodeItem(revisionCollection, rev)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, reminder := range s.reminders.All() {
+		data, err := encodeItem(reminderCollection, reminder)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+	for _, token := range s.feedTokens.All() {
+		data, err := encodeItem(feedTokenCollection, token)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{op: opPutItem, data: data})
+	}
+
+	if err := writeSnapshot(s.path(snapshotFileName), s.seq, records); err != nil {
+		return err
+	}
+
+	return s.wal.reset()
+}
+
+func (s *Store) snapshotLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.opts.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.Snapshot(); err != nil {
+				s.log.Error("filestore: failed to take snapshot", logger.Error(err))
+			}
+		}
+	}
+}
+
+// apply replays a record from the snapshot or the log into the indexes
+func (s *Store) apply(rec record) error {
+	switch rec.op {
+	case opPut, opDelete:
+		return s.applyTodo(rec)
+	case opPutItem, opDeleteItem:
+		collection, data, err := decodeItem(rec.data)
+		if err != nil {
+			return err
+		}
+		return s.applyItem(rec.op, collection, data)
+	default:
+		return fmt.Errorf("filestore: unknown record op %d", rec.op)
+	}
+}
+
+func (s *Store) applyTodo(rec record) error {
+	var todo pb.TodoModel
+	if err := proto.Unmarshal(rec.data, &todo); err != nil {
+		return err
+	}
+
+	if rec.op == opDelete {
+		if err := s.todos.Delete(todo.Id); err != nil && err != repo.ErrNotFound {
+			return err
+		}
+		return nil
+	}
+
+	// records hold todos as stored, versions included, so they replace
+	// the todo instead of updating it
+	_, err := s.todos.Create(&todo)
+	return err
+}
+
+func (s *Store) applyItem(op byte, collection string, data []byte) error {
+	switch collection {
+	case transitionCollection:
+		var t pb.TodoTransition
+		if err := proto.Unmarshal(data, &t); err != nil {
+			return err
+		}
+		_, err := s.transitions.Create(&t)
+		return err
+	case listCollection:
+		var list pb.TodoList
+		if err := proto.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		if op == opDeleteItem {
+			if err := s.lists.Delete(list.Id); err != nil && err != repo.ErrNotFound {
+				return err
+			}
+			return nil
+		}
+		// Create keeps the position of an existing list
+		_, err := s.lists.Create(&list)
+		return err
+	case commentCollection:
+		var comment pb.TodoComment
+		if err := proto.Unmarshal(data, &comment); err != nil {
+			return err
+		}
+		if op == opDeleteItem {
+			if err := s.comments.Delete(comment.Id); err != nil && err != repo.ErrNotFound {
+				return err
+			}
+			return nil
+		}
+		// Create keeps the position of an existing comment
+		_, err := s.comments.Create(&comment)
+		return err
+	case attachmentCollection:
+		var attachment pb.TodoAttachment
+		if err := proto.Unmarshal(data, &attachment); err != nil {
+			return err
+		}
+		if op == opDeleteItem {
+			if err := s.attachments.Delete(attachment.Id); err != nil && err != repo.ErrNotFound {
+				return err
+			}
+			return nil
+		}
+		_, err := s.attachments.Create(&attachment)
+		return err
+	case revisionCollection:
+		var rev pb.TodoRevision
+		if err := proto.Unmarshal(data, &rev); err != nil {
+			return err
+		}
+		_, err := s.revisions.Create(&rev)
+		return err
+	case reminderCollection:
+		var reminder pb.TodoReminder
+		if err := proto.Unmarshal(data, &reminder); err != nil {
+			return err
+		}
+		if op == opDeleteItem {
+			if err := s.reminders.Delete(reminder.Id); err != nil && err != repo.ErrNotFound {
+				return err
+			}
+			return nil
+		}
+		return s.reminders.Put(&reminder)
+	case feedTokenCollection:
+		var token pb.FeedToken
+		if err := proto.Unmarshal(data, &token); err != nil {
+			return err
+		}
+		if op == opDeleteItem {
+			if err := s.feedTokens.Delete(token.UserId); err != nil && err != repo.ErrNotFound {
+				return err
+			}
+			return nil
+		}
+		return s.feedTokens.Put(&token)
+	default:
+		return fmt.Errorf("filestore: unknown collection %q", collection)
+	}
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// encodeItem prefixes the marshalled item with the length and name of its
+// collection
+func encodeItem(collection string, msg proto.Message) ([]byte, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 0, 1+len(collection)+len(data))
+	buf = append(buf, byte(len(collection)))
+	buf = append(buf, collection...)
+
+	return append(buf, data...), nil
+}
+
+func decodeItem(buf []byte) (collection string, data []byte, err error) {
+	if len(buf) == 0 || len(buf) < 1+int(buf[0]) {
+		return "", nil, errCorrupt
+	}
+	n := 1 + int(buf[0])
+
+	return string(buf[1:n]), buf[n:], nil
+}
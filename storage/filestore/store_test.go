@@ -0,0 +1,341 @@
+package filestore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+)
+
+func openStore(t *testing.T, dir string, opts Options) *Store {
+	t.Helper()
+
+	s, err := Open(dir, opts, logger.New(logger.LevelError, "test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+// crash stops s like a killed process would, without the final snapshot
+// Close takes
+func crash(t *testing.T, s *Store) {
+	t.Helper()
+
+	close(s.done)
+	s.wg.Wait()
+	if err := s.wal.file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func createTodos(t *testing.T, s *Store, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		if _, err := s.Todo().Create(&pb.TodoModel{Id: id, TaskName: "todo " + id, Version: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func updateTodo(t *testing.T, s *Store, id string) {
+	t.Helper()
+
+	todo, err := s.Todo().Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo.TaskName += " updated"
+	if _, err = s.Todo().Update(todo); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// versions returns the versions of the stored todos by id
+func versions(t *testing.T, s *Store) map[string]int64 {
+	t.Helper()
+
+	todos, _, err := s.Todo().GetAll(&pb.ListTodosRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := make(map[string]int64, len(todos))
+	for _, todo := range todos {
+		res[todo.Id] = todo.Version
+	}
+
+	return res
+}
+
+func expectVersions(t *testing.T, s *Store, want map[string]int64) {
+	t.Helper()
+
+	if got := versions(t, s); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got todos %v, want %v", got, want)
+	}
+}
+
+// recordEnds returns the offsets the records of the log at path end at
+func recordEnds(t *testing.T, path string) []int64 {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var (
+		ends   []int64
+		offset int64
+		reader = bufio.NewReader(file)
+	)
+	for {
+		_, n, err := readRecord(reader)
+		if err == io.EOF {
+			return ends
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		offset += n
+		ends = append(ends, offset)
+	}
+}
+
+func TestRecoverFromLog(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openStore(t, dir, Options{})
+	createTodos(t, s, "a", "b", "c")
+	updateTodo(t, s, "a")
+	updateTodo(t, s, "a")
+	if err := s.Todo().Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	list, err := s.TodoList().Create(&pb.TodoList{Id: "inbox", Name: "Inbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	crash(t, s)
+
+	s = openStore(t, dir, Options{})
+	defer s.Close()
+	expectVersions(t, s, map[string]int64{"a": 3, "c": 1})
+	if got, err := s.TodoList().Get(list.Id); err != nil || got.Name != "Inbox" {
+		t.Errorf("list = %v, %v, want Inbox", got, err)
+	}
+
+	// the sequence goes on where the log ended
+	if s.seq != 7 {
+		t.Errorf("seq = %d, want 7", s.seq)
+	}
+}
+
+func TestRecoverDamagedLog(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage changes the log with records ending at ends
+		damage func(t *testing.T, path string, ends []int64)
+		want   map[string]int64
+	}{
+		{
+			name: "torn header",
+			damage: func(t *testing.T, path string, ends []int64) {
+				truncate(t, path, ends[1]+headerSize/2)
+			},
+			want: map[string]int64{"a": 1, "b": 1},
+		},
+		{
+			name: "torn body",
+			damage: func(t *testing.T, path string, ends []int64) {
+				truncate(t, path, ends[2]-1)
+			},
+			want: map[string]int64{"a": 1, "b": 1},
+		},
+		{
+			name: "crc mismatch",
+			damage: func(t *testing.T, path string, ends []int64) {
+				// the last byte of the body of the second record, the
+				// records after it are dropped as well
+				flipByte(t, path, ends[1]-1)
+			},
+			want: map[string]int64{"a": 1},
+		},
+		{
+			name: "garbage length",
+			damage: func(t *testing.T, path string, ends []int64) {
+				writeAt(t, path, ends[1], []byte{0xff, 0xff, 0xff, 0xff})
+			},
+			want: map[string]int64{"a": 1, "b": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			s := openStore(t, dir, Options{})
+			createTodos(t, s, "a", "b", "c")
+			crash(t, s)
+
+			path := s.path(walFileName)
+			tt.damage(t, path, recordEnds(t, path))
+
+			s = openStore(t, dir, Options{})
+			expectVersions(t, s, tt.want)
+
+			// the damaged tail is cut off, so new records follow the
+			// recovered ones
+			createTodos(t, s, "d")
+			crash(t, s)
+
+			s = openStore(t, dir, Options{})
+			defer s.Close()
+			tt.want["d"] = 1
+			expectVersions(t, s, tt.want)
+		})
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	dir := t.TempDir()
+
+	// every write outgrows the threshold
+	s := openStore(t, dir, Options{CompactThreshold: 1})
+	createTodos(t, s, "a", "b")
+	updateTodo(t, s, "b")
+	if s.wal.size != 0 {
+		t.Errorf("log size = %d after compaction, want 0", s.wal.size)
+	}
+	crash(t, s)
+
+	s = openStore(t, dir, Options{})
+	expectVersions(t, s, map[string]int64{"a": 1, "b": 2})
+
+	// changes after the snapshot come from the log
+	if err := s.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	createTodos(t, s, "c")
+	if err := s.Todo().Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	updateTodo(t, s, "b")
+	crash(t, s)
+
+	s = openStore(t, dir, Options{})
+	defer s.Close()
+	expectVersions(t, s, map[string]int64{"b": 3, "c": 1})
+}
+
+func TestCrashBetweenSnapshotAndTruncation(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openStore(t, dir, Options{})
+	createTodos(t, s, "a", "b")
+	updateTodo(t, s, "a")
+	walPath := s.path(walFileName)
+	log, err := ioutil.ReadFile(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	crash(t, s)
+
+	// the snapshot was written but the log not truncated yet
+	if err = ioutil.WriteFile(walPath, log, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s = openStore(t, dir, Options{})
+	expectVersions(t, s, map[string]int64{"a": 2, "b": 1})
+	if s.seq != 3 {
+		t.Fatalf("seq = %d, want the 3 of the snapshot", s.seq)
+	}
+
+	// records after the snapshot are replayed, records it covers are not
+	if err = s.Todo().Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	crash(t, s)
+
+	s = openStore(t, dir, Options{})
+	defer s.Close()
+	expectVersions(t, s, map[string]int64{"a": 2})
+}
+
+func TestDamagedSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	s := openStore(t, dir, Options{})
+	createTodos(t, s, "a")
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	flipByte(t, s.path(snapshotFileName), headerSize+bodyPrefixSize+1)
+
+	if _, err := Open(dir, Options{}, logger.New(logger.LevelError, "test")); err == nil {
+		t.Fatal("opened a store with a damaged snapshot")
+	}
+}
+
+func truncate(t *testing.T, path string, size int64) {
+	t.Helper()
+
+	if err := os.Truncate(path, size); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeAt(t *testing.T, path string, offset int64, b []byte) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteAt(b, offset); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func flipByte(t *testing.T, path string, offset int64) {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[offset] ^= 0xff
+	writeAt(t, path, offset, data[offset:offset+1])
+}
+
+func TestRecordEncoding(t *testing.T) {
+	rec := record{seq: 42, op: opPutItem, data: []byte("payload")}
+	buf := rec.encode()
+
+	if size := binary.BigEndian.Uint32(buf); int(size) != bodyPrefixSize+len(rec.data) {
+		t.Errorf("length = %d, want %d", size, bodyPrefixSize+len(rec.data))
+	}
+
+	got, n, err := readRecord(bufio.NewReader(bytes.NewReader(buf)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(buf)) || !reflect.DeepEqual(got, rec) {
+		t.Errorf("read %+v of %d bytes, want %+v of %d", got, n, rec, len(buf))
+	}
+}
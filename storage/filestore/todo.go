@@ -0,0 +1,88 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// TodoRepo stores todos in a Store. Todos predate collections, so their
+// records keep the original opPut and opDelete framing.
+type TodoRepo struct {
+	store *Store
+}
+
+func (r *TodoRepo) Create(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := r.commit(opPut, todo); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.todos.Create(todo)
+}
+
+func (r *TodoRepo) Get(id string) (*pb.TodoModel, error) {
+	return r.store.todos.Get(id)
+}
+
+func (r *TodoRepo) GetAll(req *pb.ListTodosRequest) ([]*pb.TodoModel, int64, error) {
+	return r.store.todos.GetAll(req)
+}
+
+func (r *TodoRepo) SubtaskProgress(parentIDs []string) (map[string]*pb.SubtaskProgress, error) {
+	return r.store.todos.SubtaskProgress(parentIDs)
+}
+
+func (r *TodoRepo) Update(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := s.todos.Get(todo.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != todo.Version {
+		return nil, repo.ErrVersionConflict
+	}
+
+	// the record holds the todo as stored, with the next version
+	updated := proto.Clone(todo).(*pb.TodoModel)
+	updated.Version++
+	if err := r.commit(opPut, updated); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.todos.Update(todo)
+}
+
+func (r *TodoRepo) Delete(id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.todos.Get(id); err != nil {
+		return err
+	}
+
+	if err := r.commit(opDelete, &pb.TodoModel{Id: id}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.todos.Delete(id)
+}
+
+func (r *TodoRepo) commit(op byte, todo *pb.TodoModel) error {
+	data, err := proto.Marshal(todo)
+	if err != nil {
+		return err
+	}
+
+	return r.store.commit(op, data)
+}
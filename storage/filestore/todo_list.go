@@ -0,0 +1,69 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// TodoListRepo stores todo lists in a Store
+type TodoListRepo struct {
+	store *Store
+}
+
+func (r *TodoListRepo) Create(list *pb.TodoList) (*pb.TodoList, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, listCollection, list); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.lists.Create(list)
+}
+
+func (r *TodoListRepo) Get(id string) (*pb.TodoList, error) {
+	return r.store.lists.Get(id)
+}
+
+func (r *TodoListRepo) GetInbox(userID string) (*pb.TodoList, error) {
+	return r.store.lists.GetInbox(userID)
+}
+
+func (r *TodoListRepo) GetAll(req *pb.ListTodoListsRequest) ([]*pb.TodoList, int64, error) {
+	return r.store.lists.GetAll(req)
+}
+
+func (r *TodoListRepo) Update(list *pb.TodoList) (*pb.TodoList, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lists.Get(list.Id); err != nil {
+		return nil, err
+	}
+
+	if err := s.commitItem(opPutItem, listCollection, list); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.lists.Update(list)
+}
+
+func (r *TodoListRepo) Delete(id string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.lists.Get(id); err != nil {
+		return err
+	}
+
+	if err := s.commitItem(opDeleteItem, listCollection, &pb.TodoList{Id: id}); err != nil {
+		return err
+	}
+	defer s.compactIfNeeded()
+
+	return s.lists.Delete(id)
+}
@@ -0,0 +1,27 @@
+package filestore
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// TransitionRepo stores todo transitions in a Store
+type TransitionRepo struct {
+	store *Store
+}
+
+func (r *TransitionRepo) Create(t *pb.TodoTransition) (*pb.TodoTransition, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.commitItem(opPutItem, transitionCollection, t); err != nil {
+		return nil, err
+	}
+	defer s.compactIfNeeded()
+
+	return s.transitions.Create(t)
+}
+
+func (r *TransitionRepo) GetAll(todoID string) ([]*pb.TodoTransition, error) {
+	return r.store.transitions.GetAll(todoID)
+}
@@ -0,0 +1,183 @@
+package filestore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+const (
+	opPut      byte = 1
+	opDelete   byte = 2
+	opSnapshot byte = 3
+	// opPutItem stores an item of a named collection, see encodeItem
+	opPutItem byte = 4
+	// opDeleteItem removes an item of a named collection, the encoded
+	// item only needs its id
+	opDeleteItem byte = 5
+
+	// headerSize is length(4) + crc(4)
+	headerSize = 8
+	// bodyPrefixSize is seq(8) + op(1)
+	bodyPrefixSize = 9
+	// maxRecordSize protects recovery from allocating garbage lengths
+	maxRecordSize = 64 << 20
+)
+
+var (
+	errCorrupt = errors.New("filestore: corrupt record")
+	crcTable   = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// record is a single entry of the write-ahead log or the snapshot file.
+// On disk it is framed as length | crc32c | seq | op | data, where length
+// and crc cover everything after the header.
+type record struct {
+	seq  uint64
+	op   byte
+	data []byte
+}
+
+func (r record) encode() []byte {
+	body := bodyPrefixSize + len(r.data)
+	buf := make([]byte, headerSize+body)
+
+	binary.BigEndian.PutUint64(buf[headerSize:], r.seq)
+	buf[headerSize+8] = r.op
+	copy(buf[headerSize+bodyPrefixSize:], r.data)
+
+	binary.BigEndian.PutUint32(buf[0:], uint32(body))
+	binary.BigEndian.PutUint32(buf[4:], crc32.Checksum(buf[headerSize:], crcTable))
+
+	return buf
+}
+
+// readRecord reads the next record. It returns io.EOF on a clean end of
+// input and errCorrupt when the record is torn or fails its checksum.
+func readRecord(r *bufio.Reader) (record, int64, error) {
+	var header [headerSize]byte
+
+	n, err := io.ReadFull(r, header[:])
+	if err == io.EOF {
+		return record{}, 0, io.EOF
+	}
+	if err != nil {
+		return record{}, int64(n), errCorrupt
+	}
+
+	size := binary.BigEndian.Uint32(header[0:])
+	if size < bodyPrefixSize || size > maxRecordSize {
+		return record{}, int64(n), errCorrupt
+	}
+
+	body := make([]byte, size)
+	m, err := io.ReadFull(r, body)
+	if err != nil {
+		return record{}, int64(n + m), errCorrupt
+	}
+	if crc32.Checksum(body, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return record{}, int64(n + m), errCorrupt
+	}
+
+	return record{
+		seq:  binary.BigEndian.Uint64(body),
+		op:   body[8],
+		data: body[bodyPrefixSize:],
+	}, int64(n + m), nil
+}
+
+// wal is an append-only log file
+type wal struct {
+	file *os.File
+	size int64
+	sync bool
+}
+
+// openWAL opens the log at path and calls apply for every valid record.
+// A torn or corrupt tail, left by a crash in the middle of a write, is
+// truncated away so that new records are appended after the last good one.
+func openWAL(path string, sync bool, apply func(record) error) (*wal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		reader = bufio.NewReader(file)
+		offset int64
+	)
+	for {
+		rec, n, err := readRecord(reader)
+		if err == io.EOF {
+			break
+		}
+		if err == errCorrupt {
+			if err := file.Truncate(offset); err != nil {
+				file.Close()
+				return nil, err
+			}
+			break
+		}
+		if err := apply(rec); err != nil {
+			file.Close()
+			return nil, err
+		}
+		offset += n
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &wal{
+		file: file,
+		size: offset,
+		sync: sync,
+	}, nil
+}
+
+func (w *wal) append(rec record) error {
+	buf := rec.encode()
+
+	n, err := w.file.Write(buf)
+	if err != nil {
+		// drop whatever part of the record made it to the file, so that
+		// the log does not end with a torn record while we keep running
+		w.file.Truncate(w.size)
+		w.file.Seek(w.size, io.SeekStart)
+		return err
+	}
+	w.size += int64(n)
+
+	if w.sync {
+		return w.file.Sync()
+	}
+
+	return nil
+}
+
+// reset empties the log after its records were folded into a snapshot
+func (w *wal) reset() error {
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.size = 0
+
+	return w.file.Sync()
+}
+
+func (w *wal) close() error {
+	if err := w.file.Sync(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	return w.file.Close()
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// AttachmentRepo keeps attachment metadata in process memory
+type AttachmentRepo struct {
+	mu          sync.RWMutex
+	attachments map[string]*pb.TodoAttachment
+	// order keeps ids in insertion order, which is upload order
+	order []string
+}
+
+// NewAttachmentRepo ...
+func NewAttachmentRepo() *AttachmentRepo {
+	return &AttachmentRepo{
+		attachments: make(map[string]*pb.TodoAttachment),
+	}
+}
+
+func (r *AttachmentRepo) Create(attachment *pb.TodoAttachment) (*pb.TodoAttachment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.attachments[attachment.Id]; !ok {
+		r.order = append(r.order, attachment.Id)
+	}
+	r.attachments[attachment.Id] = proto.Clone(attachment).(*pb.TodoAttachment)
+
+	return proto.Clone(attachment).(*pb.TodoAttachment), nil
+}
+
+func (r *AttachmentRepo) Get(id string) (*pb.TodoAttachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	attachment, ok := r.attachments[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(attachment).(*pb.TodoAttachment), nil
+}
+
+func (r *AttachmentRepo) GetAll(todoID string) ([]*pb.TodoAttachment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var res []*pb.TodoAttachment
+	for _, id := range r.order {
+		if attachment := r.attachments[id]; attachment.TodoId == todoID {
+			res = append(res, proto.Clone(attachment).(*pb.TodoAttachment))
+		}
+	}
+
+	return res, nil
+}
+
+func (r *AttachmentRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.attachments[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.attachments, id)
+
+	for i, v := range r.order {
+		if v == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (r *AttachmentRepo) UsedBytes(userID string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var used int64
+	for _, attachment := range r.attachments {
+		if attachment.UploadedBy == userID {
+			used += attachment.Size
+		}
+	}
+
+	return used, nil
+}
+
+func (r *AttachmentRepo) References(sha256 string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var refs int64
+	for _, attachment := range r.attachments {
+		if attachment.Sha256 == sha256 {
+			refs++
+		}
+	}
+
+	return refs, nil
+}
+
+// All returns every stored attachment in upload order, see TodoRepo.All
+func (r *AttachmentRepo) All() []*pb.TodoAttachment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoAttachment, 0, len(r.order))
+	for _, id := range r.order {
+		res = append(res, proto.Clone(r.attachments[id]).(*pb.TodoAttachment))
+	}
+
+	return res
+}
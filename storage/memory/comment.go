@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// CommentRepo keeps todo comments in process memory
+type CommentRepo struct {
+	mu       sync.RWMutex
+	comments map[string]*pb.TodoComment
+	// order keeps ids in insertion order, which is creation order
+	order []string
+}
+
+// NewCommentRepo ...
+func NewCommentRepo() *CommentRepo {
+	return &CommentRepo{
+		comments: make(map[string]*pb.TodoComment),
+	}
+}
+
+func (r *CommentRepo) Create(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.comments[comment.Id]; !ok {
+		r.order = append(r.order, comment.Id)
+	}
+	r.comments[comment.Id] = proto.Clone(comment).(*pb.TodoComment)
+
+	return proto.Clone(comment).(*pb.TodoComment), nil
+}
+
+func (r *CommentRepo) Get(id string) (*pb.TodoComment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comment, ok := r.comments[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(comment).(*pb.TodoComment), nil
+}
+
+func (r *CommentRepo) GetAll(req *pb.ListTodoCommentsRequest) ([]*pb.TodoComment, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	after, err := compileFilter(req.After, repo.CommentFilterFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var comments []*pb.TodoComment
+	for _, id := range r.order {
+		comment := r.comments[id]
+		if req.TodoId != "" && comment.TodoId != req.TodoId {
+			continue
+		}
+		if req.ParentId != "" && comment.ParentId != req.ParentId {
+			continue
+		}
+		if req.Mention != "" && !containsString(comment.Mentions, req.Mention) {
+			continue
+		}
+		comments = append(comments, comment)
+	}
+
+	sortComments(comments)
+
+	count := int64(len(comments))
+	if after != nil {
+		rest := comments[:0]
+		for _, comment := range comments {
+			if after(commentField(comment)) {
+				rest = append(rest, comment)
+			}
+		}
+		comments = rest
+	}
+	start, end := pageBounds(int64(len(comments)), req.Page, req.Limit)
+
+	res := make([]*pb.TodoComment, 0, end-start)
+	for _, comment := range comments[start:end] {
+		res = append(res, proto.Clone(comment).(*pb.TodoComment))
+	}
+
+	return res, count, nil
+}
+
+func (r *CommentRepo) Update(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.comments[comment.Id]; !ok {
+		return nil, repo.ErrNotFound
+	}
+	r.comments[comment.Id] = proto.Clone(comment).(*pb.TodoComment)
+
+	return proto.Clone(comment).(*pb.TodoComment), nil
+}
+
+func (r *CommentRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.comments[id]; !ok {
+		return repo.ErrNotFound
+	}
+	r.remove(func(comment *pb.TodoComment) bool {
+		return comment.Id == id
+	})
+
+	return nil
+}
+
+func (r *CommentRepo) DeleteAll(todoID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.remove(func(comment *pb.TodoComment) bool {
+		return comment.TodoId == todoID
+	})
+
+	return nil
+}
+
+// All returns every stored comment in creation order, see TodoRepo.All
+func (r *CommentRepo) All() []*pb.TodoComment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoComment, 0, len(r.order))
+	for _, id := range r.order {
+		res = append(res, proto.Clone(r.comments[id]).(*pb.TodoComment))
+	}
+
+	return res
+}
+
+// remove drops the comments matching fn, it must be called with mu held
+func (r *CommentRepo) remove(fn func(*pb.TodoComment) bool) {
+	order := r.order[:0]
+	for _, id := range r.order {
+		if fn(r.comments[id]) {
+			delete(r.comments, id)
+			continue
+		}
+		order = append(order, id)
+	}
+	r.order = order
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// FeedTokenRepo keeps feed tokens in process memory
+type FeedTokenRepo struct {
+	mu     sync.RWMutex
+	tokens map[string]*pb.FeedToken
+	// users finds the user of a token hash
+	users map[string]string
+}
+
+// NewFeedTokenRepo ...
+func NewFeedTokenRepo() *FeedTokenRepo {
+	return &FeedTokenRepo{
+		tokens: make(map[string]*pb.FeedToken),
+		users:  make(map[string]string),
+	}
+}
+
+func (r *FeedTokenRepo) Put(token *pb.FeedToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.tokens[token.UserId]; ok {
+		delete(r.users, old.TokenHash)
+	}
+	r.tokens[token.UserId] = proto.Clone(token).(*pb.FeedToken)
+	r.users[token.TokenHash] = token.UserId
+
+	return nil
+}
+
+func (r *FeedTokenRepo) Get(userID string) (*pb.FeedToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	token, ok := r.tokens[userID]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(token).(*pb.FeedToken), nil
+}
+
+func (r *FeedTokenRepo) GetByHash(tokenHash string) (*pb.FeedToken, error) {
+	r.mu.RLock()
+	userID, ok := r.users[tokenHash]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return r.Get(userID)
+}
+
+func (r *FeedTokenRepo) Delete(userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[userID]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.users, token.TokenHash)
+	delete(r.tokens, userID)
+
+	return nil
+}
+
+// All returns every stored token by user, see TodoRepo.All
+func (r *FeedTokenRepo) All() []*pb.FeedToken {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.FeedToken, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		res = append(res, proto.Clone(token).(*pb.FeedToken))
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].UserId < res[j].UserId })
+
+	return res
+}
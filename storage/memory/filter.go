@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fieldGetter returns a field of a record as a string, an int64, a
+// *timestamppb.Timestamp or a []string of tags, see repo.FilterKind
+type fieldGetter func(field string) interface{}
+
+type matcher func(get fieldGetter) bool
+
+func todoField(todo *pb.TodoModel) fieldGetter {
+	return func(field string) interface{} { return repo.TodoField(todo, field) }
+}
+
+func listField(list *pb.TodoList) fieldGetter {
+	return func(field string) interface{} { return repo.TodoListField(list, field) }
+}
+
+func commentField(comment *pb.TodoComment) fieldGetter {
+	return func(field string) interface{} { return repo.CommentField(comment, field) }
+}
+
+// compileFilter turns a filter into a matcher, nil when there is no filter
+func compileFilter(expr *pb.FilterExpr, fields map[string]repo.FilterKind) (matcher, error) {
+	if expr == nil {
+		return nil, nil
+	}
+	if err := repo.CheckFilter(expr, fields); err != nil {
+		return nil, err
+	}
+
+	return compileExpr(expr), nil
+}
+
+// compileExpr expects a checked expression
+func compileExpr(expr *pb.FilterExpr) matcher {
+	switch e := expr.Expr.(type) {
+	case *pb.FilterExpr_And:
+		operands := compileOperands(e.And.Operands)
+		return func(get fieldGetter) bool {
+			for _, m := range operands {
+				if !m(get) {
+					return false
+				}
+			}
+			return true
+		}
+	case *pb.FilterExpr_Or:
+		operands := compileOperands(e.Or.Operands)
+		return func(get fieldGetter) bool {
+			for _, m := range operands {
+				if m(get) {
+					return true
+				}
+			}
+			return false
+		}
+	case *pb.FilterExpr_Not:
+		m := compileExpr(e.Not)
+		return func(get fieldGetter) bool { return !m(get) }
+	}
+
+	return compileCondition(expr.GetCondition())
+}
+
+func compileOperands(operands []*pb.FilterExpr) []matcher {
+	matchers := make([]matcher, 0, len(operands))
+	for _, operand := range operands {
+		matchers = append(matchers, compileExpr(operand))
+	}
+
+	return matchers
+}
+
+func compileCondition(c *pb.FilterCondition) matcher {
+	field, values := c.Field, c.Values
+
+	equal := func(get fieldGetter) bool {
+		v := get(field)
+		for _, value := range values {
+			if cmp, ok := compareFilterValue(v, value); ok && cmp == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	ordered := func(test func(cmp int) bool) matcher {
+		return func(get fieldGetter) bool {
+			cmp, ok := compareFilterValue(get(field), values[0])
+			return ok && test(cmp)
+		}
+	}
+
+	switch c.Op {
+	case pb.FilterCondition_NE, pb.FilterCondition_NOT_IN:
+		return func(get fieldGetter) bool { return !equal(get) }
+	case pb.FilterCondition_LT:
+		return ordered(func(cmp int) bool { return cmp < 0 })
+	case pb.FilterCondition_LE:
+		return ordered(func(cmp int) bool { return cmp <= 0 })
+	case pb.FilterCondition_GT:
+		return ordered(func(cmp int) bool { return cmp > 0 })
+	case pb.FilterCondition_GE:
+		return ordered(func(cmp int) bool { return cmp >= 0 })
+	}
+
+	return equal
+}
+
+// compareFilterValue compares a field with a filter value, ok is false
+// when only one of them is an unset time, like NULL in postgres. Tags are
+// equal to each tag they contain and unequal to anything else.
+func compareFilterValue(field interface{}, value *pb.FilterValue) (cmp int, ok bool) {
+	switch f := field.(type) {
+	case string:
+		// a null string is an empty one
+		return strings.Compare(f, value.GetStringValue()), true
+	case int64:
+		return compareInts(f, value.GetIntValue()), true
+	case *timestamppb.Timestamp:
+		t := value.GetTimeValue()
+		if f == nil || t == nil {
+			return 0, f == nil && t == nil
+		}
+		return compareTimestamps(f, t), true
+	case []string:
+		tag, _ := repo.Tag(value.GetStringValue())
+		for _, t := range f {
+			if t == tag {
+				return 0, true
+			}
+		}
+		return 0, false
+	}
+
+	return 0, false
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// ReminderRepo keeps pending reminders in process memory
+type ReminderRepo struct {
+	mu        sync.RWMutex
+	reminders map[string]*pb.TodoReminder
+}
+
+// NewReminderRepo ...
+func NewReminderRepo() *ReminderRepo {
+	return &ReminderRepo{
+		reminders: make(map[string]*pb.TodoReminder),
+	}
+}
+
+func (r *ReminderRepo) Put(reminder *pb.TodoReminder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reminders[reminder.Id] = proto.Clone(reminder).(*pb.TodoReminder)
+
+	return nil
+}
+
+func (r *ReminderRepo) Get(id string) (*pb.TodoReminder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	reminder, ok := r.reminders[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(reminder).(*pb.TodoReminder), nil
+}
+
+func (r *ReminderRepo) GetAll(todoID string) ([]*pb.TodoReminder, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var res []*pb.TodoReminder
+	for _, reminder := range r.reminders {
+		if reminder.TodoId == todoID {
+			res = append(res, proto.Clone(reminder).(*pb.TodoReminder))
+		}
+	}
+	sortReminders(res)
+
+	return res, nil
+}
+
+func (r *ReminderRepo) GetPending() ([]*pb.TodoReminder, error) {
+	return r.All(), nil
+}
+
+func (r *ReminderRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reminders[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.reminders, id)
+
+	return nil
+}
+
+// All returns every stored reminder, earliest first, see TodoRepo.All
+func (r *ReminderRepo) All() []*pb.TodoReminder {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoReminder, 0, len(r.reminders))
+	for _, reminder := range r.reminders {
+		res = append(res, proto.Clone(reminder).(*pb.TodoReminder))
+	}
+	sortReminders(res)
+
+	return res
+}
+
+func sortReminders(reminders []*pb.TodoReminder) {
+	sort.Slice(reminders, func(i, j int) bool {
+		a, b := reminders[i].RemindAt.AsTime(), reminders[j].RemindAt.AsTime()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return reminders[i].Id < reminders[j].Id
+	})
+}
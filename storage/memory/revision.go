@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/golang/protobuf/proto"
+)
+
+// RevisionRepo keeps todo revisions in process memory
+type RevisionRepo struct {
+	mu        sync.RWMutex
+	revisions map[string][]*pb.TodoRevision
+}
+
+// NewRevisionRepo ...
+func NewRevisionRepo() *RevisionRepo {
+	return &RevisionRepo{
+		revisions: make(map[string][]*pb.TodoRevision),
+	}
+}
+
+func (r *RevisionRepo) Create(rev *pb.TodoRevision) (*pb.TodoRevision, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rev = proto.Clone(rev).(*pb.TodoRevision)
+	rev.Revision = int64(len(r.revisions[rev.TodoId])) + 1
+	r.revisions[rev.TodoId] = append(r.revisions[rev.TodoId], rev)
+
+	return proto.Clone(rev).(*pb.TodoRevision), nil
+}
+
+func (r *RevisionRepo) GetAll(todoID string) ([]*pb.TodoRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoRevision, 0, len(r.revisions[todoID]))
+	for _, rev := range r.revisions[todoID] {
+		res = append(res, proto.Clone(rev).(*pb.TodoRevision))
+	}
+
+	return res, nil
+}
+
+// All returns every stored revision, the revisions of a todo in order, see
+// TodoRepo.All
+func (r *RevisionRepo) All() []*pb.TodoRevision {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var res []*pb.TodoRevision
+	for _, revisions := range r.revisions {
+		for _, rev := range revisions {
+			res = append(res, proto.Clone(rev).(*pb.TodoRevision))
+		}
+	}
+
+	return res
+}
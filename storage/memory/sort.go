@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// todoComparers compare two todos by a sort field, see repo.TodoSortFields
+var todoComparers = map[string]func(a, b *pb.TodoModel) int{
+	"task_name":   func(a, b *pb.TodoModel) int { return strings.Compare(a.TaskName, b.TaskName) },
+	"task_status": func(a, b *pb.TodoModel) int { return compareInts(int64(a.TaskStatus), int64(b.TaskStatus)) },
+	"priority":    func(a, b *pb.TodoModel) int { return compareInts(int64(a.Priority), int64(b.Priority)) },
+	"due_at":      func(a, b *pb.TodoModel) int { return compareTimestamps(a.DueAt, b.DueAt) },
+	"created_at":  func(a, b *pb.TodoModel) int { return compareTimestamps(a.CreatedAt, b.CreatedAt) },
+	"updated_at":  func(a, b *pb.TodoModel) int { return compareTimestamps(a.UpdatedAt, b.UpdatedAt) },
+	"deleted_at":  func(a, b *pb.TodoModel) int { return compareTimestamps(a.DeletedAt, b.DeletedAt) },
+}
+
+// listComparers compare two lists by a sort field, see
+// repo.TodoListSortFields
+var listComparers = map[string]func(a, b *pb.TodoList) int{
+	"name":       func(a, b *pb.TodoList) int { return strings.Compare(a.Name, b.Name) },
+	"created_at": func(a, b *pb.TodoList) int { return compareTimestamps(a.CreatedAt, b.CreatedAt) },
+	"updated_at": func(a, b *pb.TodoList) int { return compareTimestamps(a.UpdatedAt, b.UpdatedAt) },
+	"deleted_at": func(a, b *pb.TodoList) int { return compareTimestamps(a.DeletedAt, b.DeletedAt) },
+}
+
+// sortTodos sorts todos by fields, or repo.DefaultSort without any, ties
+// are ordered by id. Unknown fields are skipped.
+func sortTodos(todos []*pb.TodoModel, fields []*pb.SortField) {
+	if len(fields) == 0 {
+		fields = repo.DefaultSort
+	}
+
+	sort.Slice(todos, func(i, j int) bool {
+		for _, f := range fields {
+			compare, ok := todoComparers[f.Field]
+			if !ok {
+				continue
+			}
+			if c := direction(compare(todos[i], todos[j]), f.Desc); c != 0 {
+				return c < 0
+			}
+		}
+		return todos[i].Id < todos[j].Id
+	})
+}
+
+// sortLists sorts lists like sortTodos
+func sortLists(lists []*pb.TodoList, fields []*pb.SortField) {
+	if len(fields) == 0 {
+		fields = repo.DefaultSort
+	}
+
+	sort.Slice(lists, func(i, j int) bool {
+		for _, f := range fields {
+			compare, ok := listComparers[f.Field]
+			if !ok {
+				continue
+			}
+			if c := direction(compare(lists[i], lists[j]), f.Desc); c != 0 {
+				return c < 0
+			}
+		}
+		return lists[i].Id < lists[j].Id
+	})
+}
+
+// sortComments sorts comments oldest first, ties are ordered by id
+func sortComments(comments []*pb.TodoComment) {
+	sort.Slice(comments, func(i, j int) bool {
+		if c := compareTimestamps(comments[i].CreatedAt, comments[j].CreatedAt); c != 0 {
+			return c < 0
+		}
+		return comments[i].Id < comments[j].Id
+	})
+}
+
+func direction(c int, desc bool) int {
+	if desc {
+		return -c
+	}
+	return c
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareTimestamps orders unset timestamps last, and first when
+// descending, like postgres does with NULLs
+func compareTimestamps(a, b *timestamppb.Timestamp) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	case a.Seconds != b.Seconds:
+		return compareInts(a.Seconds, b.Seconds)
+	}
+
+	return compareInts(int64(a.Nanos), int64(b.Nanos))
+}
@@ -0,0 +1,222 @@
+package memory
+
+import (
+	"strings"
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// TodoRepo keeps todos in process memory. Besides repo.TodoStorageI it
+// exposes All, which durable stores built on top of it use for snapshots.
+type TodoRepo struct {
+	mu    sync.RWMutex
+	todos map[string]*pb.TodoModel
+	// order keeps ids in insertion order, so listing without sort is stable
+	order []string
+}
+
+// NewTodoRepo ...
+func NewTodoRepo() *TodoRepo {
+	return &TodoRepo{
+		todos: make(map[string]*pb.TodoModel),
+	}
+}
+
+func (r *TodoRepo) Create(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.todos[todo.Id]; !ok {
+		r.order = append(r.order, todo.Id)
+	}
+	r.todos[todo.Id] = proto.Clone(todo).(*pb.TodoModel)
+
+	return proto.Clone(todo).(*pb.TodoModel), nil
+}
+
+func (r *TodoRepo) Get(id string) (*pb.TodoModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	todo, ok := r.todos[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(todo).(*pb.TodoModel), nil
+}
+
+func (r *TodoRepo) GetAll(req *pb.ListTodosRequest) ([]*pb.TodoModel, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	match, err := compileFilter(req.Filter, repo.TodoFilterFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	after, err := compileFilter(req.After, repo.TodoFilterFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	search := strings.ToLower(req.Search)
+	todos := make([]*pb.TodoModel, 0, len(r.order))
+	for _, id := range r.order {
+		todo := r.todos[id]
+		if match != nil && !match(todoField(todo)) {
+			continue
+		}
+		if req.ParentId != "" && todo.ParentId != req.ParentId {
+			continue
+		}
+		if req.ListId != "" && todo.ListId != req.ListId {
+			continue
+		}
+		if (todo.DeletedAt != nil) != req.Trashed {
+			continue
+		}
+		if search != "" && !matchesSearch(todo, search) {
+			continue
+		}
+		todos = append(todos, todo)
+	}
+
+	sortTodos(todos, req.Sort)
+
+	count := int64(len(todos))
+	if after != nil {
+		rest := todos[:0]
+		for _, todo := range todos {
+			if after(todoField(todo)) {
+				rest = append(rest, todo)
+			}
+		}
+		todos = rest
+	}
+	todos = paginate(todos, req.Page, req.Limit)
+
+	res := make([]*pb.TodoModel, 0, len(todos))
+	for _, todo := range todos {
+		res = append(res, proto.Clone(todo).(*pb.TodoModel))
+	}
+
+	return res, count, nil
+}
+
+func (r *TodoRepo) Update(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.todos[todo.Id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	if current.Version != todo.Version {
+		return nil, repo.ErrVersionConflict
+	}
+
+	updated := proto.Clone(todo).(*pb.TodoModel)
+	updated.Version++
+	r.todos[todo.Id] = updated
+
+	return proto.Clone(updated).(*pb.TodoModel), nil
+}
+
+func (r *TodoRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.todos[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.todos, id)
+
+	for i, v := range r.order {
+		if v == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+func (r *TodoRepo) SubtaskProgress(parentIDs []string) (map[string]*pb.SubtaskProgress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]bool, len(parentIDs))
+	for _, id := range parentIDs {
+		wanted[id] = true
+	}
+
+	res := make(map[string]*pb.SubtaskProgress)
+	for _, todo := range r.todos {
+		if !wanted[todo.ParentId] || todo.DeletedAt != nil || todo.TaskStatus == pb.TaskStatus_TASK_STATUS_CANCELLED {
+			continue
+		}
+
+		progress, ok := res[todo.ParentId]
+		if !ok {
+			progress = &pb.SubtaskProgress{}
+			res[todo.ParentId] = progress
+		}
+		progress.Total++
+		if todo.TaskStatus == pb.TaskStatus_TASK_STATUS_DONE {
+			progress.Done++
+		}
+	}
+
+	return res, nil
+}
+
+// All returns every stored todo in insertion order
+func (r *TodoRepo) All() []*pb.TodoModel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoModel, 0, len(r.order))
+	for _, id := range r.order {
+		res = append(res, proto.Clone(r.todos[id]).(*pb.TodoModel))
+	}
+
+	return res
+}
+
+// matchesSearch reports whether the lower cased search is in the todo's
+// name or description
+func matchesSearch(todo *pb.TodoModel, search string) bool {
+	return strings.Contains(strings.ToLower(todo.TaskName), search) ||
+		strings.Contains(strings.ToLower(todo.Description), search)
+}
+
+func paginate(todos []*pb.TodoModel, page, limit int64) []*pb.TodoModel {
+	start, end := pageBounds(int64(len(todos)), page, limit)
+	return todos[start:end]
+}
+
+// pageBounds returns the slice bounds of a page out of size items, a
+// limit of zero means everything
+func pageBounds(size, page, limit int64) (start, end int64) {
+	if limit <= 0 {
+		return 0, size
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	start = (page - 1) * limit
+	if start >= size {
+		return size, size
+	}
+
+	end = start + limit
+	if end > size {
+		end = size
+	}
+
+	return start, end
+}
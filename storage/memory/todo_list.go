@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"strings"
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/golang/protobuf/proto"
+)
+
+// TodoListRepo keeps todo lists in process memory
+type TodoListRepo struct {
+	mu    sync.RWMutex
+	lists map[string]*pb.TodoList
+	// order keeps ids in insertion order, so listing is stable
+	order []string
+}
+
+// NewTodoListRepo ...
+func NewTodoListRepo() *TodoListRepo {
+	return &TodoListRepo{
+		lists: make(map[string]*pb.TodoList),
+	}
+}
+
+func (r *TodoListRepo) Create(list *pb.TodoList) (*pb.TodoList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[list.Id]; !ok {
+		r.order = append(r.order, list.Id)
+	}
+	r.lists[list.Id] = proto.Clone(list).(*pb.TodoList)
+
+	return proto.Clone(list).(*pb.TodoList), nil
+}
+
+func (r *TodoListRepo) Get(id string) (*pb.TodoList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list, ok := r.lists[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return proto.Clone(list).(*pb.TodoList), nil
+}
+
+func (r *TodoListRepo) GetInbox(userID string) (*pb.TodoList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, id := range r.order {
+		list := r.lists[id]
+		if list.Inbox && list.UserId == userID {
+			return proto.Clone(list).(*pb.TodoList), nil
+		}
+	}
+
+	return nil, repo.ErrNotFound
+}
+
+func (r *TodoListRepo) GetAll(req *pb.ListTodoListsRequest) ([]*pb.TodoList, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	match, err := compileFilter(req.Filter, repo.TodoListFilterFields)
+	if err != nil {
+		return nil, 0, err
+	}
+	after, err := compileFilter(req.After, repo.TodoListFilterFields)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	search := strings.ToLower(req.Search)
+	lists := make([]*pb.TodoList, 0, len(r.order))
+	for _, id := range r.order {
+		list := r.lists[id]
+		if match != nil && !match(listField(list)) {
+			continue
+		}
+		if list.ArchivedAt != nil && !req.IncludeArchived {
+			continue
+		}
+		if (list.DeletedAt != nil) != req.Trashed {
+			continue
+		}
+		if req.UserId != "" && list.UserId != req.UserId {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(list.Name), search) {
+			continue
+		}
+		lists = append(lists, list)
+	}
+
+	sortLists(lists, req.Sort)
+
+	count := int64(len(lists))
+	if after != nil {
+		rest := lists[:0]
+		for _, list := range lists {
+			if after(listField(list)) {
+				rest = append(rest, list)
+			}
+		}
+		lists = rest
+	}
+	lists = paginateLists(lists, req.Page, req.Limit)
+
+	res := make([]*pb.TodoList, 0, len(lists))
+	for _, list := range lists {
+		res = append(res, proto.Clone(list).(*pb.TodoList))
+	}
+
+	return res, count, nil
+}
+
+func (r *TodoListRepo) Update(list *pb.TodoList) (*pb.TodoList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[list.Id]; !ok {
+		return nil, repo.ErrNotFound
+	}
+	r.lists[list.Id] = proto.Clone(list).(*pb.TodoList)
+
+	return proto.Clone(list).(*pb.TodoList), nil
+}
+
+func (r *TodoListRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.lists[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.lists, id)
+
+	for i, v := range r.order {
+		if v == id {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// All returns every stored list in insertion order, see TodoRepo.All
+func (r *TodoListRepo) All() []*pb.TodoList {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoList, 0, len(r.order))
+	for _, id := range r.order {
+		res = append(res, proto.Clone(r.lists[id]).(*pb.TodoList))
+	}
+
+	return res
+}
+
+func paginateLists(lists []*pb.TodoList, page, limit int64) []*pb.TodoList {
+	start, end := pageBounds(int64(len(lists)), page, limit)
+	return lists[start:end]
+}
@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"sync"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/golang/protobuf/proto"
+)
+
+// TransitionRepo keeps todo transitions in process memory
+type TransitionRepo struct {
+	mu          sync.RWMutex
+	transitions map[string][]*pb.TodoTransition
+}
+
+// NewTransitionRepo ...
+func NewTransitionRepo() *TransitionRepo {
+	return &TransitionRepo{
+		transitions: make(map[string][]*pb.TodoTransition),
+	}
+}
+
+func (r *TransitionRepo) Create(t *pb.TodoTransition) (*pb.TodoTransition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.transitions[t.TodoId] = append(r.transitions[t.TodoId], proto.Clone(t).(*pb.TodoTransition))
+
+	return proto.Clone(t).(*pb.TodoTransition), nil
+}
+
+func (r *TransitionRepo) GetAll(todoID string) ([]*pb.TodoTransition, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]*pb.TodoTransition, 0, len(r.transitions[todoID]))
+	for _, t := range r.transitions[todoID] {
+		res = append(res, proto.Clone(t).(*pb.TodoTransition))
+	}
+
+	return res, nil
+}
+
+// All returns every stored transition, see TodoRepo.All
+func (r *TransitionRepo) All() []*pb.TodoTransition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var res []*pb.TodoTransition
+	for _, transitions := range r.transitions {
+		for _, t := range transitions {
+			res = append(res, proto.Clone(t).(*pb.TodoTransition))
+		}
+	}
+
+	return res
+}
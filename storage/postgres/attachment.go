@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"database/sql"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+const attachmentColumns = `id, todo_id, file_name, content_type, size, sha256, uploaded_by, created_at`
+
+type attachmentRepo struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepo ...
+func NewAttachmentRepo(db *sql.DB) repo.AttachmentStorageI {
+	return &attachmentRepo{
+		db: db,
+	}
+}
+
+func (r *attachmentRepo) Create(attachment *pb.TodoAttachment) (*pb.TodoAttachment, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO todo_attachments (id, todo_id, file_name, content_type, size, sha256, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()))`,
+		attachment.Id,
+		attachment.TodoId,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Sha256,
+		nullIfEmpty(attachment.UploadedBy),
+		etc.NullTime(attachment.CreatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(attachment.Id)
+}
+
+func (r *attachmentRepo) Get(id string) (*pb.TodoAttachment, error) {
+	attachment, err := scanAttachment(r.db.QueryRow(`
+		SELECT `+attachmentColumns+`
+		FROM todo_attachments
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return attachment, nil
+}
+
+func (r *attachmentRepo) GetAll(todoID string) ([]*pb.TodoAttachment, error) {
+	rows, err := r.db.Query(`
+		SELECT `+attachmentColumns+`
+		FROM todo_attachments
+		WHERE todo_id = $1
+		ORDER BY created_at, id`,
+		todoID,
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	defer rows.Close()
+
+	var attachments []*pb.TodoAttachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *attachmentRepo) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM todo_attachments WHERE id = $1`, id)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func (r *attachmentRepo) UsedBytes(userID string) (int64, error) {
+	var used int64
+	err := r.db.QueryRow(`
+		SELECT COALESCE(SUM(size), 0)
+		FROM todo_attachments
+		WHERE uploaded_by IS NOT DISTINCT FROM $1`,
+		nullIfEmpty(userID),
+	).Scan(&used)
+
+	return used, err
+}
+
+func (r *attachmentRepo) References(sha256 string) (int64, error) {
+	var refs int64
+	err := r.db.QueryRow(`SELECT count(*) FROM todo_attachments WHERE sha256 = $1`, sha256).Scan(&refs)
+
+	return refs, err
+}
+
+func scanAttachment(row scanner) (*pb.TodoAttachment, error) {
+	var (
+		attachment pb.TodoAttachment
+		uploadedBy sql.NullString
+		createdAt  sql.NullTime
+	)
+
+	err := row.Scan(
+		&attachment.Id,
+		&attachment.TodoId,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Sha256,
+		&uploadedBy,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	attachment.UploadedBy = etc.StringValue(uploadedBy).GetValue()
+	attachment.CreatedAt = etc.TimestampValue(createdAt)
+
+	return &attachment, nil
+}
@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/lib/pq"
+)
+
+const commentColumns = `id, todo_id, parent_id, author_id, body, mentions, created_at, updated_at`
+
+type commentRepo struct {
+	db *sql.DB
+}
+
+// NewCommentRepo ...
+func NewCommentRepo(db *sql.DB) repo.CommentStorageI {
+	return &commentRepo{
+		db: db,
+	}
+}
+
+func (r *commentRepo) Create(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO todo_comments (id, todo_id, parent_id, author_id, body, mentions, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), COALESCE($8, NOW()))`,
+		comment.Id,
+		comment.TodoId,
+		nullIfEmpty(comment.ParentId),
+		nullIfEmpty(comment.AuthorId),
+		comment.Body,
+		pq.Array(mentions(comment)),
+		etc.NullTime(comment.CreatedAt),
+		etc.NullTime(comment.UpdatedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertCommentEdits(tx, comment); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.Get(comment.Id)
+}
+
+func (r *commentRepo) Get(id string) (*pb.TodoComment, error) {
+	comment, err := scanComment(r.db.QueryRow(`
+		SELECT `+commentColumns+`
+		FROM todo_comments
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	if err = r.loadEdits(comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (r *commentRepo) GetAll(req *pb.ListTodoCommentsRequest) ([]*pb.TodoComment, int64, error) {
+	var (
+		filter = " WHERE TRUE"
+		args   []interface{}
+		count  int64
+	)
+
+	if req.TodoId != "" {
+		args = append(args, req.TodoId)
+		filter += " AND todo_id = $" + strconv.Itoa(len(args))
+	}
+
+	if req.ParentId != "" {
+		args = append(args, req.ParentId)
+		filter += " AND parent_id = $" + strconv.Itoa(len(args))
+	}
+
+	if req.Mention != "" {
+		args = append(args, pq.Array([]string{req.Mention}))
+		filter += " AND mentions @> $" + strconv.Itoa(len(args))
+	}
+
+	if req.WithCount {
+		err := r.db.QueryRow(`SELECT count(*) FROM todo_comments`+filter, args...).Scan(&count)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// after only skips the rows of earlier pages, count is the total
+	after, args, err := whereFilter(req.After, repo.CommentFilterFields, commentFilterColumns, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + commentColumns + ` FROM todo_comments` + filter + after + ` ORDER BY created_at, id`
+	if req.Limit > 0 {
+		page := req.Page
+		if page <= 0 {
+			page = 1
+		}
+		args = append(args, req.Limit, (page-1)*req.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var comments []*pb.TodoComment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		comments = append(comments, comment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err = r.loadEdits(comments...); err != nil {
+		return nil, 0, err
+	}
+
+	return comments, count, nil
+}
+
+func (r *commentRepo) Update(comment *pb.TodoComment) (*pb.TodoComment, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE todo_comments SET
+			body = $2,
+			mentions = $3,
+			updated_at = COALESCE($4, NOW())
+		WHERE id = $1`,
+		comment.Id,
+		comment.Body,
+		pq.Array(mentions(comment)),
+		etc.NullTime(comment.UpdatedAt),
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	if err = checkAffected(res); err != nil {
+		return nil, err
+	}
+
+	// the edits are rewritten as a whole, the service only appends to them
+	_, err = tx.Exec(`DELETE FROM todo_comment_edits WHERE comment_id = $1`, comment.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertCommentEdits(tx, comment); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.Get(comment.Id)
+}
+
+func (r *commentRepo) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM todo_comments WHERE id = $1`, id)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func (r *commentRepo) DeleteAll(todoID string) error {
+	_, err := r.db.Exec(`DELETE FROM todo_comments WHERE todo_id = $1`, todoID)
+	return err
+}
+
+// loadEdits sets the edit history of comments
+func (r *commentRepo) loadEdits(comments ...*pb.TodoComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(comments))
+	byID := make(map[string]*pb.TodoComment, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.Id)
+		byID[comment.Id] = comment
+	}
+
+	rows, err := r.db.Query(`
+		SELECT comment_id, body, edited_at
+		FROM todo_comment_edits
+		WHERE comment_id = ANY($1::uuid[])
+		ORDER BY edited_at`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			commentID string
+			edit      pb.CommentEdit
+			editedAt  sql.NullTime
+		)
+
+		if err = rows.Scan(&commentID, &edit.Body, &editedAt); err != nil {
+			return err
+		}
+		edit.EditedAt = etc.TimestampValue(editedAt)
+
+		comment := byID[commentID]
+		comment.Edits = append(comment.Edits, &edit)
+	}
+
+	return rows.Err()
+}
+
+func insertCommentEdits(tx *sql.Tx, comment *pb.TodoComment) error {
+	for _, edit := range comment.Edits {
+		_, err := tx.Exec(`
+			INSERT INTO todo_comment_edits (comment_id, body, edited_at)
+			VALUES ($1, $2, COALESCE($3, NOW()))`,
+			comment.Id,
+			edit.Body,
+			etc.NullTime(edit.EditedAt),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mentions never returns nil, so the column gets an empty array
+func mentions(comment *pb.TodoComment) []string {
+	if comment.Mentions == nil {
+		return []string{}
+	}
+
+	return comment.Mentions
+}
+
+func scanComment(row scanner) (*pb.TodoComment, error) {
+	var (
+		comment   pb.TodoComment
+		parentID  sql.NullString
+		authorID  sql.NullString
+		createdAt sql.NullTime
+		updatedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&comment.Id,
+		&comment.TodoId,
+		&parentID,
+		&authorID,
+		&comment.Body,
+		pq.Array(&comment.Mentions),
+		&createdAt,
+		&updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	comment.ParentId = etc.StringValue(parentID).GetValue()
+	comment.AuthorId = etc.StringValue(authorID).GetValue()
+	comment.CreatedAt = etc.TimestampValue(createdAt)
+	comment.UpdatedAt = etc.TimestampValue(updatedAt)
+
+	return &comment, nil
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDB is a database/sql stand-in for postgres which answers the
+// statements a test expects, in order, with scripted results
+type fakeDB struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	expected []*fakeCall
+}
+
+// fakeCall is an expected statement, query has to be contained in the
+// statement up to whitespace and args, when set, have to match
+type fakeCall struct {
+	query    string
+	args     []driver.Value
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	err      error
+}
+
+// newFakeDB returns a database which fails the test when statements are
+// left unexpected or expected ones are not run
+func newFakeDB(t *testing.T) (*sql.DB, *fakeDB) {
+	fake := &fakeDB{t: t}
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() {
+		db.Close()
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for _, call := range fake.expected {
+			t.Errorf("expected statement was not run: %s", call.query)
+		}
+	})
+
+	return db, fake
+}
+
+func (db *fakeDB) expect(query string, args ...driver.Value) *fakeCall {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	call := &fakeCall{query: normalizeQuery(query), args: args}
+	db.expected = append(db.expected, call)
+	return call
+}
+
+// returns makes the call return rows of columns
+func (c *fakeCall) returns(columns []string, rows ...[]driver.Value) *fakeCall {
+	c.columns = columns
+	c.rows = rows
+	return c
+}
+
+// affects makes the call report n affected rows
+func (c *fakeCall) affects(n int64) *fakeCall {
+	c.affected = n
+	return c
+}
+
+// fails makes the call return err
+func (c *fakeCall) fails(err error) *fakeCall {
+	c.err = err
+	return c
+}
+
+func (db *fakeDB) next(query string, args []driver.NamedValue) (*fakeCall, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	query = normalizeQuery(query)
+	if len(db.expected) == 0 {
+		db.t.Errorf("unexpected statement: %s", query)
+		return nil, errors.New("fake: unexpected statement")
+	}
+	call := db.expected[0]
+	db.expected = db.expected[1:]
+
+	if !strings.Contains(query, call.query) {
+		db.t.Errorf("statement\n\t%s\ndoes not contain\n\t%s", query, call.query)
+		return nil, errors.New("fake: unexpected statement")
+	}
+	if call.args != nil {
+		values := make([]driver.Value, 0, len(args))
+		for _, arg := range args {
+			values = append(values, arg.Value)
+		}
+		if !reflect.DeepEqual(values, call.args) {
+			db.t.Errorf("statement %s\ngot args %#v\nwant %#v", call.query, values, call.args)
+		}
+	}
+
+	return call, call.err
+}
+
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func (db *fakeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return fakeConn{db}, nil
+}
+
+func (db *fakeDB) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("fake: use the connector")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake: prepared statements are not supported")
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+// Begin starts a transaction, which is expected as a BEGIN statement and
+// ends with a COMMIT or ROLLBACK one
+func (c fakeConn) Begin() (driver.Tx, error) {
+	if _, err := c.db.next("BEGIN", nil); err != nil {
+		return nil, err
+	}
+
+	return fakeTx{c.db}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	_, err := tx.db.next("COMMIT", nil)
+	return err
+}
+
+func (tx fakeTx) Rollback() error {
+	_, err := tx.db.next("ROLLBACK", nil)
+	return err
+}
+
+func (c fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	call, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.RowsAffected(call.affected), nil
+}
+
+func (c fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	call, err := c.db.next(query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fakeRows{columns: call.columns, rows: call.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
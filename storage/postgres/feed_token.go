@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+const feedTokenColumns = `user_id, token_hash, created_at`
+
+type feedTokenRepo struct {
+	db *sql.DB
+}
+
+// NewFeedTokenRepo ...
+func NewFeedTokenRepo(db *sql.DB) repo.FeedTokenStorageI {
+	return &feedTokenRepo{
+		db: db,
+	}
+}
+
+func (r *feedTokenRepo) Put(token *pb.FeedToken) error {
+	_, err := r.db.Exec(`
+		INSERT INTO feed_tokens (user_id, token_hash, created_at)
+		VALUES ($1, $2, COALESCE($3, NOW()))
+		ON CONFLICT (user_id) DO UPDATE SET
+			token_hash = EXCLUDED.token_hash,
+			created_at = EXCLUDED.created_at`,
+		token.UserId,
+		token.TokenHash,
+		etc.NullTime(token.CreatedAt),
+	)
+
+	return handleError(err)
+}
+
+func (r *feedTokenRepo) Get(userID string) (*pb.FeedToken, error) {
+	return r.get(`user_id`, userID)
+}
+
+func (r *feedTokenRepo) GetByHash(tokenHash string) (*pb.FeedToken, error) {
+	return r.get(`token_hash`, tokenHash)
+}
+
+func (r *feedTokenRepo) Delete(userID string) error {
+	res, err := r.db.Exec(`DELETE FROM feed_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func (r *feedTokenRepo) get(column, value string) (*pb.FeedToken, error) {
+	var (
+		token     pb.FeedToken
+		createdAt sql.NullTime
+	)
+
+	err := r.db.QueryRow(`
+		SELECT `+feedTokenColumns+`
+		FROM feed_tokens
+		WHERE `+column+` = $1`,
+		value,
+	).Scan(
+		&token.UserId,
+		&token.TokenHash,
+		&createdAt,
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	token.CreatedAt = etc.TimestampValue(createdAt)
+
+	return &token, nil
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/lib/pq"
+)
+
+// todoFilterColumns are the columns of repo.TodoFilterFields, uuid columns
+// compared as strings are cast to text
+var todoFilterColumns = map[string]string{
+	"id":           "id",
+	"task_name":    "task_name",
+	"description":  "description",
+	"task_status":  "task_status",
+	"priority":     "priority",
+	"due_at":       "due_at",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+	"user_id":      "user_id",
+	"workspace_id": "workspace_id",
+	"updated_by":   "updated_by",
+	"parent_id":    "parent_id::text",
+	"list_id":      "list_id::text",
+	"deleted_at":   "deleted_at",
+	"tag":          "tags",
+}
+
+// todoListFilterColumns are the columns of repo.TodoListFilterFields
+var todoListFilterColumns = map[string]string{
+	"id":          "id",
+	"name":        "name",
+	"description": "description",
+	"user_id":     "user_id",
+	"archived_at": "archived_at",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+	"deleted_at":  "deleted_at",
+}
+
+// commentFilterColumns are the columns of repo.CommentFilterFields
+var commentFilterColumns = map[string]string{
+	"id":         "id",
+	"created_at": "created_at",
+}
+
+// filterBuilder turns a filter into an SQL condition. Every condition is
+// true or false, never NULL, so NOT works like in the memory storage.
+type filterBuilder struct {
+	fields  map[string]repo.FilterKind
+	columns map[string]string
+	args    []interface{}
+}
+
+// whereFilter checks expr and returns its SQL condition, to be added to a
+// WHERE clause, with args extended by its values. A nil expr returns an
+// empty condition.
+func whereFilter(expr *pb.FilterExpr, fields map[string]repo.FilterKind, columns map[string]string, args []interface{}) (string, []interface{}, error) {
+	if expr == nil {
+		return "", args, nil
+	}
+	if err := repo.CheckFilter(expr, fields); err != nil {
+		return "", nil, err
+	}
+
+	b := &filterBuilder{fields: fields, columns: columns, args: args}
+	cond := b.expr(expr)
+
+	return " AND " + cond, b.args, nil
+}
+
+func (b *filterBuilder) expr(expr *pb.FilterExpr) string {
+	switch e := expr.Expr.(type) {
+	case *pb.FilterExpr_And:
+		return b.operands(e.And.Operands, " AND ")
+	case *pb.FilterExpr_Or:
+		return b.operands(e.Or.Operands, " OR ")
+	case *pb.FilterExpr_Not:
+		return "NOT (" + b.expr(e.Not) + ")"
+	}
+
+	return b.condition(expr.GetCondition())
+}
+
+func (b *filterBuilder) operands(operands []*pb.FilterExpr, sep string) string {
+	conds := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		conds = append(conds, b.expr(operand))
+	}
+
+	return "(" + strings.Join(conds, sep) + ")"
+}
+
+func (b *filterBuilder) condition(c *pb.FilterCondition) string {
+	column := b.columns[c.Field]
+	kind := b.fields[c.Field]
+	if kind == repo.FilterTag {
+		return b.tagCondition(column, c)
+	}
+	if kind == repo.FilterString {
+		// a null string is an empty one
+		column = "COALESCE(" + column + ", '')"
+	}
+
+	switch c.Op {
+	case pb.FilterCondition_IN, pb.FilterCondition_NOT_IN:
+		var arg interface{}
+		if kind != repo.FilterEnum {
+			values := make([]string, 0, len(c.Values))
+			for _, v := range c.Values {
+				values = append(values, v.GetStringValue())
+			}
+			arg = pq.Array(values)
+		} else {
+			values := make([]int64, 0, len(c.Values))
+			for _, v := range c.Values {
+				values = append(values, v.GetIntValue())
+			}
+			arg = pq.Array(values)
+		}
+		values := b.arg(arg)
+		if kind == repo.FilterID {
+			values += "::uuid[]"
+		}
+		cond := column + " = ANY(" + values + ")"
+		if c.Op == pb.FilterCondition_NOT_IN {
+			return "NOT (" + cond + ")"
+		}
+		return cond
+	}
+
+	v := c.Values[0]
+	if kind == repo.FilterTime {
+		switch {
+		case v.GetTimeValue() == nil && c.Op == pb.FilterCondition_EQ:
+			return column + " IS NULL"
+		case v.GetTimeValue() == nil:
+			return column + " IS NOT NULL"
+		case c.Op == pb.FilterCondition_NE:
+			return column + " IS DISTINCT FROM " + b.arg(v.GetTimeValue().AsTime())
+		}
+		return "COALESCE(" + column + " " + sqlOperator(c.Op) + " " + b.arg(v.GetTimeValue().AsTime()) + ", FALSE)"
+	}
+
+	var arg interface{} = v.GetStringValue()
+	if kind == repo.FilterEnum {
+		arg = v.GetIntValue()
+	}
+
+	return column + " " + sqlOperator(c.Op) + " " + b.arg(arg)
+}
+
+// tagCondition matches the rows whose tags column holds the tag, or for
+// IN any of the tags
+func (b *filterBuilder) tagCondition(column string, c *pb.FilterCondition) string {
+	tags := make([]string, 0, len(c.Values))
+	for _, v := range c.Values {
+		tag, _ := repo.Tag(v.GetStringValue())
+		tags = append(tags, tag)
+	}
+
+	var cond string
+	switch c.Op {
+	case pb.FilterCondition_IN, pb.FilterCondition_NOT_IN:
+		cond = column + " && " + b.arg(pq.Array(tags)) + "::text[]"
+	default:
+		cond = b.arg(tags[0]) + " = ANY(" + column + ")"
+	}
+	if c.Op == pb.FilterCondition_NE || c.Op == pb.FilterCondition_NOT_IN {
+		return "NOT (" + cond + ")"
+	}
+
+	return cond
+}
+
+// arg adds a value and returns its placeholder
+func (b *filterBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func sqlOperator(op pb.FilterCondition_Operator) string {
+	switch op {
+	case pb.FilterCondition_EQ:
+		return "="
+	case pb.FilterCondition_NE:
+		return "<>"
+	case pb.FilterCondition_LT:
+		return "<"
+	case pb.FilterCondition_LE:
+		return "<="
+	case pb.FilterCondition_GT:
+		return ">"
+	case pb.FilterCondition_GE:
+		return ">="
+	}
+
+	panic(fmt.Sprintf("postgres: no sql operator for %s", op))
+}
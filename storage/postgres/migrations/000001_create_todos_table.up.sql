@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS todos (
+    id UUID PRIMARY KEY,
+    task_name VARCHAR(255) NOT NULL,
+    task_status VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS todos_created_at_idx ON todos (created_at, id);
@@ -0,0 +1,3 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS user_id VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS todos_user_id_idx ON todos (user_id);
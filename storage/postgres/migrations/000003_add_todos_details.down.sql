@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS todos_due_at_idx;
+
+ALTER TABLE todos ALTER COLUMN task_status DROP NOT NULL;
+ALTER TABLE todos ALTER COLUMN task_status DROP DEFAULT;
+ALTER TABLE todos ALTER COLUMN task_status TYPE VARCHAR(50) USING
+    CASE task_status
+        WHEN 2 THEN 'in_progress'
+        WHEN 3 THEN 'done'
+        WHEN 4 THEN 'cancelled'
+        ELSE 'todo'
+    END;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS due_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
+ALTER TABLE todos DROP COLUMN IF EXISTS description;
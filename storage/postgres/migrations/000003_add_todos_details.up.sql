@@ -0,0 +1,19 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS description TEXT NOT NULL DEFAULT '';
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_at TIMESTAMP WITH TIME ZONE;
+
+-- task_status was free text, it now holds the TaskStatus enum value and
+-- anything unknown becomes TASK_STATUS_TODO
+ALTER TABLE todos ALTER COLUMN task_status TYPE SMALLINT USING
+    CASE lower(task_status)
+        WHEN 'in_progress' THEN 2
+        WHEN 'done' THEN 3
+        WHEN 'cancelled' THEN 4
+        ELSE 1
+    END;
+ALTER TABLE todos ALTER COLUMN task_status SET DEFAULT 1;
+ALTER TABLE todos ALTER COLUMN task_status SET NOT NULL;
+
+UPDATE todos SET updated_at = created_at WHERE updated_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS todos_due_at_idx ON todos (due_at);
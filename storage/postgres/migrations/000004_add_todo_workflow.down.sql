@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS todo_transitions;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS updated_by;
+ALTER TABLE todos DROP COLUMN IF EXISTS workspace_id;
@@ -0,0 +1,15 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS workspace_id VARCHAR(64);
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS updated_by VARCHAR(64);
+
+CREATE TABLE IF NOT EXISTS todo_transitions (
+    id UUID PRIMARY KEY,
+    -- no foreign key, the audit log outlives deleted todos
+    todo_id UUID NOT NULL,
+    from_status SMALLINT NOT NULL,
+    to_status SMALLINT NOT NULL,
+    actor_id VARCHAR(64),
+    comment TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS todo_transitions_todo_id_idx ON todo_transitions (todo_id, occurred_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS todo_lists (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    user_id VARCHAR(64),
+    inbox BOOLEAN NOT NULL DEFAULT FALSE,
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS todo_lists_created_at_idx ON todo_lists (created_at, id);
+-- one inbox per owner, todos without an owner share one too
+CREATE UNIQUE INDEX IF NOT EXISTS todo_lists_inbox_idx ON todo_lists (COALESCE(user_id, '')) WHERE inbox;
+
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS list_id UUID REFERENCES todo_lists (id);
+
+CREATE INDEX IF NOT EXISTS todos_list_id_idx ON todos (list_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS todo_comment_edits;
+
+DROP TABLE IF EXISTS todo_comments;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS todo_comments (
+    id UUID PRIMARY KEY,
+    todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    parent_id UUID REFERENCES todo_comments (id) ON DELETE CASCADE,
+    author_id VARCHAR(64),
+    body TEXT NOT NULL,
+    mentions TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS todo_comments_todo_id_idx ON todo_comments (todo_id, created_at);
+CREATE INDEX IF NOT EXISTS todo_comments_parent_id_idx ON todo_comments (parent_id);
+CREATE INDEX IF NOT EXISTS todo_comments_mentions_idx ON todo_comments USING GIN (mentions);
+
+-- earlier versions of edited comments
+CREATE TABLE IF NOT EXISTS todo_comment_edits (
+    comment_id UUID NOT NULL REFERENCES todo_comments (id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    edited_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS todo_comment_edits_comment_id_idx ON todo_comment_edits (comment_id, edited_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS todo_attachments (
+    id UUID PRIMARY KEY,
+    todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    uploaded_by VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS todo_attachments_todo_id_idx ON todo_attachments (todo_id, created_at);
+CREATE INDEX IF NOT EXISTS todo_attachments_sha256_idx ON todo_attachments (sha256);
+CREATE INDEX IF NOT EXISTS todo_attachments_uploaded_by_idx ON todo_attachments (uploaded_by);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS todo_lists_deleted_at_idx;
+DROP INDEX IF EXISTS todos_deleted_at_idx;
+
+ALTER TABLE todo_lists DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS deleted_at;
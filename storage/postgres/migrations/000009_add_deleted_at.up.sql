@@ -0,0 +1,6 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE todo_lists ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- the trash is listed and purged by deletion time
+CREATE INDEX IF NOT EXISTS todos_deleted_at_idx ON todos (deleted_at, id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS todo_lists_deleted_at_idx ON todo_lists (deleted_at, id) WHERE deleted_at IS NOT NULL;
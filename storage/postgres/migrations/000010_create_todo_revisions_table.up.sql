@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS todo_revisions (
+    id UUID PRIMARY KEY,
+    -- no foreign key, the history outlives purged todos
+    todo_id UUID NOT NULL,
+    revision BIGINT NOT NULL,
+    action SMALLINT NOT NULL,
+    actor_id VARCHAR(64),
+    changes JSONB NOT NULL DEFAULT '[]',
+    todo JSONB NOT NULL,
+    reverted_to BIGINT NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (todo_id, revision)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS todo_reminders;
+ALTER TABLE todos DROP COLUMN IF EXISTS remind_before;
@@ -0,0 +1,12 @@
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS remind_before BIGINT[] NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS todo_reminders (
+    -- <todo_id>/<remind_before>
+    id VARCHAR(128) PRIMARY KEY,
+    todo_id UUID NOT NULL REFERENCES todos (id) ON DELETE CASCADE,
+    remind_before BIGINT NOT NULL,
+    remind_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS todo_reminders_todo_id_idx ON todo_reminders (todo_id);
+CREATE INDEX IF NOT EXISTS todo_reminders_remind_at_idx ON todo_reminders (remind_at, id);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS feed_tokens (
+    user_id VARCHAR(64) PRIMARY KEY,
+    -- sha256 of the token, the token itself is only shown once
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS todos_tags_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS tags;
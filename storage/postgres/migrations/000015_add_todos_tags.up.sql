@@ -0,0 +1,14 @@
+-- the #tags in the title and the description, lowercased, as
+-- repo.ParseTags finds them
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE todos SET tags = ARRAY(
+    SELECT DISTINCT '#' || lower(m[1])
+    FROM regexp_matches(
+        task_name || E'\n' || description,
+        '(?:^|[^A-Za-z0-9_&#])#([A-Za-z0-9_](?:[A-Za-z0-9_-]*[A-Za-z0-9_])?)',
+        'g'
+    ) AS m
+);
+
+CREATE INDEX IF NOT EXISTS todos_tags_idx ON todos USING GIN (tags);
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"database/sql"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+const reminderColumns = `id, todo_id, remind_before, remind_at`
+
+type reminderRepo struct {
+	db *sql.DB
+}
+
+// NewReminderRepo ...
+func NewReminderRepo(db *sql.DB) repo.ReminderStorageI {
+	return &reminderRepo{
+		db: db,
+	}
+}
+
+func (r *reminderRepo) Put(reminder *pb.TodoReminder) error {
+	_, err := r.db.Exec(`
+		INSERT INTO todo_reminders (id, todo_id, remind_before, remind_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			remind_before = EXCLUDED.remind_before,
+			remind_at = EXCLUDED.remind_at`,
+		reminder.Id,
+		reminder.TodoId,
+		reminder.RemindBefore,
+		etc.NullTime(reminder.RemindAt),
+	)
+
+	return handleError(err)
+}
+
+func (r *reminderRepo) Get(id string) (*pb.TodoReminder, error) {
+	reminder, err := scanReminder(r.db.QueryRow(`
+		SELECT `+reminderColumns+`
+		FROM todo_reminders
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return reminder, nil
+}
+
+func (r *reminderRepo) GetAll(todoID string) ([]*pb.TodoReminder, error) {
+	return r.query(`
+		SELECT `+reminderColumns+`
+		FROM todo_reminders
+		WHERE todo_id = $1
+		ORDER BY remind_at, id`,
+		todoID,
+	)
+}
+
+func (r *reminderRepo) GetPending() ([]*pb.TodoReminder, error) {
+	return r.query(`
+		SELECT ` + reminderColumns + `
+		FROM todo_reminders
+		ORDER BY remind_at, id`,
+	)
+}
+
+func (r *reminderRepo) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM todo_reminders WHERE id = $1`, id)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func (r *reminderRepo) query(query string, args ...interface{}) ([]*pb.TodoReminder, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	defer rows.Close()
+
+	var reminders []*pb.TodoReminder
+	for rows.Next() {
+		reminder, err := scanReminder(rows)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
+
+func scanReminder(row scanner) (*pb.TodoReminder, error) {
+	var (
+		reminder pb.TodoReminder
+		remindAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&reminder.Id,
+		&reminder.TodoId,
+		&reminder.RemindBefore,
+		&remindAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	reminder.RemindAt = etc.TimestampValue(remindAt)
+
+	return &reminder, nil
+}
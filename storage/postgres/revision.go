@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const revisionColumns = `id, todo_id, revision, action, actor_id, changes, todo, reverted_to, occurred_at`
+
+type revisionRepo struct {
+	db *sql.DB
+}
+
+// NewRevisionRepo ...
+func NewRevisionRepo(db *sql.DB) repo.RevisionStorageI {
+	return &revisionRepo{
+		db: db,
+	}
+}
+
+// Create numbers the revision after the last one of the todo. The todo row
+// is locked while numbering, so concurrent changes to a todo take turns
+// instead of failing on the unique revision number.
+func (r *revisionRepo) Create(rev *pb.TodoRevision) (*pb.TodoRevision, error) {
+	changes, err := json.Marshal(rev.Changes)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := protojson.Marshal(rev.Todo)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var locked string
+	err = tx.QueryRow(`SELECT id FROM todos WHERE id = $1 FOR UPDATE`, rev.TodoId).Scan(&locked)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	res, err := scanRevision(tx.QueryRow(`
+		INSERT INTO todo_revisions (`+revisionColumns+`)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, $4, $5, $6, $7, COALESCE($8, NOW())
+		FROM todo_revisions
+		WHERE todo_id = $2
+		RETURNING `+revisionColumns,
+		rev.Id,
+		rev.TodoId,
+		rev.Action,
+		nullIfEmpty(rev.ActorId),
+		string(changes),
+		string(todo),
+		rev.RevertedTo,
+		etc.NullTime(rev.OccurredAt),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *revisionRepo) GetAll(todoID string) ([]*pb.TodoRevision, error) {
+	rows, err := r.db.Query(`
+		SELECT `+revisionColumns+`
+		FROM todo_revisions
+		WHERE todo_id = $1
+		ORDER BY revision`,
+		todoID,
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	defer rows.Close()
+
+	var revisions []*pb.TodoRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
+}
+
+func scanRevision(row scanner) (*pb.TodoRevision, error) {
+	var (
+		rev        pb.TodoRevision
+		actorID    sql.NullString
+		changes    []byte
+		todo       []byte
+		occurredAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&rev.Id,
+		&rev.TodoId,
+		&rev.Revision,
+		&rev.Action,
+		&actorID,
+		&changes,
+		&todo,
+		&rev.RevertedTo,
+		&occurredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rev.ActorId = etc.StringValue(actorID).GetValue()
+	rev.OccurredAt = etc.TimestampValue(occurredAt)
+
+	if err = json.Unmarshal(changes, &rev.Changes); err != nil {
+		return nil, err
+	}
+	rev.Todo = &pb.TodoModel{}
+	if err = protojson.Unmarshal(todo, rev.Todo); err != nil {
+		return nil, err
+	}
+
+	return &rev, nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+func TestRevisionCreateLocksTodo(t *testing.T) {
+	const todoID = "7c6c7b8e-0f4e-4bb7-8d3e-3d6f1b0a8a01"
+	rev := &pb.TodoRevision{
+		Id:      "rev-2",
+		TodoId:  todoID,
+		Action:  pb.RevisionAction_REVISION_UPDATED,
+		ActorId: "user-1",
+		Todo:    &pb.TodoModel{Id: todoID, TaskName: "pay rent"},
+	}
+
+	tests := []struct {
+		name string
+		// locked is whether the todo row is there to lock
+		locked bool
+		err    error
+	}{
+		{name: "todo", locked: true},
+		{name: "missing todo", err: repo.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+
+			// the revision is numbered while the todo is locked
+			fake.expect(`BEGIN`)
+			lock := fake.expect(`SELECT id FROM todos WHERE id = $1 FOR UPDATE`, todoID)
+			if tt.locked {
+				lock.returns([]string{"id"}, []driver.Value{todoID})
+				fake.expect(`COALESCE(MAX(revision), 0) + 1`).returns(strings.Split(revisionColumns, ", "), []driver.Value{
+					rev.Id, todoID, int64(2), int64(rev.Action), rev.ActorId,
+					[]byte(`[]`), []byte(`{"id":"` + todoID + `","taskName":"pay rent"}`), int64(0), createdAt,
+				})
+				fake.expect(`COMMIT`)
+			} else {
+				lock.returns([]string{"id"})
+				fake.expect(`ROLLBACK`)
+			}
+
+			res, err := NewRevisionRepo(db).Create(rev)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && (res.Revision != 2 || res.Todo.TaskName != "pay rent") {
+				t.Errorf("created revision %d of %q, want 2 of %q", res.Revision, res.Todo.TaskName, "pay rent")
+			}
+		})
+	}
+}
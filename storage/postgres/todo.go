@@ -0,0 +1,392 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// pqInvalidTextRepresentation is returned by postgres for a malformed
+	// uuid, which for us just means there is no such todo
+	pqInvalidTextRepresentation = "22P02"
+)
+
+const todoColumns = `id, task_name, task_status, user_id, description, priority, due_at, created_at, updated_at, workspace_id, updated_by, parent_id, list_id, deleted_at, version, recurrence, remind_before`
+
+type todoRepo struct {
+	db *sql.DB
+}
+
+// NewTodoRepo ...
+func NewTodoRepo(db *sql.DB) repo.TodoStorageI {
+	return &todoRepo{
+		db: db,
+	}
+}
+
+func (r *todoRepo) Create(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	recurrence, err := marshalRecurrence(todo.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO todos (id, task_name, task_status, user_id, description, priority, due_at, created_at, updated_at, workspace_id, updated_by, parent_id, list_id, deleted_at, version, recurrence, remind_before, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, NOW()), COALESCE($9, NOW()), $10, $11, $12, $13, $14, $15, $16, COALESCE($17, '{}'), COALESCE($18, '{}'))`,
+		todo.Id,
+		todo.TaskName,
+		todo.TaskStatus,
+		nullIfEmpty(todo.UserId),
+		todo.Description,
+		todo.Priority,
+		etc.NullTime(todo.DueAt),
+		etc.NullTime(todo.CreatedAt),
+		etc.NullTime(todo.UpdatedAt),
+		nullIfEmpty(todo.WorkspaceId),
+		nullIfEmpty(todo.UpdatedBy),
+		nullIfEmpty(todo.ParentId),
+		nullIfEmpty(todo.ListId),
+		etc.NullTime(todo.DeletedAt),
+		todo.Version,
+		recurrence,
+		pq.Array(todo.RemindBefore),
+		pq.Array(repo.TodoTags(todo)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(todo.Id)
+}
+
+func (r *todoRepo) Get(id string) (*pb.TodoModel, error) {
+	todo, err := scanTodo(r.db.QueryRow(`
+		SELECT `+todoColumns+`
+		FROM todos
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return todo, nil
+}
+
+func (r *todoRepo) GetAll(req *pb.ListTodosRequest) ([]*pb.TodoModel, int64, error) {
+	var (
+		filter = " WHERE deleted_at IS NULL"
+		args   []interface{}
+		count  int64
+	)
+
+	if req.Trashed {
+		filter = " WHERE deleted_at IS NOT NULL"
+	}
+
+	if req.Search != "" {
+		args = append(args, req.Search)
+		n := "$" + strconv.Itoa(len(args))
+		filter += " AND (task_name ILIKE '%' || " + n + " || '%' OR description ILIKE '%' || " + n + " || '%')"
+	}
+
+	if req.ParentId != "" {
+		args = append(args, req.ParentId)
+		filter += " AND parent_id = $" + strconv.Itoa(len(args))
+	}
+
+	if req.ListId != "" {
+		args = append(args, req.ListId)
+		filter += " AND list_id = $" + strconv.Itoa(len(args))
+	}
+
+	cond, args, err := whereFilter(req.Filter, repo.TodoFilterFields, todoFilterColumns, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter += cond
+
+	if req.WithCount {
+		err := r.db.QueryRow(`SELECT count(*) FROM todos`+filter, args...).Scan(&count)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// after only skips the rows of earlier pages, count is the total
+	after, args, err := whereFilter(req.After, repo.TodoFilterFields, todoFilterColumns, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + todoColumns + ` FROM todos` + filter + after + orderBy(req.Sort, todoSortColumns)
+	if req.Limit > 0 {
+		page := req.Page
+		if page <= 0 {
+			page = 1
+		}
+		args = append(args, req.Limit, (page-1)*req.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var todos []*pb.TodoModel
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, count, rows.Err()
+}
+
+func (r *todoRepo) Update(todo *pb.TodoModel) (*pb.TodoModel, error) {
+	recurrence, err := marshalRecurrence(todo.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.db.Exec(`
+		UPDATE todos SET
+			task_name = $2,
+			task_status = $3,
+			description = $4,
+			priority = $5,
+			due_at = $6,
+			updated_at = COALESCE($7, NOW()),
+			updated_by = $8,
+			parent_id = $9,
+			list_id = $10,
+			deleted_at = $11,
+			recurrence = $13,
+			remind_before = COALESCE($14, '{}'),
+			tags = COALESCE($15, '{}'),
+			version = version + 1
+		WHERE id = $1 AND version = $12`,
+		todo.Id,
+		todo.TaskName,
+		todo.TaskStatus,
+		todo.Description,
+		todo.Priority,
+		etc.NullTime(todo.DueAt),
+		etc.NullTime(todo.UpdatedAt),
+		nullIfEmpty(todo.UpdatedBy),
+		nullIfEmpty(todo.ParentId),
+		nullIfEmpty(todo.ListId),
+		etc.NullTime(todo.DeletedAt),
+		todo.Version,
+		recurrence,
+		pq.Array(todo.RemindBefore),
+		pq.Array(repo.TodoTags(todo)),
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	// no row is updated for a missing todo and for a stale version
+	if err = checkAffected(res); err != nil {
+		if _, err = r.Get(todo.Id); err != nil {
+			return nil, err
+		}
+		return nil, repo.ErrVersionConflict
+	}
+
+	return r.Get(todo.Id)
+}
+
+func (r *todoRepo) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM todos WHERE id = $1`, id)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func (r *todoRepo) SubtaskProgress(parentIDs []string) (map[string]*pb.SubtaskProgress, error) {
+	res := make(map[string]*pb.SubtaskProgress)
+	if len(parentIDs) == 0 {
+		return res, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT parent_id, count(*) FILTER (WHERE task_status = $2), count(*)
+		FROM todos
+		WHERE parent_id = ANY($1::uuid[]) AND deleted_at IS NULL AND task_status <> $3
+		GROUP BY parent_id`,
+		pq.Array(parentIDs),
+		pb.TaskStatus_TASK_STATUS_DONE,
+		pb.TaskStatus_TASK_STATUS_CANCELLED,
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			parentID string
+			progress pb.SubtaskProgress
+		)
+		if err = rows.Scan(&parentID, &progress.Done, &progress.Total); err != nil {
+			return nil, err
+		}
+		res[parentID] = &progress
+	}
+
+	return res, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTodo(row scanner) (*pb.TodoModel, error) {
+	var (
+		todo        pb.TodoModel
+		userID      sql.NullString
+		dueAt       sql.NullTime
+		createdAt   sql.NullTime
+		updatedAt   sql.NullTime
+		workspaceID sql.NullString
+		updatedBy   sql.NullString
+		parentID    sql.NullString
+		listID      sql.NullString
+		deletedAt   sql.NullTime
+		recurrence  []byte
+	)
+
+	err := row.Scan(
+		&todo.Id,
+		&todo.TaskName,
+		&todo.TaskStatus,
+		&userID,
+		&todo.Description,
+		&todo.Priority,
+		&dueAt,
+		&createdAt,
+		&updatedAt,
+		&workspaceID,
+		&updatedBy,
+		&parentID,
+		&listID,
+		&deletedAt,
+		&todo.Version,
+		&recurrence,
+		pq.Array(&todo.RemindBefore),
+	)
+	if err != nil {
+		return nil, err
+	}
+	todo.UserId = etc.StringValue(userID).GetValue()
+	todo.DueAt = etc.TimestampValue(dueAt)
+	todo.CreatedAt = etc.TimestampValue(createdAt)
+	todo.UpdatedAt = etc.TimestampValue(updatedAt)
+	todo.WorkspaceId = etc.StringValue(workspaceID).GetValue()
+	todo.UpdatedBy = etc.StringValue(updatedBy).GetValue()
+	todo.ParentId = etc.StringValue(parentID).GetValue()
+	todo.ListId = etc.StringValue(listID).GetValue()
+	todo.DeletedAt = etc.TimestampValue(deletedAt)
+
+	if recurrence != nil {
+		todo.Recurrence = &pb.Recurrence{}
+		if err = protojson.Unmarshal(recurrence, todo.Recurrence); err != nil {
+			return nil, err
+		}
+	}
+
+	return &todo, nil
+}
+
+// marshalRecurrence returns the jsonb value of a recurrence, NULL for none
+func marshalRecurrence(rec *pb.Recurrence) (sql.NullString, error) {
+	if rec == nil {
+		return sql.NullString{}, nil
+	}
+
+	data, err := protojson.Marshal(rec)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+// todoSortColumns are the columns of repo.TodoSortFields
+var todoSortColumns = map[string]string{
+	"task_name":   "task_name",
+	"task_status": "task_status",
+	"priority":    "priority",
+	"due_at":      "due_at",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+	"deleted_at":  "deleted_at",
+}
+
+// orderBy turns sort fields, or repo.DefaultSort without any, into an
+// ORDER BY clause of their columns, ties are ordered by id. Only fields in
+// columns are used.
+func orderBy(fields []*pb.SortField, columns map[string]string) string {
+	if len(fields) == 0 {
+		fields = repo.DefaultSort
+	}
+
+	keys := make([]string, 0, len(fields)+1)
+	for _, f := range fields {
+		column, ok := columns[f.Field]
+		if !ok {
+			continue
+		}
+		if f.Desc {
+			keys = append(keys, column+" DESC")
+		} else {
+			keys = append(keys, column+" ASC")
+		}
+	}
+
+	return " ORDER BY " + strings.Join(append(keys, "id"), ", ")
+}
+
+func handleError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.ErrNotFound
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqInvalidTextRepresentation {
+		return repo.ErrNotFound
+	}
+
+	return err
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func nullIfEmpty(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+const todoListColumns = `id, name, description, user_id, inbox, archived_at, created_at, updated_at, deleted_at`
+
+type todoListRepo struct {
+	db *sql.DB
+}
+
+// NewTodoListRepo ...
+func NewTodoListRepo(db *sql.DB) repo.TodoListStorageI {
+	return &todoListRepo{
+		db: db,
+	}
+}
+
+func (r *todoListRepo) Create(list *pb.TodoList) (*pb.TodoList, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO todo_lists (id, name, description, user_id, inbox, archived_at, created_at, updated_at, deleted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()), COALESCE($8, NOW()), $9)`,
+		list.Id,
+		list.Name,
+		list.Description,
+		nullIfEmpty(list.UserId),
+		list.Inbox,
+		etc.NullTime(list.ArchivedAt),
+		etc.NullTime(list.CreatedAt),
+		etc.NullTime(list.UpdatedAt),
+		etc.NullTime(list.DeletedAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(list.Id)
+}
+
+func (r *todoListRepo) Get(id string) (*pb.TodoList, error) {
+	list, err := scanTodoList(r.db.QueryRow(`
+		SELECT `+todoListColumns+`
+		FROM todo_lists
+		WHERE id = $1`,
+		id,
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return list, nil
+}
+
+func (r *todoListRepo) GetInbox(userID string) (*pb.TodoList, error) {
+	list, err := scanTodoList(r.db.QueryRow(`
+		SELECT `+todoListColumns+`
+		FROM todo_lists
+		WHERE inbox AND user_id IS NOT DISTINCT FROM $1`,
+		nullIfEmpty(userID),
+	))
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	return list, nil
+}
+
+// todoListSortColumns are the columns of repo.TodoListSortFields
+var todoListSortColumns = map[string]string{
+	"name":       "name",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"deleted_at": "deleted_at",
+}
+
+func (r *todoListRepo) GetAll(req *pb.ListTodoListsRequest) ([]*pb.TodoList, int64, error) {
+	var (
+		filter = " WHERE deleted_at IS NULL"
+		args   []interface{}
+		count  int64
+	)
+
+	if req.Trashed {
+		filter = " WHERE deleted_at IS NOT NULL"
+	}
+
+	if !req.IncludeArchived {
+		filter += " AND archived_at IS NULL"
+	}
+
+	if req.UserId != "" {
+		args = append(args, req.UserId)
+		filter += " AND user_id = $" + strconv.Itoa(len(args))
+	}
+
+	if req.Search != "" {
+		args = append(args, req.Search)
+		filter += " AND name ILIKE '%' || $" + strconv.Itoa(len(args)) + " || '%'"
+	}
+
+	cond, args, err := whereFilter(req.Filter, repo.TodoListFilterFields, todoListFilterColumns, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter += cond
+
+	if req.WithCount {
+		err := r.db.QueryRow(`SELECT count(*) FROM todo_lists`+filter, args...).Scan(&count)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// after only skips the rows of earlier pages, count is the total
+	after, args, err := whereFilter(req.After, repo.TodoListFilterFields, todoListFilterColumns, args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + todoListColumns + ` FROM todo_lists` + filter + after + orderBy(req.Sort, todoListSortColumns)
+	if req.Limit > 0 {
+		page := req.Page
+		if page <= 0 {
+			page = 1
+		}
+		args = append(args, req.Limit, (page-1)*req.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var lists []*pb.TodoList
+	for rows.Next() {
+		list, err := scanTodoList(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		lists = append(lists, list)
+	}
+
+	return lists, count, rows.Err()
+}
+
+func (r *todoListRepo) Update(list *pb.TodoList) (*pb.TodoList, error) {
+	res, err := r.db.Exec(`
+		UPDATE todo_lists SET
+			name = $2,
+			description = $3,
+			archived_at = $4,
+			updated_at = COALESCE($5, NOW()),
+			deleted_at = $6
+		WHERE id = $1`,
+		list.Id,
+		list.Name,
+		list.Description,
+		etc.NullTime(list.ArchivedAt),
+		etc.NullTime(list.UpdatedAt),
+		etc.NullTime(list.DeletedAt),
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+
+	if err = checkAffected(res); err != nil {
+		return nil, err
+	}
+
+	return r.Get(list.Id)
+}
+
+func (r *todoListRepo) Delete(id string) error {
+	res, err := r.db.Exec(`DELETE FROM todo_lists WHERE id = $1`, id)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return checkAffected(res)
+}
+
+func scanTodoList(row scanner) (*pb.TodoList, error) {
+	var (
+		list       pb.TodoList
+		userID     sql.NullString
+		archivedAt sql.NullTime
+		createdAt  sql.NullTime
+		updatedAt  sql.NullTime
+		deletedAt  sql.NullTime
+	)
+
+	err := row.Scan(
+		&list.Id,
+		&list.Name,
+		&list.Description,
+		&userID,
+		&list.Inbox,
+		&archivedAt,
+		&createdAt,
+		&updatedAt,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	list.UserId = etc.StringValue(userID).GetValue()
+	list.ArchivedAt = etc.TimestampValue(archivedAt)
+	list.CreatedAt = etc.TimestampValue(createdAt)
+	list.UpdatedAt = etc.TimestampValue(updatedAt)
+	list.DeletedAt = etc.TimestampValue(deletedAt)
+
+	return &list, nil
+}
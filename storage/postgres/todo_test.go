@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+	"github.com/lib/pq"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	todoColumnNames = strings.Split(todoColumns, ", ")
+	createdAt       = time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func todoRow(id string, version int64) []driver.Value {
+	return []driver.Value{
+		id, "pay rent", int64(pb.TaskStatus_TASK_STATUS_TODO), "user-1", "", int64(0),
+		nil, createdAt, createdAt, nil, nil, nil, nil, nil,
+		version, nil, []byte("{3600}"),
+	}
+}
+
+func TestTodoUpdateVersion(t *testing.T) {
+	const id = "7c6c7b8e-0f4e-4bb7-8d3e-3d6f1b0a8a01"
+	todo := &pb.TodoModel{Id: id, TaskName: "pay rent", Version: 3, RemindBefore: []int64{3600}}
+
+	tests := []struct {
+		name string
+		// affected is the number of rows the update matches
+		affected int64
+		// current is the stored todo, nil when there is none
+		current []driver.Value
+		version int64
+		err     error
+	}{
+		{name: "current version", affected: 1, current: todoRow(id, 4), version: 4},
+		{name: "stale version", affected: 0, current: todoRow(id, 5), err: repo.ErrVersionConflict},
+		{name: "missing todo", affected: 0, err: repo.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+
+			// the update only matches the version it was read at
+			fake.expect(`version = version + 1 WHERE id = $1 AND version = $12`,
+				id, "pay rent", int64(0), "", int64(0), nil, nil, nil, nil, nil, nil, int64(3), nil, "{3600}", nil,
+			).affects(tt.affected)
+			get := fake.expect(`SELECT `+todoColumns+` FROM todos WHERE id = $1`, id)
+			if tt.current != nil {
+				get.returns(todoColumnNames, tt.current)
+			} else {
+				get.returns(todoColumnNames)
+			}
+
+			res, err := NewTodoRepo(db).Update(todo)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Update() error = %v, want %v", err, tt.err)
+			}
+			if err == nil && res.Version != tt.version {
+				t.Errorf("version = %d, want %d", res.Version, tt.version)
+			}
+		})
+	}
+}
+
+func TestTodoGetMalformedID(t *testing.T) {
+	db, fake := newFakeDB(t)
+	fake.expect(`FROM todos WHERE id = $1`, "not-a-uuid").
+		fails(&pq.Error{Code: pqInvalidTextRepresentation, Message: `invalid input syntax for type uuid: "not-a-uuid"`})
+	fake.expect(`DELETE FROM todos WHERE id = $1`, "not-a-uuid").
+		fails(&pq.Error{Code: pqInvalidTextRepresentation})
+
+	r := NewTodoRepo(db)
+	if _, err := r.Get("not-a-uuid"); !errors.Is(err, repo.ErrNotFound) {
+		t.Errorf("Get() error = %v, want %v", err, repo.ErrNotFound)
+	}
+	if err := r.Delete("not-a-uuid"); !errors.Is(err, repo.ErrNotFound) {
+		t.Errorf("Delete() error = %v, want %v", err, repo.ErrNotFound)
+	}
+}
+
+func TestHandleError(t *testing.T) {
+	other := &pq.Error{Code: "23505"}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"no rows", sql.ErrNoRows, repo.ErrNotFound},
+		{"invalid text representation", &pq.Error{Code: pqInvalidTextRepresentation}, repo.ErrNotFound},
+		{"other postgres error", other, other},
+		{"other error", sql.ErrConnDone, sql.ErrConnDone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handleError(tt.err); got != tt.want {
+				t.Errorf("handleError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func condition(field string, op pb.FilterCondition_Operator, values ...interface{}) *pb.FilterExpr {
+	c := &pb.FilterCondition{Field: field, Op: op}
+	for _, v := range values {
+		c.Values = append(c.Values, repo.FilterValueOf(v))
+	}
+	return &pb.FilterExpr{Expr: &pb.FilterExpr_Condition{Condition: c}}
+}
+
+func TestTodoGetAll(t *testing.T) {
+	after := timestamppb.New(createdAt)
+
+	tests := []struct {
+		name  string
+		req   *pb.ListTodosRequest
+		count string
+		query string
+		args  []driver.Value
+	}{
+		{
+			name:  "everything",
+			req:   &pb.ListTodosRequest{WithCount: true},
+			count: `SELECT count(*) FROM todos WHERE deleted_at IS NULL`,
+			query: `FROM todos WHERE deleted_at IS NULL ORDER BY created_at ASC, id`,
+			args:  []driver.Value{},
+		},
+		{
+			name:  "without count",
+			req:   &pb.ListTodosRequest{Limit: 10},
+			query: `FROM todos WHERE deleted_at IS NULL ORDER BY created_at ASC, id LIMIT $1 OFFSET $2`,
+			args:  []driver.Value{int64(10), int64(0)},
+		},
+		{
+			name: "filter and page",
+			req: &pb.ListTodosRequest{
+				ListId: "inbox",
+				Filter: &pb.FilterExpr{Expr: &pb.FilterExpr_Or{Or: &pb.FilterOperands{Operands: []*pb.FilterExpr{
+					condition("task_status", pb.FilterCondition_IN, int64(1), int64(2)),
+					{Expr: &pb.FilterExpr_Not{Not: condition("task_name", pb.FilterCondition_EQ, "rent")}},
+				}}}},
+				Sort:      []*pb.SortField{{Field: "priority", Desc: true}},
+				Limit:     10,
+				Page:      3,
+				WithCount: true,
+			},
+			count: `SELECT count(*) FROM todos WHERE deleted_at IS NULL AND list_id = $1 AND (task_status = ANY($2) OR NOT (COALESCE(task_name, '') = $3))`,
+			query: `AND (task_status = ANY($2) OR NOT (COALESCE(task_name, '') = $3)) ORDER BY priority DESC, id LIMIT $4 OFFSET $5`,
+			args:  []driver.Value{"inbox", "{1,2}", "rent", int64(10), int64(20)},
+		},
+		{
+			name: "tags",
+			req: &pb.ListTodosRequest{
+				Filter: &pb.FilterExpr{Expr: &pb.FilterExpr_And{And: &pb.FilterOperands{Operands: []*pb.FilterExpr{
+					condition("tag", pb.FilterCondition_EQ, "Ops"),
+					condition("tag", pb.FilterCondition_NOT_IN, "home", "#errands"),
+				}}}},
+			},
+			query: `FROM todos WHERE deleted_at IS NULL AND ($1 = ANY(tags) AND NOT (tags && $2::text[])) ORDER BY created_at ASC, id`,
+			args:  []driver.Value{"#ops", `{"#home","#errands"}`},
+		},
+		{
+			name: "after a cursor",
+			req: &pb.ListTodosRequest{
+				Trashed:   true,
+				After:     condition("created_at", pb.FilterCondition_GT, after),
+				Limit:     2,
+				WithCount: true,
+			},
+			// the rows before the cursor are still counted
+			count: `SELECT count(*) FROM todos WHERE deleted_at IS NOT NULL`,
+			query: `FROM todos WHERE deleted_at IS NOT NULL AND COALESCE(created_at > $1, FALSE) ORDER BY created_at ASC, id LIMIT $2 OFFSET $3`,
+			args:  []driver.Value{createdAt, int64(2), int64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, fake := newFakeDB(t)
+			want := int64(0)
+			if tt.count != "" {
+				want = 42
+				fake.expect(tt.count).returns([]string{"count"}, []driver.Value{want})
+			}
+			fake.expect(tt.query, tt.args...).returns(todoColumnNames, todoRow("a", 1), todoRow("b", 1))
+
+			todos, count, err := NewTodoRepo(db).GetAll(tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != want {
+				t.Errorf("count = %d, want %d", count, want)
+			}
+			if len(todos) != 2 || todos[0].Id != "a" || todos[1].Id != "b" {
+				t.Errorf("got todos %v, want a and b", todos)
+			}
+			if got := todos[0].RemindBefore; len(got) != 1 || got[0] != 3600 {
+				t.Errorf("remind_before = %v, want [3600]", got)
+			}
+		})
+	}
+}
+
+func TestTodoGetAllInvalidFilter(t *testing.T) {
+	// nothing is queried
+	db, _ := newFakeDB(t)
+
+	_, _, err := NewTodoRepo(db).GetAll(&pb.ListTodosRequest{
+		Filter: condition("secret", pb.FilterCondition_EQ, "x"),
+	})
+	if !errors.Is(err, repo.ErrInvalidFilter) {
+		t.Errorf("GetAll() error = %v, want %v", err, repo.ErrInvalidFilter)
+	}
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"github.com/abdukhashimov/go_gin_example/pkg/etc"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+type transitionRepo struct {
+	db *sql.DB
+}
+
+// NewTransitionRepo ...
+func NewTransitionRepo(db *sql.DB) repo.TransitionStorageI {
+	return &transitionRepo{
+		db: db,
+	}
+}
+
+func (r *transitionRepo) Create(t *pb.TodoTransition) (*pb.TodoTransition, error) {
+	_, err := r.db.Exec(`
+		INSERT INTO todo_transitions (id, todo_id, from_status, to_status, actor_id, comment, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7, NOW()))`,
+		t.Id,
+		t.TodoId,
+		t.From,
+		t.To,
+		nullIfEmpty(t.ActorId),
+		t.Comment,
+		etc.NullTime(t.OccurredAt),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r *transitionRepo) GetAll(todoID string) ([]*pb.TodoTransition, error) {
+	rows, err := r.db.Query(`
+		SELECT id, todo_id, from_status, to_status, actor_id, comment, occurred_at
+		FROM todo_transitions
+		WHERE todo_id = $1
+		ORDER BY occurred_at, id`,
+		todoID,
+	)
+	if err != nil {
+		return nil, handleError(err)
+	}
+	defer rows.Close()
+
+	var transitions []*pb.TodoTransition
+	for rows.Next() {
+		var (
+			t          pb.TodoTransition
+			actorID    sql.NullString
+			occurredAt sql.NullTime
+		)
+
+		err = rows.Scan(&t.Id, &t.TodoId, &t.From, &t.To, &actorID, &t.Comment, &occurredAt)
+		if err != nil {
+			return nil, err
+		}
+		t.ActorId = etc.StringValue(actorID).GetValue()
+		t.OccurredAt = etc.TimestampValue(occurredAt)
+
+		transitions = append(transitions, &t)
+	}
+
+	return transitions, rows.Err()
+}
@@ -0,0 +1,19 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// AttachmentStorageI keeps attachment metadata, see storage/blob for the
+// content
+type AttachmentStorageI interface {
+	Create(attachment *pb.TodoAttachment) (*pb.TodoAttachment, error)
+	Get(id string) (*pb.TodoAttachment, error)
+	// GetAll returns the attachments of a todo, oldest first
+	GetAll(todoID string) ([]*pb.TodoAttachment, error)
+	Delete(id string) error
+	// UsedBytes returns the total size of the attachments a user uploaded
+	UsedBytes(userID string) (int64, error)
+	// References returns the number of attachments with the content sum
+	References(sha256 string) (int64, error)
+}
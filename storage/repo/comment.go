@@ -0,0 +1,18 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// CommentStorageI ...
+type CommentStorageI interface {
+	Create(comment *pb.TodoComment) (*pb.TodoComment, error)
+	Get(id string) (*pb.TodoComment, error)
+	// GetAll returns a page of the comments matching req, oldest first,
+	// and, with req.WithCount, how many match in total
+	GetAll(req *pb.ListTodoCommentsRequest) ([]*pb.TodoComment, int64, error)
+	Update(comment *pb.TodoComment) (*pb.TodoComment, error)
+	Delete(id string) error
+	// DeleteAll deletes every comment of a todo
+	DeleteAll(todoID string) error
+}
@@ -0,0 +1,15 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// FeedTokenStorageI keeps the calendar feed tokens of users, a user has at
+// most one
+type FeedTokenStorageI interface {
+	// Put stores the token of its user, replacing the previous one
+	Put(*pb.FeedToken) error
+	Get(userID string) (*pb.FeedToken, error)
+	GetByHash(tokenHash string) (*pb.FeedToken, error)
+	Delete(userID string) error
+}
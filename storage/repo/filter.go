@@ -0,0 +1,251 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FilterKind is the type of a field filters compare
+type FilterKind int
+
+// Kinds of filterable fields. Strings and ids compare with every operator,
+// a null string is an empty one and ids are never null. Enums compare by
+// number with every operator. Times compare with everything but IN, null
+// matches unset times. Tags are the #tags of a record, = and IN match
+// records with any of the tags, with or without the #.
+const (
+	FilterString FilterKind = iota
+	FilterEnum
+	FilterTime
+	FilterID
+	FilterTag
+)
+
+// maxFilterDepth limits how deep filter expressions nest
+const maxFilterDepth = 64
+
+// ErrInvalidFilter is returned for filters which don't fit the fields they
+// compare
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// TodoFilterFields are the fields GetAll filters todos by
+var TodoFilterFields = map[string]FilterKind{
+	"id":           FilterID,
+	"task_name":    FilterString,
+	"description":  FilterString,
+	"task_status":  FilterEnum,
+	"priority":     FilterEnum,
+	"due_at":       FilterTime,
+	"created_at":   FilterTime,
+	"updated_at":   FilterTime,
+	"user_id":      FilterString,
+	"workspace_id": FilterString,
+	"updated_by":   FilterString,
+	"parent_id":    FilterString,
+	"list_id":      FilterString,
+	"deleted_at":   FilterTime,
+	"tag":          FilterTag,
+}
+
+// TodoListFilterFields are the fields GetAll filters lists by
+var TodoListFilterFields = map[string]FilterKind{
+	"id":          FilterID,
+	"name":        FilterString,
+	"description": FilterString,
+	"user_id":     FilterString,
+	"archived_at": FilterTime,
+	"created_at":  FilterTime,
+	"updated_at":  FilterTime,
+	"deleted_at":  FilterTime,
+}
+
+// CommentFilterFields are the fields GetAll filters comments by
+var CommentFilterFields = map[string]FilterKind{
+	"id":         FilterID,
+	"created_at": FilterTime,
+}
+
+// TodoField returns a field of TodoFilterFields of a todo, as a string, an
+// int64, a *timestamppb.Timestamp or the []string of its tags
+func TodoField(todo *pb.TodoModel, field string) interface{} {
+	switch field {
+	case "id":
+		return todo.Id
+	case "task_name":
+		return todo.TaskName
+	case "description":
+		return todo.Description
+	case "task_status":
+		return int64(todo.TaskStatus)
+	case "priority":
+		return int64(todo.Priority)
+	case "due_at":
+		return todo.DueAt
+	case "created_at":
+		return todo.CreatedAt
+	case "updated_at":
+		return todo.UpdatedAt
+	case "user_id":
+		return todo.UserId
+	case "workspace_id":
+		return todo.WorkspaceId
+	case "updated_by":
+		return todo.UpdatedBy
+	case "parent_id":
+		return todo.ParentId
+	case "list_id":
+		return todo.ListId
+	case "deleted_at":
+		return todo.DeletedAt
+	case "tag":
+		return TodoTags(todo)
+	}
+	return nil
+}
+
+// TodoListField is TodoField for TodoListFilterFields
+func TodoListField(list *pb.TodoList, field string) interface{} {
+	switch field {
+	case "id":
+		return list.Id
+	case "name":
+		return list.Name
+	case "description":
+		return list.Description
+	case "user_id":
+		return list.UserId
+	case "archived_at":
+		return list.ArchivedAt
+	case "created_at":
+		return list.CreatedAt
+	case "updated_at":
+		return list.UpdatedAt
+	case "deleted_at":
+		return list.DeletedAt
+	}
+	return nil
+}
+
+// CommentField is TodoField for CommentFilterFields
+func CommentField(comment *pb.TodoComment, field string) interface{} {
+	switch field {
+	case "id":
+		return comment.Id
+	case "created_at":
+		return comment.CreatedAt
+	}
+	return nil
+}
+
+// CheckFilter checks that expr only compares fields with values of their
+// kind, using operators the kind supports. A nil expr matches everything.
+func CheckFilter(expr *pb.FilterExpr, fields map[string]FilterKind) error {
+	if expr == nil {
+		return nil
+	}
+
+	return checkFilter(expr, fields, 0)
+}
+
+func checkFilter(expr *pb.FilterExpr, fields map[string]FilterKind, depth int) error {
+	if depth == maxFilterDepth {
+		return fmt.Errorf("%w: nested too deep", ErrInvalidFilter)
+	}
+
+	switch e := expr.GetExpr().(type) {
+	case *pb.FilterExpr_And:
+		return checkOperands(e.And.GetOperands(), fields, depth)
+	case *pb.FilterExpr_Or:
+		return checkOperands(e.Or.GetOperands(), fields, depth)
+	case *pb.FilterExpr_Not:
+		if e.Not == nil {
+			return fmt.Errorf("%w: not without operand", ErrInvalidFilter)
+		}
+		return checkFilter(e.Not, fields, depth+1)
+	case *pb.FilterExpr_Condition:
+		return checkCondition(e.Condition, fields)
+	}
+
+	return fmt.Errorf("%w: empty expression", ErrInvalidFilter)
+}
+
+func checkOperands(operands []*pb.FilterExpr, fields map[string]FilterKind, depth int) error {
+	if len(operands) == 0 {
+		return fmt.Errorf("%w: and or or without operands", ErrInvalidFilter)
+	}
+	for _, operand := range operands {
+		if err := checkFilter(operand, fields, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkCondition(c *pb.FilterCondition, fields map[string]FilterKind) error {
+	kind, ok := fields[c.Field]
+	if !ok {
+		return fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, c.Field)
+	}
+
+	switch c.Op {
+	case pb.FilterCondition_EQ, pb.FilterCondition_NE:
+	case pb.FilterCondition_LT, pb.FilterCondition_LE, pb.FilterCondition_GT, pb.FilterCondition_GE:
+		if kind == FilterTag {
+			return fmt.Errorf("%w: %s can't be compared with %s", ErrInvalidFilter, c.Field, c.Op)
+		}
+	case pb.FilterCondition_IN, pb.FilterCondition_NOT_IN:
+		if kind == FilterTime {
+			return fmt.Errorf("%w: %s can't be compared with %s", ErrInvalidFilter, c.Field, c.Op)
+		}
+	default:
+		return fmt.Errorf("%w: unknown operator %d", ErrInvalidFilter, c.Op)
+	}
+
+	in := c.Op == pb.FilterCondition_IN || c.Op == pb.FilterCondition_NOT_IN
+	if in && len(c.Values) == 0 || !in && len(c.Values) != 1 {
+		return fmt.Errorf("%w: wrong number of values for %s %s", ErrInvalidFilter, c.Field, c.Op)
+	}
+
+	for _, v := range c.Values {
+		var ok bool
+		switch v.GetValue().(type) {
+		case *pb.FilterValue_StringValue:
+			ok = kind == FilterString || kind == FilterID
+			if kind == FilterTag {
+				_, ok = Tag(v.GetStringValue())
+			}
+		case *pb.FilterValue_IntValue:
+			ok = kind == FilterEnum
+		case *pb.FilterValue_TimeValue:
+			ok = kind == FilterTime && v.GetTimeValue().IsValid()
+		case *pb.FilterValue_NullValue:
+			ok = (kind == FilterString || kind == FilterTime) && (c.Op == pb.FilterCondition_EQ || c.Op == pb.FilterCondition_NE)
+		}
+		if !ok {
+			return fmt.Errorf("%w: invalid value for %s %s", ErrInvalidFilter, c.Field, c.Op)
+		}
+	}
+
+	return nil
+}
+
+// FilterValueOf turns a field returned by TodoField, TodoListField or
+// CommentField into a filter value, unset times become null
+func FilterValueOf(field interface{}) *pb.FilterValue {
+	switch f := field.(type) {
+	case string:
+		return &pb.FilterValue{Value: &pb.FilterValue_StringValue{StringValue: f}}
+	case int64:
+		return &pb.FilterValue{Value: &pb.FilterValue_IntValue{IntValue: f}}
+	case *timestamppb.Timestamp:
+		if f != nil {
+			return &pb.FilterValue{Value: &pb.FilterValue_TimeValue{TimeValue: f}}
+		}
+	}
+
+	return &pb.FilterValue{Value: &pb.FilterValue_NullValue{NullValue: true}}
+}
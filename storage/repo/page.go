@@ -0,0 +1,83 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// DefaultSort is the order of GetAll without sort fields
+var DefaultSort = []*pb.SortField{{Field: "created_at"}}
+
+// After returns a filter matching the records which come after the record
+// with values for fields and id, in the order of fields with ties broken
+// by id. Unset times come last, and first when descending, like GetAll
+// sorts them.
+func After(fields []*pb.SortField, kinds map[string]FilterKind, values []*pb.FilterValue, id string) *pb.FilterExpr {
+	var (
+		branches []*pb.FilterExpr
+		equal    []*pb.FilterExpr
+	)
+	for i, f := range fields {
+		v := values[i]
+		if after := afterValue(f, kinds[f.Field], v); after != nil {
+			operands := append(append([]*pb.FilterExpr{}, equal...), after)
+			branches = append(branches, filterAnd(operands))
+		}
+		equal = append(equal, filterCondition(f.Field, pb.FilterCondition_EQ, v))
+	}
+
+	byID := filterCondition("id", pb.FilterCondition_GT, FilterValueOf(id))
+	branches = append(branches, filterAnd(append(equal, byID)))
+
+	return filterOr(branches)
+}
+
+// afterValue returns the condition for a field to come after v, nil when
+// nothing comes after it
+func afterValue(f *pb.SortField, kind FilterKind, v *pb.FilterValue) *pb.FilterExpr {
+	null := v.GetNullValue()
+	switch {
+	case null && f.Desc:
+		return filterCondition(f.Field, pb.FilterCondition_NE, v)
+	case null:
+		return nil
+	case f.Desc:
+		return filterCondition(f.Field, pb.FilterCondition_LT, v)
+	case kind == FilterTime:
+		return filterOr([]*pb.FilterExpr{
+			filterCondition(f.Field, pb.FilterCondition_GT, v),
+			filterCondition(f.Field, pb.FilterCondition_EQ, FilterValueOf(nil)),
+		})
+	}
+
+	return filterCondition(f.Field, pb.FilterCondition_GT, v)
+}
+
+func filterCondition(field string, op pb.FilterCondition_Operator, v *pb.FilterValue) *pb.FilterExpr {
+	return &pb.FilterExpr{
+		Expr: &pb.FilterExpr_Condition{Condition: &pb.FilterCondition{
+			Field:  field,
+			Op:     op,
+			Values: []*pb.FilterValue{v},
+		}},
+	}
+}
+
+func filterAnd(operands []*pb.FilterExpr) *pb.FilterExpr {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+
+	return &pb.FilterExpr{
+		Expr: &pb.FilterExpr_And{And: &pb.FilterOperands{Operands: operands}},
+	}
+}
+
+func filterOr(operands []*pb.FilterExpr) *pb.FilterExpr {
+	if len(operands) == 1 {
+		return operands[0]
+	}
+
+	return &pb.FilterExpr{
+		Expr: &pb.FilterExpr_Or{Or: &pb.FilterOperands{Operands: operands}},
+	}
+}
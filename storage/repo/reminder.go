@@ -0,0 +1,18 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// ReminderStorageI keeps the pending reminders of todos, so they survive a
+// restart of the scheduler
+type ReminderStorageI interface {
+	// Put stores a reminder, replacing the one with its id
+	Put(*pb.TodoReminder) error
+	Get(id string) (*pb.TodoReminder, error)
+	// GetAll returns the reminders of a todo
+	GetAll(todoID string) ([]*pb.TodoReminder, error)
+	// GetPending returns every reminder, earliest first
+	GetPending() ([]*pb.TodoReminder, error)
+	Delete(id string) error
+}
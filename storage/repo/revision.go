@@ -0,0 +1,13 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// RevisionStorageI is the history of changes to todos
+type RevisionStorageI interface {
+	// Create numbers the revision after the last one of its todo
+	Create(*pb.TodoRevision) (*pb.TodoRevision, error)
+	// GetAll returns the revisions of a todo, oldest first
+	GetAll(todoID string) ([]*pb.TodoRevision, error)
+}
@@ -0,0 +1,54 @@
+package repo
+
+import (
+	"regexp"
+	"strings"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+var (
+	// tagPattern matches #tag at the start of the text or after a
+	// character which can't be part of a word or an HTML entity
+	tagPattern = regexp.MustCompile(`(?:^|[^\w&#])#(\w(?:[\w-]*\w)?)`)
+	// tagNamePattern is a tag after the #
+	tagNamePattern = regexp.MustCompile(`^\w(?:[\w-]*\w)?$`)
+)
+
+// ParseTags returns the lowercased #tags in texts, each once, in order of
+// appearance
+func ParseTags(texts ...string) []string {
+	var (
+		tags []string
+		seen = make(map[string]bool)
+	)
+
+	for _, text := range texts {
+		for _, match := range tagPattern.FindAllStringSubmatch(text, -1) {
+			tag := "#" + strings.ToLower(match[1])
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// TodoTags returns the #tags in the title and the description of todo
+func TodoTags(todo *pb.TodoModel) []string {
+	return ParseTags(todo.TaskName, todo.Description)
+}
+
+// Tag returns s as ParseTags returns it, with or without the #, ok is
+// false if s is no tag
+func Tag(s string) (tag string, ok bool) {
+	name := strings.TrimPrefix(s, "#")
+	if !tagNamePattern.MatchString(name) {
+		return "", false
+	}
+
+	return "#" + strings.ToLower(name), true
+}
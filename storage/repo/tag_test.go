@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  []string
+	}{
+		{[]string{"#work"}, []string{"#work"}},
+		{[]string{"plan #Work and #home-office", "#work again"}, []string{"#work", "#home-office"}},
+		{[]string{"ends with a dash #todo-"}, []string{"#todo"}},
+		{[]string{"C# and &#35; and issue##2"}, nil},
+		{[]string{"(#quoted), #a"}, []string{"#quoted", "#a"}},
+		{[]string{"# alone", ""}, nil},
+	}
+	for _, tt := range tests {
+		if got := ParseTags(tt.texts...); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+}
+
+func TestTag(t *testing.T) {
+	tests := map[string]string{"work": "#work", "#Work": "#work", "home-office": "#home-office", "#_1": "#_1"}
+	for s, want := range tests {
+		if got, ok := Tag(s); !ok || got != want {
+			t.Errorf("Tag(%q) = %q, %v, want %q", s, got, ok, want)
+		}
+	}
+	for _, s := range []string{"", "#", "##work", "-work", "work-", "two words", "a,b"} {
+		if got, ok := Tag(s); ok {
+			t.Errorf("Tag(%q) = %q, want no tag", s, got)
+		}
+	}
+}
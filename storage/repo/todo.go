@@ -0,0 +1,34 @@
+package repo
+
+import (
+	"errors"
+
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+var (
+	// ErrNotFound is returned when the requested todo does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrVersionConflict is returned when a todo changed since the version
+	// an update is based on
+	ErrVersionConflict = errors.New("version conflict")
+)
+
+// TodoSortFields are the fields GetAll sorts todos by
+var TodoSortFields = []string{"task_name", "task_status", "priority", "due_at", "created_at", "updated_at", "deleted_at"}
+
+// TodoStorageI ...
+type TodoStorageI interface {
+	Create(todo *pb.TodoModel) (*pb.TodoModel, error)
+	Get(id string) (*pb.TodoModel, error)
+	// GetAll returns a page of the todos matching req and, with
+	// req.WithCount, how many match in total
+	GetAll(req *pb.ListTodosRequest) ([]*pb.TodoModel, int64, error)
+	// Update stores todo with the next version, if its version is still
+	// the stored one, otherwise it returns ErrVersionConflict
+	Update(todo *pb.TodoModel) (*pb.TodoModel, error)
+	Delete(id string) error
+	// SubtaskProgress counts the direct subtasks of each of parentIDs,
+	// parents without subtasks are left out
+	SubtaskProgress(parentIDs []string) (map[string]*pb.SubtaskProgress, error)
+}
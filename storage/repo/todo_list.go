@@ -0,0 +1,22 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// TodoListSortFields are the fields GetAll sorts lists by
+var TodoListSortFields = []string{"name", "created_at", "updated_at", "deleted_at"}
+
+// TodoListStorageI ...
+type TodoListStorageI interface {
+	Create(list *pb.TodoList) (*pb.TodoList, error)
+	Get(id string) (*pb.TodoList, error)
+	// GetInbox returns the inbox list of an owner, ErrNotFound when it has
+	// none yet
+	GetInbox(userID string) (*pb.TodoList, error)
+	// GetAll returns a page of the lists matching req and, with
+	// req.WithCount, how many match in total
+	GetAll(req *pb.ListTodoListsRequest) ([]*pb.TodoList, int64, error)
+	Update(list *pb.TodoList) (*pb.TodoList, error)
+	Delete(id string) error
+}
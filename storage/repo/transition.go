@@ -0,0 +1,12 @@
+package repo
+
+import (
+	pb "github.com/abdukhashimov/go_gin_example/genproto/todo_service"
+)
+
+// TransitionStorageI is the audit log of todo status changes
+type TransitionStorageI interface {
+	Create(*pb.TodoTransition) (*pb.TodoTransition, error)
+	// GetAll returns the transitions of a todo, oldest first
+	GetAll(todoID string) ([]*pb.TodoTransition, error)
+}
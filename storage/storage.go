@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/abdukhashimov/go_gin_example/pkg/logger"
+	"github.com/abdukhashimov/go_gin_example/storage/filestore"
+	"github.com/abdukhashimov/go_gin_example/storage/memory"
+	"github.com/abdukhashimov/go_gin_example/storage/postgres"
+	"github.com/abdukhashimov/go_gin_example/storage/repo"
+)
+
+// StorageI ...
+type StorageI interface {
+	Todo() repo.TodoStorageI
+	Transition() repo.TransitionStorageI
+	TodoList() repo.TodoListStorageI
+	Comment() repo.CommentStorageI
+	Attachment() repo.AttachmentStorageI
+	Revision() repo.RevisionStorageI
+	Reminder() repo.ReminderStorageI
+	FeedToken() repo.FeedTokenStorageI
+	Close() error
+}
+
+type storageMemory struct {
+	todoRepo       repo.TodoStorageI
+	transitionRepo repo.TransitionStorageI
+	todoListRepo   repo.TodoListStorageI
+	commentRepo    repo.CommentStorageI
+	attachmentRepo repo.AttachmentStorageI
+	revisionRepo   repo.RevisionStorageI
+	reminderRepo   repo.ReminderStorageI
+	feedTokenRepo  repo.FeedTokenStorageI
+}
+
+// NewStorageMemory returns storage which keeps everything in process memory
+func NewStorageMemory() StorageI {
+	return &storageMemory{
+		todoRepo:       memory.NewTodoRepo(),
+		transitionRepo: memory.NewTransitionRepo(),
+		todoListRepo:   memory.NewTodoListRepo(),
+		commentRepo:    memory.NewCommentRepo(),
+		attachmentRepo: memory.NewAttachmentRepo(),
+		revisionRepo:   memory.NewRevisionRepo(),
+		reminderRepo:   memory.NewReminderRepo(),
+		feedTokenRepo:  memory.NewFeedTokenRepo(),
+	}
+}
+
+func (s storageMemory) Todo() repo.TodoStorageI {
+	return s.todoRepo
+}
+
+func (s storageMemory) Transition() repo.TransitionStorageI {
+	return s.transitionRepo
+}
+
+func (s storageMemory) TodoList() repo.TodoListStorageI {
+	return s.todoListRepo
+}
+
+func (s storageMemory) Comment() repo.CommentStorageI {
+	return s.commentRepo
+}
+
+func (s storageMemory) Attachment() repo.AttachmentStorageI {
+	return s.attachmentRepo
+}
+
+func (s storageMemory) Revision() repo.RevisionStorageI {
+	return s.revisionRepo
+}
+
+func (s storageMemory) Reminder() repo.ReminderStorageI {
+	return s.reminderRepo
+}
+
+func (s storageMemory) FeedToken() repo.FeedTokenStorageI {
+	return s.feedTokenRepo
+}
+
+func (s storageMemory) Close() error {
+	return nil
+}
+
+type storageFile struct {
+	store          *filestore.Store
+	todoRepo       repo.TodoStorageI
+	transitionRepo repo.TransitionStorageI
+	todoListRepo   repo.TodoListStorageI
+	commentRepo    repo.CommentStorageI
+	attachmentRepo repo.AttachmentStorageI
+	revisionRepo   repo.RevisionStorageI
+	reminderRepo   repo.ReminderStorageI
+	feedTokenRepo  repo.FeedTokenStorageI
+}
+
+// NewStorageFile returns storage persisted to files in dir, see filestore
+func NewStorageFile(dir string, opts filestore.Options, log logger.Logger) (StorageI, error) {
+	store, err := filestore.Open(dir, opts, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storageFile{
+		store:          store,
+		todoRepo:       store.Todo(),
+		transitionRepo: store.Transition(),
+		todoListRepo:   store.TodoList(),
+		commentRepo:    store.Comment(),
+		attachmentRepo: store.Attachment(),
+		revisionRepo:   store.Revision(),
+		reminderRepo:   store.Reminder(),
+		feedTokenRepo:  store.FeedToken(),
+	}, nil
+}
+
+func (s storageFile) Todo() repo.TodoStorageI {
+	return s.todoRepo
+}
+
+func (s storageFile) Transition() repo.TransitionStorageI {
+	return s.transitionRepo
+}
+
+func (s storageFile) TodoList() repo.TodoListStorageI {
+	return s.todoListRepo
+}
+
+func (s storageFile) Comment() repo.CommentStorageI {
+	return s.commentRepo
+}
+
+func (s storageFile) Attachment() repo.AttachmentStorageI {
+	return s.attachmentRepo
+}
+
+func (s storageFile) Revision() repo.RevisionStorageI {
+	return s.revisionRepo
+}
+
+func (s storageFile) Reminder() repo.ReminderStorageI {
+	return s.reminderRepo
+}
+
+func (s storageFile) FeedToken() repo.FeedTokenStorageI {
+	return s.feedTokenRepo
+}
+
+func (s storageFile) Close() error {
+	return s.store.Close()
+}
+
+type storagePg struct {
+	db             *sql.DB
+	todoRepo       repo.TodoStorageI
+	transitionRepo repo.TransitionStorageI
+	todoListRepo   repo.TodoListStorageI
+	commentRepo    repo.CommentStorageI
+	attachmentRepo repo.AttachmentStorageI
+	revisionRepo   repo.RevisionStorageI
+	reminderRepo   repo.ReminderStorageI
+	feedTokenRepo  repo.FeedTokenStorageI
+}
+
+// NewStoragePg returns storage backed by a postgres database
+func NewStoragePg(db *sql.DB) StorageI {
+	return &storagePg{
+		db:             db,
+		todoRepo:       postgres.NewTodoRepo(db),
+		transitionRepo: postgres.NewTransitionRepo(db),
+		todoListRepo:   postgres.NewTodoListRepo(db),
+		commentRepo:    postgres.NewCommentRepo(db),
+		attachmentRepo: postgres.NewAttachmentRepo(db),
+		revisionRepo:   postgres.NewRevisionRepo(db),
+		reminderRepo:   postgres.NewReminderRepo(db),
+		feedTokenRepo:  postgres.NewFeedTokenRepo(db),
+	}
+}
+
+func (s storagePg) Todo() repo.TodoStorageI {
+	return s.todoRepo
+}
+
+func (s storagePg) Transition() repo.TransitionStorageI {
+	return s.transitionRepo
+}
+
+func (s storagePg) TodoList() repo.TodoListStorageI {
+	return s.todoListRepo
+}
+
+func (s storagePg) Comment() repo.CommentStorageI {
+	return s.commentRepo
+}
+
+func (s storagePg) Attachment() repo.AttachmentStorageI {
+	return s.attachmentRepo
+}
+
+func (s storagePg) Revision() repo.RevisionStorageI {
+	return s.revisionRepo
+}
+
+func (s storagePg) Reminder() repo.ReminderStorageI {
+	return s.reminderRepo
+}
+
+func (s storagePg) FeedToken() repo.FeedTokenStorageI {
+	return s.feedTokenRepo
+}
+
+func (s storagePg) Close() error {
+	return s.db.Close()
+}